@@ -0,0 +1,182 @@
+// Package address parses, validates and builds Monero addresses offline.
+package address
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/MarinX/monerorpc/base58"
+	"github.com/MarinX/monerorpc/crypto"
+	"github.com/MarinX/monerorpc/wallet"
+)
+
+// Network is a Monero network
+type Network int
+
+// Monero networks
+const (
+	Mainnet Network = iota
+	Testnet
+	Stagenet
+)
+
+// String returns the network name as reported by wallet-rpc's nettype
+func (n Network) String() string {
+	switch n {
+	case Mainnet:
+		return "mainnet"
+	case Testnet:
+		return "testnet"
+	case Stagenet:
+		return "stagenet"
+	}
+	return fmt.Sprintf("Network(%d)", int(n))
+}
+
+// Type is the kind of an address
+type Type int
+
+// Address types
+const (
+	Standard Type = iota
+	Integrated
+	Subaddress
+)
+
+// String returns the name of the address type
+func (t Type) String() string {
+	switch t {
+	case Standard:
+		return "standard"
+	case Integrated:
+		return "integrated"
+	case Subaddress:
+		return "subaddress"
+	}
+	return fmt.Sprintf("Type(%d)", int(t))
+}
+
+// prefixes holds the base58 prefix of every network and address type
+var prefixes = map[Network]map[Type]uint64{
+	Mainnet:  {Standard: 18, Integrated: 19, Subaddress: 42},
+	Testnet:  {Standard: 53, Integrated: 54, Subaddress: 63},
+	Stagenet: {Standard: 24, Integrated: 25, Subaddress: 36},
+}
+
+const (
+	keySize       = 32
+	paymentIDSize = 8
+	checksumSize  = 4
+)
+
+var (
+	// ErrInvalidAddress is returned when an address cannot be decoded
+	ErrInvalidAddress = errors.New("address: invalid address")
+	// ErrInvalidChecksum is returned when the checksum of an address does not match
+	ErrInvalidChecksum = errors.New("address: invalid checksum")
+	// ErrUnknownPrefix is returned when the address prefix matches no known network and type
+	ErrUnknownPrefix = errors.New("address: unknown prefix")
+	// ErrInvalidKey is returned when a public key of the address is not a valid curve point
+	ErrInvalidKey = errors.New("address: invalid public key")
+)
+
+// Address is a decoded Monero address
+type Address struct {
+	Network Network
+	Type    Type
+	// SpendKey is the public spend key
+	SpendKey [32]byte
+	// ViewKey is the public view key
+	ViewKey [32]byte
+	// PaymentID is set for integrated addresses
+	PaymentID [8]byte
+}
+
+// Parse decodes and validates a Monero address
+func Parse(s string) (*Address, error) {
+	data, err := base58.Decode(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidAddress, err)
+	}
+	prefix, n := binary.Uvarint(data)
+	if n <= 0 {
+		return nil, ErrInvalidAddress
+	}
+	a := &Address{}
+	if !a.setPrefix(prefix) {
+		return nil, fmt.Errorf("%w %d", ErrUnknownPrefix, prefix)
+	}
+
+	size := n + 2*keySize + checksumSize
+	if a.Type == Integrated {
+		size += paymentIDSize
+	}
+	if len(data) != size {
+		return nil, fmt.Errorf("%w: unexpected length %d", ErrInvalidAddress, len(data))
+	}
+	body, sum := data[:size-checksumSize], data[size-checksumSize:]
+	if hash := crypto.Keccak256(body); string(hash[:checksumSize]) != string(sum) {
+		return nil, ErrInvalidChecksum
+	}
+
+	copy(a.SpendKey[:], body[n:])
+	copy(a.ViewKey[:], body[n+keySize:])
+	if a.Type == Integrated {
+		copy(a.PaymentID[:], body[n+2*keySize:])
+	}
+	if !crypto.CheckKey(a.SpendKey) || !crypto.CheckKey(a.ViewKey) {
+		return nil, ErrInvalidKey
+	}
+	return a, nil
+}
+
+func (a *Address) setPrefix(prefix uint64) bool {
+	for network, types := range prefixes {
+		for t, p := range types {
+			if p == prefix {
+				a.Network, a.Type = network, t
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// String encodes the address
+func (a *Address) String() string {
+	buf := make([]byte, binary.MaxVarintLen64, binary.MaxVarintLen64+2*keySize+paymentIDSize+checksumSize)
+	buf = buf[:binary.PutUvarint(buf, prefixes[a.Network][a.Type])]
+	buf = append(buf, a.SpendKey[:]...)
+	buf = append(buf, a.ViewKey[:]...)
+	if a.Type == Integrated {
+		buf = append(buf, a.PaymentID[:]...)
+	}
+	hash := crypto.Keccak256(buf)
+	buf = append(buf, hash[:checksumSize]...)
+	return base58.Encode(buf)
+}
+
+// PaymentIDHex returns the hex encoded payment ID of an integrated address, or an empty string
+func (a *Address) PaymentIDHex() string {
+	if a.Type != Integrated {
+		return ""
+	}
+	return hex.EncodeToString(a.PaymentID[:])
+}
+
+// Validate checks address like wallet-rpc's validate_address does, for a wallet running on network.
+// OpenAlias addresses are not resolved and are reported as invalid.
+func Validate(req *wallet.ValidateAddressRequest, network Network) *wallet.ValidateAddressResponse {
+	a, err := Parse(req.Address)
+	if err != nil || (!req.AnyNetType && a.Network != network) {
+		return &wallet.ValidateAddressResponse{}
+	}
+	return &wallet.ValidateAddressResponse{
+		Valid:      true,
+		Integrated: a.Type == Integrated,
+		Subaddress: a.Type == Subaddress,
+		Nettype:    a.Network.String(),
+	}
+}
@@ -0,0 +1,136 @@
+package address
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/MarinX/monerorpc/wallet"
+	"github.com/matryer/is"
+)
+
+var vectors = []struct {
+	addr      string
+	network   Network
+	typ       Type
+	spend     string
+	view      string
+	paymentID string
+}{
+	{
+		addr:    "48ukkZtBSBRL8iva7k3p2sBVMLWTfNwsTbW1aVh5M84g21muDCssvCHTpoZCaSc6rq8M9QLZ3sQMrMn1bq2RD2anGnyHhtq",
+		network: Mainnet, typ: Standard,
+		spend: "c04ac8adc844e07263bf9a4dd337883eb55db89743c9aece4357381ae6c0b106",
+		view:  "0ef3c9e1146ed2a05f0eb4b25e41662bed41fa246251257c363a8ba95750cb8b",
+	},
+	{
+		addr:    "84nvgV2eTnG1vAKbg87MnbfjWrSY3eH3s2eykmggk549C8zdNk4PPD7iv7BPfPsnoH9NjXaRhjC19FY6PBmXZUtoG5SEiY7",
+		network: Mainnet, typ: Subaddress,
+		spend: "3dba53246e6981057ad2a9eff6d164e791cdef4578caee09e4b6ea03af774e42",
+		view:  "96b37bede10496fa98e0a7c58c49403211b225643621e456e7d2d4d8c13b6885",
+	},
+	{
+		addr:    "4JcRmNhg3SwL8iva7k3p2sBVMLWTfNwsTbW1aVh5M84g21muDCssvCHTpoZCaSc6rq8M9QLZ3sQMrMn1bq2RD2anQMXGeH3St98D3GPzmn",
+		network: Mainnet, typ: Integrated,
+		spend:     "c04ac8adc844e07263bf9a4dd337883eb55db89743c9aece4357381ae6c0b106",
+		view:      "0ef3c9e1146ed2a05f0eb4b25e41662bed41fa246251257c363a8ba95750cb8b",
+		paymentID: "9f9739432368cb6a",
+	},
+	{
+		addr:    "53zEYzu2hi3e97tdMTqTvSRAfFYXwxA7LBJEHLWvFnm699WgcsE8CJujENwNAQotKyY2u94vpbGEZTiwahuMcMfX3x6NFwY",
+		network: Stagenet, typ: Standard,
+		spend: "38e9908d33d034de0ba1281aa7afe3907b795cea14852b3d8fe276e8931cb130",
+		view:  "b4cdbf52851002fc7b098b99536df8b9885aa6cb8db24e9fc46103674dc9421a",
+	},
+	{
+		addr:    "74xhb5sXRsnDZv8RKFEv7LAMfUq5AmGEEB77SVvsUJf8bLvFMSEfc8YYyJHF6xNNnjAZQmgqZp76AjT8bD6qKkLZLeR42oi",
+		network: Stagenet, typ: Subaddress,
+		spend: "47a69d7aa0d0b14b22e2ff185b2e8b37effd771fee0c8b3c6a7ff9d53910ffcd",
+		view:  "536bab26fc7101bf23da6b6a39daa83925f5393df58a1bfdcb8edcc388726aae",
+	},
+	{
+		addr:    "5DguZoiXJyZe97tdMTqTvSRAfFYXwxA7LBJEHLWvFnm699WgcsE8CJujENwNAQotKyY2u94vpbGEZTiwahuMcMfX5MsmWgk84zrS4MPMnW",
+		network: Stagenet, typ: Integrated,
+		spend:     "38e9908d33d034de0ba1281aa7afe3907b795cea14852b3d8fe276e8931cb130",
+		view:      "b4cdbf52851002fc7b098b99536df8b9885aa6cb8db24e9fc46103674dc9421a",
+		paymentID: "10f5ebd54675efde",
+	},
+	{
+		addr:    "9zvkxwHbuHxX8B82zA8G9yBh6oKzbXS8viKexKeBCVBwNeP246aVAKSiC1DyVoETYZ11qDdmibSShX88HWGevRbp3G6hKyK",
+		network: Testnet, typ: Standard,
+		spend: "ccc9377cde8377b4190b13b1384c2c3feb697bc98a783ff70bbdf789d623e281",
+		view:  "6763ef0f8d3b41f641db860acbe5360015f00f6d0f05b6b417c0ad4708277b14",
+	},
+	{
+		addr:    "Be4mtTzNR3gGe7S9foMPdiLnv7jP2cfR3FB4YiNjNQFh8Q6WGortUdtXgwumP6xRu8MxdozhRjXMf4gCwwwE7NtRQ5jMkJd",
+		network: Testnet, typ: Subaddress,
+		spend: "9b5365b83a95d35d8127e5af7b9fa976539907f255ad9254bcd54705a7e6582c",
+		view:  "3b1aba22c7292fb779d907deb9fbd77d4e96286f22053615fa273513938b9acc",
+	},
+	{
+		addr:    "AAdRyk76WZUX8B82zA8G9yBh6oKzbXS8viKexKeBCVBwNeP246aVAKSiC1DyVoETYZ11qDdmibSShX88HWGevRbp4MEeHy9Xttg2tozpVS",
+		network: Testnet, typ: Integrated,
+		spend:     "ccc9377cde8377b4190b13b1384c2c3feb697bc98a783ff70bbdf789d623e281",
+		view:      "6763ef0f8d3b41f641db860acbe5360015f00f6d0f05b6b417c0ad4708277b14",
+		paymentID: "058bca5e06c79110",
+	},
+}
+
+func TestParse(t *testing.T) {
+	is := is.New(t)
+	for _, v := range vectors {
+		a, err := Parse(v.addr)
+		is.NoErr(err)
+		is.Equal(a.Network, v.network)
+		is.Equal(a.Type, v.typ)
+		is.Equal(hex.EncodeToString(a.SpendKey[:]), v.spend)
+		is.Equal(hex.EncodeToString(a.ViewKey[:]), v.view)
+		is.Equal(a.PaymentIDHex(), v.paymentID)
+		is.Equal(a.String(), v.addr)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	is := is.New(t)
+	valid := vectors[0].addr
+
+	_, err := Parse("")
+	is.True(errors.Is(err, ErrInvalidAddress))
+
+	_, err = Parse(valid[:len(valid)-1])
+	is.True(errors.Is(err, ErrInvalidAddress))
+
+	_, err = Parse(valid[:len(valid)-1] + "0")
+	is.True(errors.Is(err, ErrInvalidAddress))
+
+	// flip a character inside the keys
+	tampered := valid[:10] + "A" + valid[11:]
+	_, err = Parse(tampered)
+	is.True(errors.Is(err, ErrInvalidChecksum))
+
+	// a valid checksum over a key that is not on the curve
+	a, err := Parse(valid)
+	is.NoErr(err)
+	a.SpendKey = [32]byte{0: 2, 31: 0x7f}
+	for i := 1; i < 31; i++ {
+		a.SpendKey[i] = 0xff
+	}
+	_, err = Parse(a.String())
+	is.True(errors.Is(err, ErrInvalidKey))
+}
+
+func TestValidate(t *testing.T) {
+	is := is.New(t)
+
+	res := Validate(&wallet.ValidateAddressRequest{Address: vectors[1].addr}, Mainnet)
+	is.Equal(*res, wallet.ValidateAddressResponse{Valid: true, Subaddress: true, Nettype: "mainnet"})
+
+	res = Validate(&wallet.ValidateAddressRequest{Address: vectors[5].addr}, Mainnet)
+	is.Equal(*res, wallet.ValidateAddressResponse{})
+
+	res = Validate(&wallet.ValidateAddressRequest{Address: vectors[5].addr, AnyNetType: true}, Mainnet)
+	is.Equal(*res, wallet.ValidateAddressResponse{Valid: true, Integrated: true, Nettype: "stagenet"})
+
+	res = Validate(&wallet.ValidateAddressRequest{Address: "donate.getmonero.org", AllowOpenalias: true}, Mainnet)
+	is.Equal(res.Valid, false)
+}
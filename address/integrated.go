@@ -0,0 +1,100 @@
+package address
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/MarinX/monerorpc/wallet"
+)
+
+var (
+	// ErrNotIntegrated is returned when splitting an address that is not integrated
+	ErrNotIntegrated = errors.New("address: not an integrated address")
+	// ErrNotStandard is returned when integrating a subaddress or an integrated address
+	ErrNotStandard = errors.New("address: not a standard address")
+	// ErrInvalidPaymentID is returned for payment IDs that are not 16 hex characters
+	ErrInvalidPaymentID = errors.New("address: invalid payment id")
+)
+
+// ParsePaymentID decodes a 16 characters hex encoded payment ID
+func ParsePaymentID(s string) ([8]byte, error) {
+	var id [8]byte
+	if len(s) != 2*paymentIDSize {
+		return id, fmt.Errorf("%w %q", ErrInvalidPaymentID, s)
+	}
+	if _, err := hex.Decode(id[:], []byte(s)); err != nil {
+		return id, fmt.Errorf("%w %q", ErrInvalidPaymentID, s)
+	}
+	return id, nil
+}
+
+// Integrated returns the integrated address of a standard address and paymentID
+func (a *Address) Integrated(paymentID [8]byte) (*Address, error) {
+	if a.Type != Standard {
+		return nil, fmt.Errorf("%w: %s", ErrNotStandard, a.Type)
+	}
+	return &Address{
+		Network:   a.Network,
+		Type:      Integrated,
+		SpendKey:  a.SpendKey,
+		ViewKey:   a.ViewKey,
+		PaymentID: paymentID,
+	}, nil
+}
+
+// Standard returns the standard address an integrated address was made from
+func (a *Address) Standard() (*Address, error) {
+	if a.Type != Integrated {
+		return nil, fmt.Errorf("%w: %s", ErrNotIntegrated, a.Type)
+	}
+	return &Address{
+		Network:  a.Network,
+		Type:     Standard,
+		SpendKey: a.SpendKey,
+		ViewKey:  a.ViewKey,
+	}, nil
+}
+
+// MakeIntegrated builds an integrated address like wallet-rpc's make_integrated_address.
+// The standard address is required as there is no wallet to take the primary address from.
+// A random payment ID is used if none is given.
+func MakeIntegrated(req *wallet.MakeIntegratedAddressRequest) (*wallet.MakeIntegratedAddressResponse, error) {
+	a, err := Parse(req.StandardAddress)
+	if err != nil {
+		return nil, err
+	}
+	var id [8]byte
+	if req.PaymentID == "" {
+		if _, err = rand.Read(id[:]); err != nil {
+			return nil, err
+		}
+	} else if id, err = ParsePaymentID(req.PaymentID); err != nil {
+		return nil, err
+	}
+	integrated, err := a.Integrated(id)
+	if err != nil {
+		return nil, err
+	}
+	return &wallet.MakeIntegratedAddressResponse{
+		IntegratedAddress: integrated.String(),
+		PaymentID:         integrated.PaymentIDHex(),
+	}, nil
+}
+
+// SplitIntegrated splits an integrated address like wallet-rpc's split_integrated_address
+func SplitIntegrated(req *wallet.SplitIntegratedAddressRequest) (*wallet.SplitIntegratedAddressResponse, error) {
+	a, err := Parse(req.IntegratedAddress)
+	if err != nil {
+		return nil, err
+	}
+	standard, err := a.Standard()
+	if err != nil {
+		return nil, err
+	}
+	return &wallet.SplitIntegratedAddressResponse{
+		Payment:         a.PaymentIDHex(),
+		StandardAddress: standard.String(),
+	}, nil
+}
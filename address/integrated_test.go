@@ -0,0 +1,55 @@
+package address
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/MarinX/monerorpc/wallet"
+	"github.com/matryer/is"
+)
+
+func TestMakeIntegrated(t *testing.T) {
+	is := is.New(t)
+	// standard and integrated vectors of each network
+	for _, pair := range [][2]int{{0, 2}, {3, 5}, {6, 8}} {
+		standard, integrated := vectors[pair[0]], vectors[pair[1]]
+		res, err := MakeIntegrated(&wallet.MakeIntegratedAddressRequest{
+			StandardAddress: standard.addr,
+			PaymentID:       integrated.paymentID,
+		})
+		is.NoErr(err)
+		is.Equal(res.IntegratedAddress, integrated.addr)
+		is.Equal(res.PaymentID, integrated.paymentID)
+
+		split, err := SplitIntegrated(&wallet.SplitIntegratedAddressRequest{IntegratedAddress: res.IntegratedAddress})
+		is.NoErr(err)
+		is.Equal(*split, wallet.SplitIntegratedAddressResponse{Payment: integrated.paymentID, StandardAddress: standard.addr})
+	}
+}
+
+func TestMakeIntegratedRandomPaymentID(t *testing.T) {
+	is := is.New(t)
+	res, err := MakeIntegrated(&wallet.MakeIntegratedAddressRequest{StandardAddress: vectors[0].addr})
+	is.NoErr(err)
+	is.Equal(len(res.PaymentID), 16)
+
+	a, err := Parse(res.IntegratedAddress)
+	is.NoErr(err)
+	is.Equal(a.PaymentIDHex(), res.PaymentID)
+}
+
+func TestMakeIntegratedErrors(t *testing.T) {
+	is := is.New(t)
+
+	_, err := MakeIntegrated(&wallet.MakeIntegratedAddressRequest{StandardAddress: vectors[1].addr, PaymentID: "9f9739432368cb6a"})
+	is.True(errors.Is(err, ErrNotStandard))
+
+	_, err = MakeIntegrated(&wallet.MakeIntegratedAddressRequest{StandardAddress: vectors[0].addr, PaymentID: "9f97"})
+	is.True(errors.Is(err, ErrInvalidPaymentID))
+
+	_, err = MakeIntegrated(&wallet.MakeIntegratedAddressRequest{StandardAddress: vectors[0].addr, PaymentID: "zz9739432368cb6a"})
+	is.True(errors.Is(err, ErrInvalidPaymentID))
+
+	_, err = SplitIntegrated(&wallet.SplitIntegratedAddressRequest{IntegratedAddress: vectors[0].addr})
+	is.True(errors.Is(err, ErrNotIntegrated))
+}
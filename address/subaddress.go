@@ -0,0 +1,119 @@
+package address
+
+import (
+	"encoding/binary"
+
+	"filippo.io/edwards25519"
+	"github.com/MarinX/monerorpc/crypto"
+	"github.com/MarinX/monerorpc/wallet"
+)
+
+// subaddressPrefix is the domain separator of the subaddress secret, including the trailing NUL
+var subaddressPrefix = []byte("SubAddr\x00")
+
+// Generator derives the subaddresses of an account offline from its private view key and public spend key,
+// without exposing the spend key
+type Generator struct {
+	network    Network
+	viewSecret *edwards25519.Scalar
+	viewKey    [32]byte
+	spendKey   *edwards25519.Point
+}
+
+// NewGenerator creates a generator for the account with the private viewKey and public spendKey on network
+func NewGenerator(network Network, viewKey [32]byte, spendKey [32]byte) (*Generator, error) {
+	view, err := crypto.ParseScalar(viewKey)
+	if err != nil {
+		return nil, err
+	}
+	spend, err := crypto.ParsePoint(spendKey)
+	if err != nil {
+		return nil, err
+	}
+	return &Generator{
+		network:    network,
+		viewSecret: view,
+		viewKey:    crypto.Bytes(new(edwards25519.Point).ScalarBaseMult(view)),
+		spendKey:   spend,
+	}, nil
+}
+
+// Address returns the address at index, the primary address for (0, 0) and a subaddress otherwise.
+// The result matches the addresses reported by wallet-rpc's get_address and create_address.
+func (g *Generator) Address(major, minor uint32) *Address {
+	if major == 0 && minor == 0 {
+		return &Address{
+			Network:  g.network,
+			Type:     Standard,
+			SpendKey: crypto.Bytes(g.spendKey),
+			ViewKey:  g.viewKey,
+		}
+	}
+	spend := g.subaddressSpendKey(major, minor)
+	return &Address{
+		Network:  g.network,
+		Type:     Subaddress,
+		SpendKey: crypto.Bytes(spend),
+		ViewKey:  crypto.Bytes(new(edwards25519.Point).ScalarMult(g.viewSecret, spend)),
+	}
+}
+
+// SpendKey returns the public spend key of the address at index, D = B + Hs("SubAddr" || a || major || minor) * G
+func (g *Generator) SpendKey(major, minor uint32) [32]byte {
+	if major == 0 && minor == 0 {
+		return crypto.Bytes(g.spendKey)
+	}
+	return crypto.Bytes(g.subaddressSpendKey(major, minor))
+}
+
+// SecretKey returns the secret m of the address at index, with D = B + m * G. It is zero for the primary address.
+// The private spend key of a subaddress is b + m.
+func (g *Generator) SecretKey(major, minor uint32) [32]byte {
+	if major == 0 && minor == 0 {
+		return [32]byte{}
+	}
+	return crypto.Bytes(g.subaddressSecret(major, minor))
+}
+
+func (g *Generator) subaddressSecret(major, minor uint32) *edwards25519.Scalar {
+	return crypto.HashToScalar(subaddressPrefix, g.viewSecret.Bytes(), indexBytes(major, minor))
+}
+
+func (g *Generator) subaddressSpendKey(major, minor uint32) *edwards25519.Point {
+	d := new(edwards25519.Point).ScalarBaseMult(g.subaddressSecret(major, minor))
+	return d.Add(d, g.spendKey)
+}
+
+func indexBytes(major, minor uint32) []byte {
+	b := make([]byte, 8)
+	binary.LittleEndian.PutUint32(b, major)
+	binary.LittleEndian.PutUint32(b[4:], minor)
+	return b
+}
+
+// LookupTable maps public spend keys to their subaddress index, as used by a scanner to
+// find which subaddress an output belongs to
+type LookupTable map[[32]byte]wallet.Index
+
+// LookupTable returns the table of accounts 0 to majors-1, each with subaddresses 0 to minors-1,
+// like the lookahead of a wallet
+func (g *Generator) LookupTable(majors, minors uint32) LookupTable {
+	t := make(LookupTable, int(majors)*int(minors))
+	for major := uint32(0); major < majors; major++ {
+		for minor := uint32(0); minor < minors; minor++ {
+			t.Add(g, major, minor)
+		}
+	}
+	return t
+}
+
+// Add inserts the subaddress of g at index into the table
+func (t LookupTable) Add(g *Generator, major, minor uint32) {
+	t[g.SpendKey(major, minor)] = wallet.Index{Major: major, Minor: minor}
+}
+
+// Lookup returns the index of the subaddress with the public spendKey, like wallet-rpc's get_address_index
+func (t LookupTable) Lookup(spendKey [32]byte) (wallet.Index, bool) {
+	i, ok := t[spendKey]
+	return i, ok
+}
@@ -0,0 +1,77 @@
+package address
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/MarinX/monerorpc/wallet"
+	"github.com/matryer/is"
+)
+
+func key(t *testing.T, s string) [32]byte {
+	var k [32]byte
+	b, err := hex.DecodeString(s)
+	if err != nil || len(b) != 32 {
+		t.Fatalf("invalid key %s", s)
+	}
+	copy(k[:], b)
+	return k
+}
+
+func TestGenerator(t *testing.T) {
+	is := is.New(t)
+	tests := []struct {
+		network      Network
+		view, spend  string
+		major, minor uint32
+		expected     string
+	}{
+		{Mainnet, "ac413c16b815899b69393d72086fa86d31e8e352895606180c4c8fadd707450a", "c04ac8adc844e07263bf9a4dd337883eb55db89743c9aece4357381ae6c0b106", 0, 0, vectors[0].addr},
+		{Mainnet, "ac413c16b815899b69393d72086fa86d31e8e352895606180c4c8fadd707450a", "c04ac8adc844e07263bf9a4dd337883eb55db89743c9aece4357381ae6c0b106", 1, 0, "87BTvS4grAXSrwgzonu3N8Tm7N6W29UGAcd3GLumriVYiCJrUbsyPGWQoA92FZ6MgKWStiZjhS6o9Eeh6yinHH5NAgE9CUe"},
+		{Mainnet, "ac413c16b815899b69393d72086fa86d31e8e352895606180c4c8fadd707450a", "c04ac8adc844e07263bf9a4dd337883eb55db89743c9aece4357381ae6c0b106", 2, 3, "87aJx3x1cd56PS9JZdY4rzFSWcjvh274ERV1LYmFzzTwYFbfzWLRfgxTm8zBCPxPmoCpEnmHgDAn3dNAi1zRchNv8zdeQ1i"},
+		{Stagenet, "8aa763d1c8d9da4ca75cb6ca22a021b5cca376c1367be8d62bcc9cdf4b926009", "38e9908d33d034de0ba1281aa7afe3907b795cea14852b3d8fe276e8931cb130", 0, 0, vectors[3].addr},
+		{Stagenet, "8aa763d1c8d9da4ca75cb6ca22a021b5cca376c1367be8d62bcc9cdf4b926009", "38e9908d33d034de0ba1281aa7afe3907b795cea14852b3d8fe276e8931cb130", 1, 0, "72c2F4L6XMu28Wf4e5yiVfKJcb4uDzvM9DxSAydF9o766RUiVqXawkhUcz7y59EBRrDafZB8DezLbLSrtb5xPL7s6PZ2zoj"},
+		{Stagenet, "8aa763d1c8d9da4ca75cb6ca22a021b5cca376c1367be8d62bcc9cdf4b926009", "38e9908d33d034de0ba1281aa7afe3907b795cea14852b3d8fe276e8931cb130", 3, 5, "74wdCFDsraBfreEwnfyyexK5d5ZkU48bK6Xd1UGjFTvNYes7gQJY47WUdA23hny1ynC2REEM9Rf1DGNuuwbDrsuAEHrwVmv"},
+	}
+	for _, tt := range tests {
+		g, err := NewGenerator(tt.network, key(t, tt.view), key(t, tt.spend))
+		is.NoErr(err)
+		a := g.Address(tt.major, tt.minor)
+		is.Equal(a.String(), tt.expected)
+		is.Equal(g.SpendKey(tt.major, tt.minor), a.SpendKey)
+	}
+}
+
+func TestLookupTable(t *testing.T) {
+	is := is.New(t)
+	g, err := NewGenerator(Mainnet,
+		key(t, "ac413c16b815899b69393d72086fa86d31e8e352895606180c4c8fadd707450a"),
+		key(t, "c04ac8adc844e07263bf9a4dd337883eb55db89743c9aece4357381ae6c0b106"))
+	is.NoErr(err)
+
+	table := g.LookupTable(3, 5)
+	is.Equal(len(table), 15)
+
+	a, err := Parse("87aJx3x1cd56PS9JZdY4rzFSWcjvh274ERV1LYmFzzTwYFbfzWLRfgxTm8zBCPxPmoCpEnmHgDAn3dNAi1zRchNv8zdeQ1i")
+	is.NoErr(err)
+	index, ok := table.Lookup(a.SpendKey)
+	is.True(ok)
+	is.Equal(index, wallet.Index{Major: 2, Minor: 3})
+
+	_, ok = table.Lookup(g.SpendKey(3, 0))
+	is.True(!ok)
+	table.Add(g, 3, 0)
+	index, ok = table.Lookup(g.SpendKey(3, 0))
+	is.True(ok)
+	is.Equal(index, wallet.Index{Major: 3})
+}
+
+func TestNewGeneratorInvalidKeys(t *testing.T) {
+	is := is.New(t)
+	var notReduced [32]byte
+	for i := range notReduced {
+		notReduced[i] = 0xff
+	}
+	_, err := NewGenerator(Mainnet, notReduced, key(t, vectors[0].spend))
+	is.True(err != nil)
+}
@@ -0,0 +1,180 @@
+package monerorpc
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+
+	"github.com/MarinX/monerorpc/wallet"
+)
+
+// Amount is a quantity of XMR in atomic units (piconero).
+// It is encoded in JSON as a plain number, like the uint64 amount fields of the wallet models.
+type Amount uint64
+
+// Common units
+const (
+	Piconero  Amount = 1
+	Nanonero  Amount = 1000 * Piconero
+	Micronero Amount = 1000 * Nanonero
+	Millinero Amount = 1000 * Micronero
+	XMR       Amount = 1000 * Millinero
+)
+
+// AmountDecimals is the number of decimal places of one XMR
+const AmountDecimals = 12
+
+var (
+	// ErrAmountOverflow is returned when a result does not fit into an Amount
+	ErrAmountOverflow = errors.New("amount overflow")
+	// ErrAmountUnderflow is returned when subtracting a larger amount
+	ErrAmountUnderflow = errors.New("amount underflow")
+	// ErrInvalidAmount is returned when parsing a malformed amount
+	ErrInvalidAmount = errors.New("invalid amount")
+)
+
+// ParseAmount parses a decimal XMR amount such as "1.5" or "0.000000000003" without rounding.
+// More than 12 significant decimal places are rejected.
+func ParseAmount(s string) (Amount, error) {
+	whole, frac, _ := strings.Cut(s, ".")
+	if whole == "" && frac == "" {
+		return 0, fmt.Errorf("%w %q", ErrInvalidAmount, s)
+	}
+	if !isDigits(whole) || !isDigits(frac) {
+		return 0, fmt.Errorf("%w %q", ErrInvalidAmount, s)
+	}
+	trimmed := strings.TrimRight(frac, "0")
+	if len(trimmed) > AmountDecimals {
+		return 0, fmt.Errorf("%w %q: more than %d decimal places", ErrInvalidAmount, s, AmountDecimals)
+	}
+
+	var w uint64
+	if whole != "" {
+		var err error
+		w, err = strconv.ParseUint(whole, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("%w %q", ErrAmountOverflow, s)
+		}
+	}
+	var f uint64
+	if trimmed != "" {
+		// cannot fail, at most 12 digits
+		f, _ = strconv.ParseUint(trimmed+strings.Repeat("0", AmountDecimals-len(trimmed)), 10, 64)
+	}
+	a, err := Amount(w).Mul(uint64(XMR))
+	if err != nil {
+		return 0, fmt.Errorf("%w %q", ErrAmountOverflow, s)
+	}
+	return a.Add(Amount(f))
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// MustParseAmount is like ParseAmount but panics on error, for constants in code
+func MustParseAmount(s string) Amount {
+	a, err := ParseAmount(s)
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+// String formats the amount in XMR without trailing zeros, e.g. "1.5" or "0.000000000003"
+func (a Amount) String() string {
+	whole := uint64(a / XMR)
+	frac := uint64(a % XMR)
+	if frac == 0 {
+		return strconv.FormatUint(whole, 10)
+	}
+	f := strings.TrimRight(fmt.Sprintf("%012d", frac), "0")
+	return strconv.FormatUint(whole, 10) + "." + f
+}
+
+// Uint64 returns the amount in atomic units
+func (a Amount) Uint64() uint64 {
+	return uint64(a)
+}
+
+// Add returns a+b or ErrAmountOverflow
+func (a Amount) Add(b Amount) (Amount, error) {
+	sum, carry := bits.Add64(uint64(a), uint64(b), 0)
+	if carry != 0 {
+		return 0, ErrAmountOverflow
+	}
+	return Amount(sum), nil
+}
+
+// Sub returns a-b or ErrAmountUnderflow
+func (a Amount) Sub(b Amount) (Amount, error) {
+	diff, borrow := bits.Sub64(uint64(a), uint64(b), 0)
+	if borrow != 0 {
+		return 0, ErrAmountUnderflow
+	}
+	return Amount(diff), nil
+}
+
+// Mul returns a*n or ErrAmountOverflow
+func (a Amount) Mul(n uint64) (Amount, error) {
+	hi, lo := bits.Mul64(uint64(a), n)
+	if hi != 0 {
+		return 0, ErrAmountOverflow
+	}
+	return Amount(lo), nil
+}
+
+// MarshalJSON encodes the amount as a number of atomic units
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.FormatUint(uint64(a), 10)), nil
+}
+
+// UnmarshalJSON decodes a number of atomic units
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	n, err := strconv.ParseUint(string(data), 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w %s: expected atomic units", ErrInvalidAmount, data)
+	}
+	*a = Amount(n)
+	return nil
+}
+
+// SumAmounts adds up amounts or returns ErrAmountOverflow
+func SumAmounts(amounts ...Amount) (Amount, error) {
+	var total Amount
+	for _, amount := range amounts {
+		var err error
+		if total, err = total.Add(amount); err != nil {
+			return 0, err
+		}
+	}
+	return total, nil
+}
+
+// SumDestinations adds up the amounts of destinations, e.g. to check a TransferRequest against the balance
+func SumDestinations(destinations []wallet.Destination) (Amount, error) {
+	amounts := make([]Amount, len(destinations))
+	for i, d := range destinations {
+		amounts[i] = Amount(d.Amount)
+	}
+	return SumAmounts(amounts...)
+}
+
+// SumTransfers adds up the amounts of transfers, e.g. the In list of a GetTransfersResponse
+func SumTransfers(transfers []*wallet.Transfer) (Amount, error) {
+	amounts := make([]Amount, len(transfers))
+	for i, t := range transfers {
+		amounts[i] = Amount(t.Amount)
+	}
+	return SumAmounts(amounts...)
+}
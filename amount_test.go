@@ -0,0 +1,125 @@
+package monerorpc
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/MarinX/monerorpc/wallet"
+	"github.com/matryer/is"
+)
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Amount
+	}{
+		{"0", 0},
+		{"1", XMR},
+		{"1.5", 1500000000000},
+		{".5", 500000000000},
+		{"2.", 2 * XMR},
+		{"0.000000000003", 3},
+		{"0.000000000001000", 1},
+		{"18446744.073709551615", math.MaxUint64},
+		{"123456.789012345678", 123456789012345678},
+	}
+	for _, test := range tests {
+		a, err := ParseAmount(test.in)
+		is.New(t).NoErr(err)
+		is.New(t).Equal(a, test.want)
+	}
+
+	for _, in := range []string{"", ".", "-1", "+1", "1e3", "1.2.3", " 1", "abc"} {
+		_, err := ParseAmount(in)
+		is.New(t).True(errors.Is(err, ErrInvalidAmount))
+	}
+	_, err := ParseAmount("0.0000000000001")
+	is.New(t).True(errors.Is(err, ErrInvalidAmount))
+	_, err = ParseAmount("18446744.073709551616")
+	is.New(t).True(errors.Is(err, ErrAmountOverflow))
+	_, err = ParseAmount("99999999999999999999")
+	is.New(t).True(errors.Is(err, ErrAmountOverflow))
+}
+
+func TestAmountString(t *testing.T) {
+	is := is.New(t)
+
+	is.Equal(Amount(0).String(), "0")
+	is.Equal(Amount(3).String(), "0.000000000003")
+	is.Equal((XMR + 5*Millinero).String(), "1.005")
+	is.Equal(Amount(math.MaxUint64).String(), "18446744.073709551615")
+	is.Equal(MustParseAmount("42.42").String(), "42.42")
+}
+
+func TestAmountArithmetic(t *testing.T) {
+	is := is.New(t)
+
+	a, err := XMR.Add(Piconero)
+	is.NoErr(err)
+	is.Equal(a, Amount(1000000000001))
+	_, err = Amount(math.MaxUint64).Add(1)
+	is.True(errors.Is(err, ErrAmountOverflow))
+
+	a, err = XMR.Sub(Millinero)
+	is.NoErr(err)
+	is.Equal(a.String(), "0.999")
+	_, err = Millinero.Sub(XMR)
+	is.True(errors.Is(err, ErrAmountUnderflow))
+
+	a, err = Millinero.Mul(3)
+	is.NoErr(err)
+	is.Equal(a.String(), "0.003")
+	_, err = XMR.Mul(math.MaxUint64)
+	is.True(errors.Is(err, ErrAmountOverflow))
+}
+
+func TestAmountJSON(t *testing.T) {
+	is := is.New(t)
+
+	buff, err := json.Marshal(wallet.Destination{Amount: 1500000000000, Address: "addr"})
+	is.NoErr(err)
+
+	var dest struct {
+		Amount  Amount `json:"amount"`
+		Address string `json:"address"`
+	}
+	is.NoErr(json.Unmarshal(buff, &dest))
+	is.Equal(dest.Amount, MustParseAmount("1.5"))
+
+	out, err := json.Marshal(dest)
+	is.NoErr(err)
+	is.Equal(string(out), string(buff))
+
+	is.True(json.Unmarshal([]byte(`{"amount": 1.5}`), &dest) != nil)
+	is.True(json.Unmarshal([]byte(`{"amount": -1}`), &dest) != nil)
+}
+
+func TestSumAmounts(t *testing.T) {
+	is := is.New(t)
+
+	total, err := SumDestinations([]wallet.Destination{{Amount: 1}, {Amount: uint64(XMR)}})
+	is.NoErr(err)
+	is.Equal(total, XMR+1)
+
+	res := wallet.GetTransfersResponse{In: []*wallet.Transfer{{Amount: 2}, {Amount: 3}}}
+	total, err = SumTransfers(res.In)
+	is.NoErr(err)
+	is.Equal(total, Amount(5))
+
+	_, err = SumAmounts(math.MaxUint64, 1)
+	is.True(errors.Is(err, ErrAmountOverflow))
+}
+
+func TestStringToXMR(t *testing.T) {
+	is := is.New(t)
+
+	xmr, err := StringToXMR("0.000000000003")
+	is.NoErr(err)
+	is.Equal(xmr, uint64(3))
+
+	xmr, err = StringToXMR("9007.199254740993")
+	is.NoErr(err)
+	is.Equal(xmr, uint64(9007199254740993))
+}
@@ -0,0 +1,115 @@
+// Package base58 implements Monero's base58 encoding, which encodes data in blocks of
+// 8 bytes to 11 characters so the length of the output only depends on the input length.
+package base58
+
+import (
+	"errors"
+	"math/big"
+)
+
+const alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+const (
+	fullBlockSize        = 8
+	fullEncodedBlockSize = 11
+)
+
+// encodedBlockSizes maps the size of a block to the size of its encoding
+var encodedBlockSizes = [fullBlockSize + 1]int{0, 2, 3, 5, 6, 7, 9, 10, 11}
+
+// ErrInvalid is returned when decoding malformed input
+var ErrInvalid = errors.New("base58: invalid input")
+
+var decodeMap [256]int8
+
+func init() {
+	for i := range decodeMap {
+		decodeMap[i] = -1
+	}
+	for i := 0; i < len(alphabet); i++ {
+		decodeMap[alphabet[i]] = int8(i)
+	}
+}
+
+// Encode returns the base58 encoding of data
+func Encode(data []byte) string {
+	full := len(data) / fullBlockSize
+	rest := len(data) % fullBlockSize
+	out := make([]byte, 0, full*fullEncodedBlockSize+encodedBlockSizes[rest])
+	for i := 0; i < full; i++ {
+		out = encodeBlock(out, data[i*fullBlockSize:(i+1)*fullBlockSize])
+	}
+	if rest > 0 {
+		out = encodeBlock(out, data[full*fullBlockSize:])
+	}
+	return string(out)
+}
+
+func encodeBlock(out []byte, block []byte) []byte {
+	var n uint64
+	for _, b := range block {
+		n = n<<8 | uint64(b)
+	}
+	size := encodedBlockSizes[len(block)]
+	enc := make([]byte, size)
+	for i := size - 1; i >= 0; i-- {
+		enc[i] = alphabet[n%58]
+		n /= 58
+	}
+	return append(out, enc...)
+}
+
+// Decode returns the bytes represented by the base58 string s
+func Decode(s string) ([]byte, error) {
+	full := len(s) / fullEncodedBlockSize
+	rest := len(s) % fullEncodedBlockSize
+	restSize := -1
+	for size, encoded := range encodedBlockSizes {
+		if encoded == rest {
+			restSize = size
+			break
+		}
+	}
+	if restSize < 0 {
+		return nil, ErrInvalid
+	}
+	out := make([]byte, 0, full*fullBlockSize+restSize)
+	var err error
+	for i := 0; i < full; i++ {
+		if out, err = decodeBlock(out, s[i*fullEncodedBlockSize:(i+1)*fullEncodedBlockSize], fullBlockSize); err != nil {
+			return nil, err
+		}
+	}
+	if rest > 0 {
+		if out, err = decodeBlock(out, s[full*fullEncodedBlockSize:], restSize); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+var maxBlock = new(big.Int).Lsh(big.NewInt(1), 64)
+
+func decodeBlock(out []byte, block string, size int) ([]byte, error) {
+	// an 11 character block may exceed 64 bits, so accumulate in a big.Int
+	n := new(big.Int)
+	base := big.NewInt(58)
+	for i := 0; i < len(block); i++ {
+		d := decodeMap[block[i]]
+		if d < 0 {
+			return nil, ErrInvalid
+		}
+		n.Mul(n, base)
+		n.Add(n, big.NewInt(int64(d)))
+	}
+	if n.Cmp(maxBlock) >= 0 || (size < fullBlockSize && n.BitLen() > size*8) {
+		return nil, ErrInvalid
+	}
+	v := n.Uint64()
+	dec := make([]byte, size)
+	for i := size - 1; i >= 0; i-- {
+		dec[i] = byte(v)
+		v >>= 8
+	}
+	return append(out, dec...), nil
+}
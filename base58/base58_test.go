@@ -0,0 +1,39 @@
+package base58
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	tests := []struct {
+		hex string
+		enc string
+	}{
+		{"", ""},
+		{"00", "11"},
+		{"ff", "5Q"},
+		{"0000000000000000", "11111111111"},
+		{"ffffffffffffffff", "jpXCZedGfVQ"},
+		{"12c04ac8adc844e07263bf9a4dd337883eb55db89743c9aece4357381ae6c0b1060ef3c9e1146ed2a05f0eb4b25e41662bed41fa246251257c363a8ba95750cb8bfa84423e", "48ukkZtBSBRL8iva7k3p2sBVMLWTfNwsTbW1aVh5M84g21muDCssvCHTpoZCaSc6rq8M9QLZ3sQMrMn1bq2RD2anGnyHhtq"},
+	}
+	for _, test := range tests {
+		data, _ := hex.DecodeString(test.hex)
+		is.New(t).Equal(Encode(data), test.enc)
+
+		dec, err := Decode(test.enc)
+		is.New(t).NoErr(err)
+		is.New(t).True(bytes.Equal(dec, data))
+	}
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	for _, in := range []string{"1", "0OIl1", "11111111111" + "1111", "zzzzzzzzzzz", "zz"} {
+		_, err := Decode(in)
+		is.New(t).True(errors.Is(err, ErrInvalid))
+	}
+}
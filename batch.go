@@ -0,0 +1,238 @@
+package monerorpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/gorilla/rpc/v2/json2"
+)
+
+// BatchCall is a single json rpc call sent as part of a batch
+type BatchCall struct {
+	// Method is the json rpc method to call
+	Method string
+	// Params is the request model, may be nil
+	Params interface{}
+	// Result is a pointer to the response model, may be nil
+	Result interface{}
+	// Error is set to the error of this call once the batch is done
+	Error error
+}
+
+type batchRequest struct {
+	Version string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params,omitempty"`
+	ID      uint64      `json:"id"`
+}
+
+type batchResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *json2.Error    `json:"error"`
+	ID     *uint64         `json:"id"`
+}
+
+// Batch sends all calls in a single http request, see MoneroRPC.Do for which endpoint is used
+func (c *MoneroRPC) Batch(calls []*BatchCall) error {
+	return c.BatchContext(context.Background(), calls)
+}
+
+// BatchContext is like Batch but the request is bound to ctx
+func (c *MoneroRPC) BatchContext(ctx context.Context, calls []*BatchCall) error {
+	if c.wallet.uri == "" {
+		return c.daemon.BatchContext(ctx, calls)
+	}
+	return c.wallet.BatchContext(ctx, calls)
+}
+
+// Batch sends all calls to this endpoint in a single http request
+func (e *Endpoint) Batch(calls []*BatchCall) error {
+	return e.BatchContext(context.Background(), calls)
+}
+
+// BatchContext sends all calls as one json rpc batch and stores each reply into the Result
+// and Error of its call. The returned error is only set when the batch as a whole failed,
+// e.g. on transport or auth failures.
+//
+// With a retry policy, calls answered with a BUSY status are sent again in a new batch, and
+// failed batches are repeated when every call may be retried.
+//
+// Servers which reject batches (monerod and monero-wallet-rpc answer them with a single json rpc
+// error) are remembered and the calls are made one after the other instead.
+func (e *Endpoint) BatchContext(ctx context.Context, calls []*BatchCall) error {
+	if len(calls) == 0 {
+		return nil
+	}
+	if atomic.LoadInt32(&e.noBatch) == 0 {
+		var rejected bool
+		err := e.intercept(ctx, "batch", calls, nil, func(ctx context.Context, method string, req interface{}, res interface{}) error {
+			batch, ok := req.([]*BatchCall)
+			if !ok {
+				return &Error{Method: method, Err: fmt.Errorf("batch request must be []*BatchCall, got %T", req)}
+			}
+			calls = batch
+			var err error
+			rejected, err = e.retryBatch(ctx, batch)
+			return err
+		})
+		if err != nil || !rejected {
+			return err
+		}
+		atomic.StoreInt32(&e.noBatch, 1)
+	}
+	for i, call := range calls {
+		if err := ctx.Err(); err != nil {
+			// the calls left were not sent
+			for _, call := range calls[i:] {
+				call.Error = &Error{Method: call.Method, Err: err, transport: true}
+			}
+			return calls[i].Error
+		}
+		call.Error = e.DoContext(ctx, call.Method, call.Params, call.Result)
+	}
+	return nil
+}
+
+// retryBatch sends calls with the retry policy of the endpoint and reports whether the server rejected the batch
+func (e *Endpoint) retryBatch(ctx context.Context, calls []*BatchCall) (bool, error) {
+	var rejected bool
+	pending := calls
+	err := e.retry.doFunc(ctx, func(err error) bool {
+		for _, call := range pending {
+			if !e.retry.retryable(call.Method, err) {
+				return false
+			}
+		}
+		return true
+	}, func() error {
+		var err error
+		rejected, err = e.batch(ctx, pending)
+		if err != nil || rejected {
+			return err
+		}
+		var busy []*BatchCall
+		for _, call := range pending {
+			if call.Error == errBusyResult {
+				busy = append(busy, call)
+			}
+		}
+		if len(busy) > 0 {
+			pending = busy
+			return errBusyResult
+		}
+		return nil
+	})
+	for _, call := range calls {
+		call.Error = finalError(call.Error)
+	}
+	return rejected, err
+}
+
+// batch posts the calls as a json rpc batch and reports whether the server rejected it
+func (e *Endpoint) batch(ctx context.Context, calls []*BatchCall) (bool, error) {
+	reqs := make([]batchRequest, len(calls))
+	for i, call := range calls {
+		reqs[i] = batchRequest{Version: "2.0", Method: call.Method, Params: call.Params, ID: uint64(i)}
+	}
+	buff, err := json.Marshal(reqs)
+	if err != nil {
+		return false, &Error{Method: "batch", Err: fmt.Errorf("error creating encoded request %v", err)}
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", e.uri, bytes.NewReader(buff))
+	if err != nil {
+		return false, &Error{Method: "batch", Err: fmt.Errorf("error creating http request %v", err)}
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	setHeaders(ctx, httpReq)
+
+	httpResp, err := e.client.Do(httpReq)
+	if err != nil {
+		return false, &Error{Method: "batch", Err: err, transport: true}
+	}
+	defer httpResp.Body.Close()
+	if httpResp.StatusCode == http.StatusUnauthorized {
+		return false, &Error{Method: "batch", HTTPStatus: httpResp.StatusCode, Err: ErrUnauthorized}
+	}
+	body, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return false, &Error{Method: "batch", HTTPStatus: httpResp.StatusCode, Err: err, transport: true}
+	}
+
+	if batchRejected(body) {
+		return true, nil
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		return false, &Error{Method: "batch", HTTPStatus: httpResp.StatusCode}
+	}
+	var replies []batchResponse
+	if err = json.Unmarshal(body, &replies); err != nil {
+		return false, &Error{Method: "batch", HTTPStatus: httpResp.StatusCode, Err: fmt.Errorf("error decoding response %v", err)}
+	}
+
+	seen := make([]bool, len(calls))
+	for _, reply := range replies {
+		if reply.ID == nil || *reply.ID >= uint64(len(calls)) {
+			continue
+		}
+		call := calls[*reply.ID]
+		seen[*reply.ID] = true
+		call.Error = e.decodeBatchReply(call, reply, httpResp.StatusCode)
+		if call.Error == nil && e.retry != nil && batchResultBusy(reply.Result) {
+			call.Error = errBusyResult
+		}
+	}
+	for i, call := range calls {
+		if !seen[i] {
+			call.Error = &Error{Method: call.Method, HTTPStatus: httpResp.StatusCode, Err: fmt.Errorf("missing reply in batch")}
+		}
+	}
+	return false, nil
+}
+
+// batchRejected reports whether body is a single json rpc error, the reply of servers which do not support batches
+func batchRejected(body []byte) bool {
+	var reply struct {
+		Version string       `json:"jsonrpc"`
+		Error   *json2.Error `json:"error"`
+	}
+	return json.Unmarshal(body, &reply) == nil && reply.Version == "2.0" && reply.Error != nil
+}
+
+// batchResultBusy reports whether the result of a batch reply has a BUSY status
+func batchResultBusy(result json.RawMessage) bool {
+	var reply struct {
+		Status string `json:"status"`
+	}
+	return json.Unmarshal(result, &reply) == nil && reply.Status == "BUSY"
+}
+
+// decodeBatchReply stores a single reply of a batch into call.Result
+func (e *Endpoint) decodeBatchReply(call *BatchCall, reply batchResponse, status int) error {
+	if reply.Error != nil {
+		// same workaround as in DoContext for empty errors with code 0
+		if reply.Error.Code == 0 && reply.Error.Message == "" && reply.Error.Data == nil {
+			return nil
+		}
+		return &Error{
+			Code:       int(reply.Error.Code),
+			Message:    reply.Error.Message,
+			Data:       reply.Error.Data,
+			Method:     call.Method,
+			HTTPStatus: status,
+			Daemon:     e.daemonError(call.Method),
+		}
+	}
+	if call.Result == nil || len(reply.Result) == 0 || string(reply.Result) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(reply.Result, call.Result); err != nil {
+		return &Error{Method: call.Method, HTTPStatus: status, Err: fmt.Errorf("error decoding response %v", err)}
+	}
+	return nil
+}
@@ -0,0 +1,240 @@
+package monerorpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+type rpcRequest struct {
+	Method string `json:"method"`
+	Params struct {
+		Height uint64 `json:"height"`
+	} `json:"params"`
+	ID json.RawMessage `json:"id"`
+}
+
+// reply answers on_get_block_hash style requests, failing for heights above 100
+func (r rpcRequest) reply() string {
+	if r.Params.Height > 100 {
+		return fmt.Sprintf(`{"id": %s, "jsonrpc": "2.0", "error": {"code": -2, "message": "Too big height"}}`, r.ID)
+	}
+	return fmt.Sprintf(`{"id": %s, "jsonrpc": "2.0", "result": {"hash": "hash%d"}}`, r.ID, r.Params.Height)
+}
+
+// setupBatchServer serves json rpc requests, answering batches only when batches is set
+func setupBatchServer(t *testing.T, batches bool, posts *int) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		*posts++
+		buff, _ := io.ReadAll(req.Body)
+		var reqs []rpcRequest
+		if err := json.Unmarshal(buff, &reqs); err == nil {
+			if !batches {
+				rw.Write([]byte(`{"id": 0, "jsonrpc": "2.0", "error": {"code": -32600, "message": "Invalid Request"}}`))
+				return
+			}
+			rw.Write([]byte("["))
+			// reply in reverse order to check demultiplexing by id
+			for i := len(reqs) - 1; i >= 0; i-- {
+				rw.Write([]byte(reqs[i].reply()))
+				if i > 0 {
+					rw.Write([]byte(","))
+				}
+			}
+			rw.Write([]byte("]"))
+			return
+		}
+		var r rpcRequest
+		if err := json.Unmarshal(buff, &r); err != nil {
+			t.Fatal(err)
+		}
+		rw.Write([]byte(r.reply()))
+	}))
+	return server
+}
+
+type hashResult struct {
+	Hash string `json:"hash"`
+}
+
+func batchCalls(heights ...uint64) []*BatchCall {
+	calls := make([]*BatchCall, len(heights))
+	for i, h := range heights {
+		calls[i] = &BatchCall{
+			Method: "get_block_header_by_height",
+			Params: map[string]uint64{"height": h},
+			Result: &hashResult{},
+		}
+	}
+	return calls
+}
+
+func checkBatchCalls(t *testing.T, calls []*BatchCall) {
+	is := is.New(t)
+
+	is.NoErr(calls[0].Error)
+	is.Equal(calls[0].Result.(*hashResult).Hash, "hash1")
+	is.NoErr(calls[1].Error)
+	is.Equal(calls[1].Result.(*hashResult).Hash, "hash2")
+	var rpcErr *Error
+	is.True(errors.As(calls[2].Error, &rpcErr))
+	is.Equal(rpcErr.Code, -2)
+	is.Equal(rpcErr.Message, "Too big height")
+	is.Equal(rpcErr.Method, "get_block_header_by_height")
+}
+
+func TestBatch(t *testing.T) {
+	var posts int
+	server := setupBatchServer(t, true, &posts)
+	defer server.Close()
+
+	client := New(server.URL, server.Client())
+
+	calls := batchCalls(1, 2, 101)
+	is.New(t).NoErr(client.Batch(calls))
+	is.New(t).Equal(posts, 1)
+	checkBatchCalls(t, calls)
+}
+
+func TestBatchFallback(t *testing.T) {
+	var posts int
+	server := setupBatchServer(t, false, &posts)
+	defer server.Close()
+
+	client := New(server.URL, server.Client())
+	is := is.New(t)
+
+	calls := batchCalls(1, 2, 101)
+	is.NoErr(client.Batch(calls))
+	is.Equal(posts, 4)
+	checkBatchCalls(t, calls)
+
+	// the rejection is remembered, so no batch is sent anymore
+	posts = 0
+	calls = batchCalls(1, 2, 101)
+	is.NoErr(client.DaemonEndpoint().Batch(calls))
+	is.Equal(posts, 3)
+	checkBatchCalls(t, calls)
+}
+
+func TestBatchUnauthorized(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	client := New(server.URL, server.Client())
+
+	err := client.Batch(batchCalls(1))
+	is.New(t).True(errors.Is(err, ErrUnauthorized))
+}
+
+func TestBatchTransientFailure(t *testing.T) {
+	var posts int
+	batching := setupBatchServer(t, true, &posts)
+	defer batching.Close()
+	failing := true
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if failing {
+			failing = false
+			rw.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		batching.Config.Handler.ServeHTTP(rw, req)
+	}))
+	defer server.Close()
+
+	client := New(server.URL, server.Client())
+	is := is.New(t)
+
+	var rpcErr *Error
+	is.True(errors.As(client.Batch(batchCalls(1, 2, 101)), &rpcErr))
+	is.Equal(rpcErr.HTTPStatus, http.StatusBadGateway)
+
+	// batching is not turned off by a proxy failure
+	calls := batchCalls(1, 2, 101)
+	is.NoErr(client.Batch(calls))
+	is.Equal(posts, 1)
+	checkBatchCalls(t, calls)
+}
+
+func TestBatchInterceptedRequest(t *testing.T) {
+	var posts int
+	server := setupBatchServer(t, true, &posts)
+	defer server.Close()
+
+	client := New(server.URL, server.Client()).Use(func(ctx context.Context, method string, req interface{}, res interface{}, next Invoker) error {
+		return next(ctx, method, req.([]*BatchCall)[:1], res)
+	})
+	is := is.New(t)
+
+	calls := batchCalls(1, 2)
+	is.NoErr(client.Batch(calls))
+	is.Equal(calls[0].Result.(*hashResult).Hash, "hash1")
+	is.Equal(calls[1].Result.(*hashResult).Hash, "")
+}
+
+func TestBatchRetryBusy(t *testing.T) {
+	var sizes []int
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		buff, _ := io.ReadAll(req.Body)
+		var reqs []rpcRequest
+		if err := json.Unmarshal(buff, &reqs); err != nil {
+			t.Fatal(err)
+		}
+		sizes = append(sizes, len(reqs))
+		rw.Write([]byte("["))
+		for i, r := range reqs {
+			if i > 0 {
+				rw.Write([]byte(","))
+			}
+			if r.Params.Height == 2 && len(sizes) == 1 {
+				fmt.Fprintf(rw, `{"id": %s, "jsonrpc": "2.0", "result": {"status": "BUSY"}}`, r.ID)
+				continue
+			}
+			rw.Write([]byte(r.reply()))
+		}
+		rw.Write([]byte("]"))
+	}))
+	defer server.Close()
+
+	client := New(server.URL, server.Client()).SetRetry(testRetryPolicy())
+	is := is.New(t)
+
+	calls := batchCalls(1, 2, 101)
+	is.NoErr(client.Batch(calls))
+	// only the busy call is sent again
+	is.Equal(sizes, []int{3, 1})
+	checkBatchCalls(t, calls)
+}
+
+func TestBatchFallbackCanceled(t *testing.T) {
+	var posts int
+	server := setupBatchServer(t, false, &posts)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	client := New(server.URL, server.Client()).Use(func(ctx context.Context, method string, req interface{}, res interface{}, next Invoker) error {
+		err := next(ctx, method, req, res)
+		if method != "batch" {
+			cancel()
+		}
+		return err
+	})
+	is := is.New(t)
+
+	calls := batchCalls(1, 2, 101)
+	err := client.BatchContext(ctx, calls)
+	is.True(errors.Is(err, context.Canceled))
+	is.NoErr(calls[0].Error)
+	is.True(errors.Is(calls[1].Error, context.Canceled))
+	is.True(errors.Is(calls[2].Error, context.Canceled))
+	is.Equal(posts, 2)
+}
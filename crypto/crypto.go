@@ -0,0 +1,82 @@
+// Package crypto implements the Monero cryptographic primitives used to work with
+// addresses, keys and proofs offline.
+package crypto
+
+import (
+	"errors"
+
+	"filippo.io/edwards25519"
+	"golang.org/x/crypto/sha3"
+)
+
+var (
+	// ErrInvalidScalar is returned for secret keys that are not reduced modulo the group order
+	ErrInvalidScalar = errors.New("crypto: invalid secret key")
+	// ErrInvalidPoint is returned for public keys that are not points on the curve
+	ErrInvalidPoint = errors.New("crypto: invalid public key")
+)
+
+// Keccak256 returns the original Keccak-256 hash of the concatenated data, as used by Monero (cn_fast_hash)
+func Keccak256(data ...[]byte) [32]byte {
+	h := sha3.NewLegacyKeccak256()
+	for _, d := range data {
+		h.Write(d)
+	}
+	var sum [32]byte
+	h.Sum(sum[:0])
+	return sum
+}
+
+// CheckKey reports whether key is the encoding of a point on the ed25519 curve
+func CheckKey(key [32]byte) bool {
+	_, err := new(edwards25519.Point).SetBytes(key[:])
+	return err == nil
+}
+
+// HashToScalar returns Keccak256 of the concatenated data reduced modulo the group order (hash_to_scalar)
+func HashToScalar(data ...[]byte) *edwards25519.Scalar {
+	return Reduce(Keccak256(data...))
+}
+
+// Reduce interprets b as a little endian integer and reduces it modulo the group order (sc_reduce32)
+func Reduce(b [32]byte) *edwards25519.Scalar {
+	var wide [64]byte
+	copy(wide[:], b[:])
+	// cannot fail, the input is 64 bytes long
+	s, _ := new(edwards25519.Scalar).SetUniformBytes(wide[:])
+	return s
+}
+
+// ParseScalar decodes a canonical secret key
+func ParseScalar(b [32]byte) (*edwards25519.Scalar, error) {
+	s, err := new(edwards25519.Scalar).SetCanonicalBytes(b[:])
+	if err != nil {
+		return nil, ErrInvalidScalar
+	}
+	return s, nil
+}
+
+// ParsePoint decodes a public key
+func ParsePoint(b [32]byte) (*edwards25519.Point, error) {
+	p, err := new(edwards25519.Point).SetBytes(b[:])
+	if err != nil {
+		return nil, ErrInvalidPoint
+	}
+	return p, nil
+}
+
+// Bytes returns the encoding of a point or scalar as a key
+func Bytes(v interface{ Bytes() []byte }) [32]byte {
+	var b [32]byte
+	copy(b[:], v.Bytes())
+	return b
+}
+
+// PublicKey returns the public key of a secret key (secret_key_to_public_key)
+func PublicKey(secret [32]byte) ([32]byte, error) {
+	s, err := ParseScalar(secret)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	return Bytes(new(edwards25519.Point).ScalarBaseMult(s)), nil
+}
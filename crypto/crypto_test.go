@@ -0,0 +1,268 @@
+package crypto
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"filippo.io/edwards25519"
+	"github.com/matryer/is"
+)
+
+func TestKeccak256(t *testing.T) {
+	is := is.New(t)
+	sum := Keccak256()
+	is.Equal(hex.EncodeToString(sum[:]), "c5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470")
+	sum = Keccak256([]byte("hello "), []byte("world"))
+	other := Keccak256([]byte("hello world"))
+	is.Equal(sum, other)
+}
+
+func TestPublicKey(t *testing.T) {
+	is := is.New(t)
+	var secret [32]byte
+	hex.Decode(secret[:], []byte("ac413c16b815899b69393d72086fa86d31e8e352895606180c4c8fadd707450a"))
+	pub, err := PublicKey(secret)
+	is.NoErr(err)
+	is.Equal(hex.EncodeToString(pub[:]), "0ef3c9e1146ed2a05f0eb4b25e41662bed41fa246251257c363a8ba95750cb8b")
+	is.True(CheckKey(pub))
+
+	for i := range secret {
+		secret[i] = 0xff
+	}
+	_, err = PublicKey(secret)
+	is.Equal(err, ErrInvalidScalar)
+	// the same bytes reduce to a valid scalar
+	is.True(Reduce(secret) != nil)
+}
+
+func TestSignature(t *testing.T) {
+	is := is.New(t)
+	secret, err := RandomScalar()
+	is.NoErr(err)
+	pub := Bytes(new(edwards25519.Point).ScalarBaseMult(secret))
+	hash := Keccak256([]byte("message"))
+
+	sig, err := GenerateSignature(hash, pub, secret)
+	is.NoErr(err)
+	is.True(CheckSignature(hash, pub, sig))
+
+	decoded, ok := SignatureFromBytes(sig.Bytes())
+	is.True(ok)
+	is.Equal(decoded, sig)
+
+	is.True(!CheckSignature(Keccak256([]byte("other")), pub, sig))
+	sig.R[0] ^= 1
+	is.True(!CheckSignature(hash, pub, sig))
+	is.True(!CheckSignature(hash, pub, Signature{}))
+}
+
+func key(s string) [32]byte {
+	var k [32]byte
+	hex.Decode(k[:], []byte(s))
+	return k
+}
+
+// output 1 of a stagenet transaction paying 0.55 XMR to 53zEYzu2hi3e97tdMTqTvSRAfFYXwxA7LBJEHLWvFnm699WgcsE8CJujENwNAQotKyY2u94vpbGEZTiwahuMcMfX3x6NFwY
+func TestDerivation(t *testing.T) {
+	is := is.New(t)
+	view, err := ParseScalar(key("8aa763d1c8d9da4ca75cb6ca22a021b5cca376c1367be8d62bcc9cdf4b926009"))
+	is.NoErr(err)
+	derivation, err := GenerateKeyDerivation(key("7302dd77bf4095baf868de43b7a32f4a36fe9d8b48ccfff537157a4a786fa364"), view)
+	is.NoErr(err)
+
+	is.Equal(ViewTag(derivation, 1), byte(0x1a))
+	is.True(ViewTag(derivation, 0) != 0x1a || ViewTag(derivation, 2) != 0x1a)
+	out, err := DerivePublicKey(derivation, 1, key("38e9908d33d034de0ba1281aa7afe3907b795cea14852b3d8fe276e8931cb130"))
+	is.NoErr(err)
+	is.Equal(hex.EncodeToString(out[:]), "7e4f4427539b206740bed78b81b0dc10acb89aa1545880863f73264492ee0c16")
+	spend, err := DeriveSubaddressPublicKey(derivation, 1, out)
+	is.NoErr(err)
+	is.Equal(spend, key("38e9908d33d034de0ba1281aa7afe3907b795cea14852b3d8fe276e8931cb130"))
+
+	encAmount, _ := hex.DecodeString("5db33f80fd4990bc")
+	amount, mask, err := DecodeAmount(DerivationToScalar(derivation, 1), [32]byte{}, encAmount, true)
+	is.NoErr(err)
+	is.Equal(amount, uint64(550000000000))
+	is.True(mask != nil)
+
+	invalid := [32]byte{2}
+	for CheckKey(invalid) {
+		invalid[0]++
+	}
+	_, err = GenerateKeyDerivation(invalid, view)
+	is.Equal(err, ErrInvalidPoint)
+}
+
+func TestCommit(t *testing.T) {
+	is := is.New(t)
+	is.Equal(Commit(edwards25519.NewScalar(), 1), Bytes(H))
+	mask, err := RandomScalar()
+	is.NoErr(err)
+	is.Equal(Commit(mask, 0), Bytes(new(edwards25519.Point).ScalarBaseMult(mask)))
+}
+
+// amounts of RingCT outputs before Bulletproof2 encrypt the mask and the amount as scalars
+func TestDecodeAmountFull(t *testing.T) {
+	is := is.New(t)
+	secret, err := RandomScalar()
+	is.NoErr(err)
+	mask, err := RandomScalar()
+	is.NoErr(err)
+	s1 := HashToScalar(secret.Bytes())
+	s2 := HashToScalar(s1.Bytes())
+	var a [32]byte
+	a[0], a[1] = 0x40, 0x42
+	amount, err := ParseScalar(a)
+	is.NoErr(err)
+
+	encMask := Bytes(new(edwards25519.Scalar).Add(mask, s1))
+	encAmount := Bytes(new(edwards25519.Scalar).Add(amount, s2))
+	decoded, decodedMask, err := DecodeAmount(secret, encMask, encAmount[:], false)
+	is.NoErr(err)
+	is.Equal(decoded, uint64(0x4240))
+	is.Equal(decodedMask.Equal(mask), 1)
+
+	_, _, err = DecodeAmount(secret, encMask, encAmount[:8], false)
+	is.Equal(err, ErrInvalidScalar)
+}
+
+func TestTxProof(t *testing.T) {
+	is := is.New(t)
+	hash := Keccak256([]byte("txid and message"))
+	secret, err := RandomScalar()
+	is.NoErr(err)
+	view, err := RandomScalar()
+	is.NoErr(err)
+	spend, err := RandomScalar()
+	is.NoErr(err)
+	a := Bytes(new(edwards25519.Point).ScalarBaseMult(view))
+	b := Bytes(new(edwards25519.Point).ScalarBaseMult(spend))
+	d := Bytes(new(edwards25519.Point).ScalarMult(secret, new(edwards25519.Point).ScalarBaseMult(view)))
+
+	for _, version := range []int{1, 2} {
+		r := Bytes(new(edwards25519.Point).ScalarBaseMult(secret))
+		sig, err := GenerateTxProof(hash, r, a, nil, d, secret, version)
+		is.NoErr(err)
+		is.True(CheckTxProof(hash, r, a, nil, d, sig, version))
+		is.True(!CheckTxProof(hash, r, a, nil, a, sig, version))
+		is.True(!CheckTxProof(Keccak256(), r, a, nil, d, sig, version))
+
+		// subaddresses prove r = secret·B
+		bp, _ := ParsePoint(b)
+		r = Bytes(new(edwards25519.Point).ScalarMult(secret, bp))
+		sig, err = GenerateTxProof(hash, r, a, &b, d, secret, version)
+		is.NoErr(err)
+		is.True(CheckTxProof(hash, r, a, &b, d, sig, version))
+		is.True(!CheckTxProof(hash, r, a, nil, d, sig, version))
+	}
+
+	// version 2 binds the keys to the challenge
+	r := Bytes(new(edwards25519.Point).ScalarBaseMult(secret))
+	sig, err := GenerateTxProof(hash, r, a, nil, d, secret, 2)
+	is.NoErr(err)
+	is.True(!CheckTxProof(hash, r, a, nil, d, sig, 1))
+}
+
+// vectors of monero's tests/crypto/tests.txt
+func TestHashToEC(t *testing.T) {
+	is := is.New(t)
+	for _, v := range [][2]string{
+		{"da66e9ba613919dec28ef367a125bb310d6d83fb9052e71034164b6dc4f392d0", "52b3f38753b4e13b74624862e253072cf12f745d43fcfafbe8c217701a6e5875"},
+		{"a7fbdeeccb597c2d5fdaf2ea2e10cbfcd26b5740903e7f6d46bcbf9a90384fc6", "f055ba2d0d9828ce2e203d9896bfda494d7830e7e3a27fa27d5eaa825a79a19c"},
+		{"ed6e6579368caba2cc4851672972e949c0ee586fee4d6d6a9476d4a908f64070", "da3ceda9a2ef6316bf9272566e6dffd785ac71f57855c0202f422bbb86af4ec0"},
+		{"9ae78e5620f1c4e6b29d03da006869465b3b16dae87ab0a51f4e1b74bc8aa48b", "72d8720da66f797f55fbb7fa538af0b4a4f5930c8289c991472c37dc5ec16853"},
+		{"ab49eb4834d24db7f479753217b763f70604ecb79ed37e6c788528720f424e5b", "45914ba926a1a22c8146459c7f050a51ef5f560f5b74bae436b93a379866e6b8"},
+	} {
+		p := Bytes(HashToEC(key(v[0])))
+		is.Equal(hex.EncodeToString(p[:]), v[1])
+	}
+}
+
+func signatures(s string) []Signature {
+	b, _ := hex.DecodeString(s)
+	var sigs []Signature
+	for i := 0; i < len(b); i += 64 {
+		sig, _ := SignatureFromBytes(b[i : i+64])
+		sigs = append(sigs, sig)
+	}
+	return sigs
+}
+
+func TestCheckRingSignature(t *testing.T) {
+	is := is.New(t)
+	for _, v := range []struct {
+		hash, image string
+		pubs        []string
+		sigs        string
+		good        bool
+	}{
+		// input of a transaction of block 40646
+		{
+			"aeecb4170b276d2ac69a7abca86f82621f56d943c8d4a8900cd56192da8d442d",
+			"c9679ba9ca8a6fa87a1352985e46ea3723489d3699ab1af075532f711739b9c5",
+			[]string{"6646f168c842275b31ca863f6eac8eed9e5dfc5714d5864efb62f6c340298a30"},
+			"11b4d1bd92e85f38152848cbf100c6f8b15c9de5278e4506bb9131230807d60e658188593715e7980a9d9e188d2114f2a3b71541cfe66fb94413237edf36dc0a",
+			true,
+		},
+		// vectors of monero's tests/crypto/tests.txt
+		{
+			"90660b84dd3be5705c7766695fec404348af6df58f8c5d58213f3b70b8b67a23",
+			"6289b9b151eeb263fc29e4b5e90978db7670f06f408403c8973bbfff2a884dd9",
+			[]string{"4af96f2c3a70ac1860d48132136989c1d38551367025d43f36aec0ffa8e7f28a", "376cc178d8ae3a68ce467bfbe719e88b22514617dbd1e764e0b94b4f6bc961af"},
+			"4ccadd504d1d03e385ebd25dc51b98c6f3a0e1c1be7e5694e44dc2377898510ca3202d7872294cc04b65d8c109e3a6e843c327b3416ca3a2b1c585fe4152260555441dd7b1543549f749acf5fc9a93a3f3c240425c5f7cadccdef4f06cef0702ae4ad477d0cb60a1a48c1da22f5a8b20c7c5672833c7ae13f78edeb3db1a7b01",
+			true,
+		},
+		{
+			"d280b24c280daade9d2bcd68c6dfd39d3a13eb1b0645c4f7d2b0613dd4b5af3d",
+			"f1b943daa1ef225726215f551dfd85f56a3b429ded8608a09a8310a90b8aa88a",
+			[]string{"2d4e494897c24b1730f018df65468c2647b2dc19f650d1a9e055b9319045ff13", "74db9c16b0cb4beb7d48ec77b654c63917529072aa57d381b5e3b8dbb06e0f5b"},
+			"8aae0a8523d65b3746c87994e4cffaf437ac147a82efe34389d270a976183006c7de37ef0362e13aab9287a85445748a8e0e1a357c6a0ba090f436937a1878b47b41de38a3737152453ca3c0c6546b65ceaff3298329273b0808d35af376a20c1217c85b153d40bc154108eca199175b3efa3f190740325c734d82cfb054d50f",
+			false,
+		},
+		{
+			"17e1d8c991803cf0747a66dd16a3c5069afb0f604670b823b675bed5de59d6c5",
+			"81abb2291ae3e208665370f4fe07c1d82d3f8f6a6ccafe7e5fb4819ce1d2f113",
+			[]string{"130f844d2ff629d6374653997afec462eceed08648daff08eac4c58b9006e6e4", "f92f7aa2bb9273830b966f71c7d7aa0ee8473973d65fa044c74ec4d4628d765c", "8c1f5b3b71c27ebdfadefed2594ba57b19934eda6fb7b5c7e63dd0ed471b6e2e"},
+			"7e799950f135343936af6719ebcedfe6e4a3fceaa86047923f592e1fb69aa909575174936ecf6615813c0a4620aa77161d8309aefffd6d33b8eb31b37aa36109dceafe0b8b49a5a280561b204f71f1c6116053ed1bac94b26fcad0ec947a9b01e4459a956e4644f7a8c39719164a87c93d21971366e66e0409556fc93c4c1d0f7db9b2d221fdf6fae05cca363b5e9ea1a7c9b0c80080b9c825f9bcc0b734030711b981b71f0c193bdf51b41bdca81579144e1d7ea134b93a6ba40bd18bb74f07",
+			true,
+		},
+	} {
+		var pubs [][32]byte
+		for _, p := range v.pubs {
+			pubs = append(pubs, key(p))
+		}
+		is.Equal(CheckRingSignature(key(v.hash), key(v.image), pubs, signatures(v.sigs)), v.good)
+	}
+}
+
+func TestGenerateRingSignature(t *testing.T) {
+	is := is.New(t)
+	hash := Keccak256([]byte("prefix"))
+	secret, err := RandomScalar()
+	is.NoErr(err)
+	pubs := make([][32]byte, 4)
+	for i := range pubs {
+		s, err := RandomScalar()
+		is.NoErr(err)
+		pubs[i] = Bytes(new(edwards25519.Point).ScalarBaseMult(s))
+	}
+	pubs[2] = Bytes(new(edwards25519.Point).ScalarBaseMult(secret))
+	image := KeyImage(pubs[2], secret)
+
+	sigs, err := GenerateRingSignature(hash, image, pubs, secret, 2)
+	is.NoErr(err)
+	is.True(CheckRingSignature(hash, image, pubs, sigs))
+	is.True(!CheckRingSignature(Keccak256(), image, pubs, sigs))
+	is.True(!CheckRingSignature(hash, image, pubs[:3], sigs[:3]))
+
+	// key images with a torsion component are rejected
+	torsion := key("26e8958fc2b227b045c3f489f2ef98f0d5dfac05d3c63339b13802886d53fc05")
+	tp, err := ParsePoint(torsion)
+	is.NoErr(err)
+	ip, err := ParsePoint(image)
+	is.NoErr(err)
+	bad := Bytes(new(edwards25519.Point).Add(ip, tp))
+	sigs, err = GenerateRingSignature(hash, bad, pubs, secret, 2)
+	is.NoErr(err)
+	is.True(!CheckRingSignature(hash, bad, pubs, sigs))
+}
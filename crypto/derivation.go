@@ -0,0 +1,62 @@
+package crypto
+
+import (
+	"encoding/binary"
+
+	"filippo.io/edwards25519"
+)
+
+// viewTagKey is the domain separator of view tags, without a trailing NUL
+const viewTagKey = "view_tag"
+
+// GenerateKeyDerivation returns the shared secret 8·secret·pub of the outputs of a transaction. The sender
+// computes it from the tx secret key and the view key of the recipient, the recipient from its secret view
+// key and the tx public key (generate_key_derivation).
+func GenerateKeyDerivation(pub [32]byte, secret *edwards25519.Scalar) ([32]byte, error) {
+	p, err := ParsePoint(pub)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	d := new(edwards25519.Point).ScalarMult(secret, p)
+	return Bytes(d.MultByCofactor(d)), nil
+}
+
+// varint appends index to b as a varint
+func varint(b []byte, index uint64) []byte {
+	buf := make([]byte, binary.MaxVarintLen64)
+	return append(b, buf[:binary.PutUvarint(buf, index)]...)
+}
+
+// DerivationToScalar returns the secret of output index, Hs(derivation || varint(index)) (derivation_to_scalar)
+func DerivationToScalar(derivation [32]byte, index uint64) *edwards25519.Scalar {
+	return HashToScalar(varint(derivation[:], index))
+}
+
+// DerivePublicKey returns the one time key of output index paid to the spend key base,
+// DerivationToScalar(derivation, index)·G + base (derive_public_key)
+func DerivePublicKey(derivation [32]byte, index uint64, base [32]byte) ([32]byte, error) {
+	b, err := ParsePoint(base)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	p := new(edwards25519.Point).ScalarBaseMult(DerivationToScalar(derivation, index))
+	return Bytes(p.Add(p, b)), nil
+}
+
+// DeriveSubaddressPublicKey returns the spend key an output of one time key out was paid to,
+// out - DerivationToScalar(derivation, index)·G (derive_subaddress_public_key)
+func DeriveSubaddressPublicKey(derivation [32]byte, index uint64, out [32]byte) ([32]byte, error) {
+	o, err := ParsePoint(out)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	p := new(edwards25519.Point).ScalarBaseMult(DerivationToScalar(derivation, index))
+	return Bytes(p.Subtract(o, p)), nil
+}
+
+// ViewTag returns the view tag of output index, the first byte of keccak("view_tag" || derivation || varint(index))
+// (derive_view_tag)
+func ViewTag(derivation [32]byte, index uint64) byte {
+	sum := Keccak256([]byte(viewTagKey), varint(derivation[:], index))
+	return sum[0]
+}
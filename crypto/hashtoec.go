@@ -0,0 +1,103 @@
+package crypto
+
+import (
+	"filippo.io/edwards25519"
+	"filippo.io/edwards25519/field"
+)
+
+// Constants of the map to the curve of ge_fromfe_frombytes_vartime, A is the Montgomery coefficient 486662
+var (
+	feA      = new(field.Element).Mult32(new(field.Element).One(), 486662)
+	feMA     = new(field.Element).Negate(feA)
+	feMA2    = new(field.Element).Negate(new(field.Element).Square(feA))
+	feSqrtM1 = sqrt(new(field.Element).Negate(new(field.Element).One()))
+	// A·(A + 2)
+	feAA2  = new(field.Element).Multiply(feA, new(field.Element).Add(feA, new(field.Element).Mult32(new(field.Element).One(), 2)))
+	fe2AA2 = new(field.Element).Add(feAA2, feAA2)
+	// fffb1 = sqrt(-2·A·(A + 2)), fffb2 = sqrt(2·A·(A + 2)), fffb3 = sqrt(-sqrt(-1)·A·(A + 2)), fffb4 = sqrt(sqrt(-1)·A·(A + 2))
+	feFffb1 = sqrt(new(field.Element).Negate(fe2AA2))
+	feFffb2 = sqrt(fe2AA2)
+	feFffb3 = sqrt(new(field.Element).Negate(new(field.Element).Multiply(feSqrtM1, feAA2)))
+	feFffb4 = sqrt(new(field.Element).Multiply(feSqrtM1, feAA2))
+)
+
+func sqrt(v *field.Element) *field.Element {
+	r, ok := new(field.Element).SqrtRatio(v, new(field.Element).One())
+	if ok != 1 {
+		panic("crypto: no square root")
+	}
+	return r
+}
+
+// fromFieldBytes maps 32 bytes to a point of the curve (ge_fromfe_frombytes_vartime)
+func fromFieldBytes(s [32]byte) *edwards25519.Point {
+	// unlike fe_frombytes, all 256 bits are read: 2^255 = 19 mod p
+	u, _ := new(field.Element).SetBytes(s[:])
+	if s[31]&0x80 != 0 {
+		u.Add(u, new(field.Element).Mult32(new(field.Element).One(), 19))
+	}
+
+	one := new(field.Element).One()
+	v := new(field.Element).Square(u)
+	v.Add(v, v)                         // 2·u²
+	w := new(field.Element).Add(v, one) // w = 2·u² + 1
+	x := new(field.Element).Square(w)
+	y := new(field.Element).Multiply(feMA2, v)
+	x.Add(x, y) // x = w² - 2·A²·u²
+
+	// rx = (w / x)^((p + 3) / 8) = w·x³·(w·x⁷)^((p - 5) / 8)
+	x3 := new(field.Element).Multiply(new(field.Element).Square(x), x)
+	x7 := new(field.Element).Multiply(new(field.Element).Square(x3), x)
+	rx := new(field.Element).Pow22523(new(field.Element).Multiply(w, x7))
+	rx.Multiply(rx, x3)
+	rx.Multiply(rx, w)
+
+	y.Square(rx)
+	x.Multiply(y, x)
+	y.Subtract(w, x)
+	z := new(field.Element).Set(feMA)
+	var sign int
+	switch {
+	case y.Equal(new(field.Element).Zero()) == 1:
+		rx.Multiply(rx, feFffb2)
+		rx.Multiply(rx, u)
+		z.Multiply(z, v)
+	case y.Add(w, x).Equal(new(field.Element).Zero()) == 1:
+		rx.Multiply(rx, feFffb1)
+		rx.Multiply(rx, u)
+		z.Multiply(z, v)
+	default:
+		x.Multiply(x, feSqrtM1)
+		if y.Subtract(w, x).Equal(new(field.Element).Zero()) == 1 {
+			rx.Multiply(rx, feFffb4)
+		} else {
+			rx.Multiply(rx, feFffb3)
+		}
+		sign = 1
+	}
+	if rx.IsNegative() != sign {
+		rx.Negate(rx)
+	}
+
+	// the point is (rx·(z + w) : z - w : z + w)
+	rz := new(field.Element).Add(z, w)
+	ry := new(field.Element).Subtract(z, w)
+	rx.Multiply(rx, rz)
+	inv := new(field.Element).Invert(rz)
+	px := new(field.Element).Multiply(rx, inv)
+	py := new(field.Element).Multiply(ry, inv)
+	b := py.Bytes()
+	b[31] |= byte(px.IsNegative() << 7)
+	p, err := new(edwards25519.Point).SetBytes(b)
+	if err != nil {
+		panic("crypto: hash to curve off the curve")
+	}
+	return p
+}
+
+// HashToEC hashes a key to a point of the prime order subgroup, 8 times the map of its Keccak256 to the curve
+// (hash_to_ec)
+func HashToEC(key [32]byte) *edwards25519.Point {
+	p := fromFieldBytes(Keccak256(key[:]))
+	return p.MultByCofactor(p)
+}
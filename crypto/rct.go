@@ -0,0 +1,78 @@
+package crypto
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+
+	"filippo.io/edwards25519"
+)
+
+// Domain separators of RingCT amount encryption
+const (
+	amountKey = "amount"
+	maskKey   = "commitment_mask"
+)
+
+// H is the second generator of RingCT commitments, 8·keccak(G) read as a point
+var H = mustPoint("8b655970153799af2aeadc9ff1add0ea6c7251d54154cfa92c173a0dd39c1f94")
+
+func mustPoint(s string) *edwards25519.Point {
+	var b [32]byte
+	if _, err := hex.Decode(b[:], []byte(s)); err != nil {
+		panic(err)
+	}
+	p, err := ParsePoint(b)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// Commit returns the Pedersen commitment mask·G + amount·H of an amount
+func Commit(mask *edwards25519.Scalar, amount uint64) [32]byte {
+	var b [32]byte
+	binary.LittleEndian.PutUint64(b[:], amount)
+	// cannot fail, amounts are far below the group order
+	a, _ := edwards25519.NewScalar().SetCanonicalBytes(b[:])
+	return Bytes(new(edwards25519.Point).VarTimeDoubleScalarBaseMult(a, H, mask))
+}
+
+// DecodeAmount decrypts the amount and the commitment mask of a RingCT output (ecdhDecode). secret is the
+// output secret, DerivationToScalar of the output.
+//
+// Compact encodings, used since Bulletproof2 transactions, encrypt only the amount in 8 bytes and derive the
+// mask from the secret. Older encodings encrypt the mask and the amount in 32 bytes each. The amount must be
+// checked against the output commitment with Commit.
+func DecodeAmount(secret *edwards25519.Scalar, encMask [32]byte, encAmount []byte, compact bool) (uint64, *edwards25519.Scalar, error) {
+	s := secret.Bytes()
+	if compact {
+		if len(encAmount) < 8 {
+			return 0, nil, ErrInvalidScalar
+		}
+		pad := Keccak256([]byte(amountKey), s)
+		var amount [8]byte
+		for i := range amount {
+			amount[i] = encAmount[i] ^ pad[i]
+		}
+		return binary.LittleEndian.Uint64(amount[:]), HashToScalar([]byte(maskKey), s), nil
+	}
+
+	if len(encAmount) != 32 {
+		return 0, nil, ErrInvalidScalar
+	}
+	var b [32]byte
+	copy(b[:], encAmount)
+	amount, err := ParseScalar(b)
+	if err != nil {
+		return 0, nil, err
+	}
+	mask, err := ParseScalar(encMask)
+	if err != nil {
+		return 0, nil, err
+	}
+	s1 := HashToScalar(s)
+	s2 := HashToScalar(s1.Bytes())
+	mask.Subtract(mask, s1)
+	amount.Subtract(amount, s2)
+	return binary.LittleEndian.Uint64(amount.Bytes()), mask, nil
+}
@@ -0,0 +1,103 @@
+package crypto
+
+import (
+	"filippo.io/edwards25519"
+)
+
+// minusOne is l - 1, l the order of the prime order subgroup
+var minusOne, _ = new(edwards25519.Scalar).SetCanonicalBytes([]byte{
+	0xec, 0xd3, 0xf5, 0x5c, 0x1a, 0x63, 0x12, 0x58, 0xd6, 0x9c, 0xf7, 0xa2, 0xde, 0xf9, 0xde, 0x14,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x10,
+})
+
+// inSubgroup reports whether l·p, computed as (l - 1)·p + p, is the identity
+func inSubgroup(p *edwards25519.Point) bool {
+	q := new(edwards25519.Point).ScalarMult(minusOne, p)
+	return q.Add(q, p).Equal(edwards25519.NewIdentityPoint()) == 1
+}
+
+// KeyImage returns the key image secret·HashToEC(pub) of the output of one time key pub (generate_key_image)
+func KeyImage(pub [32]byte, secret *edwards25519.Scalar) [32]byte {
+	return Bytes(new(edwards25519.Point).ScalarMult(secret, HashToEC(pub)))
+}
+
+// GenerateRingSignature signs hash with the secret key of pubs[index] and its key image, hiding which key
+// signed among pubs (generate_ring_signature). The signatures of pre-RingCT inputs and of spend proofs are ring
+// signatures.
+func GenerateRingSignature(hash [32]byte, image [32]byte, pubs [][32]byte, secret *edwards25519.Scalar, index int) ([]Signature, error) {
+	ip, err := ParsePoint(image)
+	if err != nil {
+		return nil, err
+	}
+	sigs := make([]Signature, len(pubs))
+	data := [][]byte{hash[:]}
+	sum := edwards25519.NewScalar()
+	var k *edwards25519.Scalar
+	for i, pub := range pubs {
+		p, err := ParsePoint(pub)
+		if err != nil {
+			return nil, err
+		}
+		var a, b *edwards25519.Point
+		if i == index {
+			if k, err = RandomScalar(); err != nil {
+				return nil, err
+			}
+			a = new(edwards25519.Point).ScalarBaseMult(k)
+			b = new(edwards25519.Point).ScalarMult(k, HashToEC(pub))
+		} else {
+			c, err := RandomScalar()
+			if err != nil {
+				return nil, err
+			}
+			r, err := RandomScalar()
+			if err != nil {
+				return nil, err
+			}
+			a = new(edwards25519.Point).VarTimeDoubleScalarBaseMult(c, p, r)
+			b = new(edwards25519.Point).VarTimeMultiScalarMult([]*edwards25519.Scalar{r, c}, []*edwards25519.Point{HashToEC(pub), ip})
+			sum.Add(sum, c)
+			sigs[i] = Signature{C: Bytes(c), R: Bytes(r)}
+		}
+		data = append(data, a.Bytes(), b.Bytes())
+	}
+	c := new(edwards25519.Scalar).Subtract(HashToScalar(data...), sum)
+	r := new(edwards25519.Scalar).Subtract(k, new(edwards25519.Scalar).Multiply(c, secret))
+	sigs[index] = Signature{C: Bytes(c), R: Bytes(r)}
+	return sigs, nil
+}
+
+// CheckRingSignature verifies a ring signature of hash with the key image image by one of pubs (check_ring_signature)
+func CheckRingSignature(hash [32]byte, image [32]byte, pubs [][32]byte, sigs []Signature) bool {
+	if len(pubs) == 0 || len(sigs) != len(pubs) {
+		return false
+	}
+	ip, err := ParsePoint(image)
+	if err != nil {
+		return false
+	}
+	if !inSubgroup(ip) {
+		return false
+	}
+	data := [][]byte{hash[:]}
+	sum := edwards25519.NewScalar()
+	for i, pub := range pubs {
+		p, err := ParsePoint(pub)
+		if err != nil {
+			return false
+		}
+		c, err := ParseScalar(sigs[i].C)
+		if err != nil {
+			return false
+		}
+		r, err := ParseScalar(sigs[i].R)
+		if err != nil {
+			return false
+		}
+		a := new(edwards25519.Point).VarTimeDoubleScalarBaseMult(c, p, r)
+		b := new(edwards25519.Point).VarTimeMultiScalarMult([]*edwards25519.Scalar{r, c}, []*edwards25519.Point{HashToEC(pub), ip})
+		data = append(data, a.Bytes(), b.Bytes())
+		sum.Add(sum, c)
+	}
+	return HashToScalar(data...).Equal(sum) == 1
+}
@@ -0,0 +1,72 @@
+package crypto
+
+import (
+	"crypto/rand"
+
+	"filippo.io/edwards25519"
+)
+
+// Signature is a Schnorr signature (c, r) as produced by monero's generate_signature
+type Signature struct {
+	C [32]byte
+	R [32]byte
+}
+
+// Bytes returns c || r
+func (s Signature) Bytes() []byte {
+	return append(append(make([]byte, 0, 64), s.C[:]...), s.R[:]...)
+}
+
+// SignatureFromBytes decodes c || r
+func SignatureFromBytes(b []byte) (Signature, bool) {
+	var s Signature
+	if len(b) != 64 {
+		return s, false
+	}
+	copy(s.C[:], b)
+	copy(s.R[:], b[32:])
+	return s, true
+}
+
+// RandomScalar returns a uniformly random scalar
+func RandomScalar() (*edwards25519.Scalar, error) {
+	var b [64]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return nil, err
+	}
+	return new(edwards25519.Scalar).SetUniformBytes(b[:])
+}
+
+// GenerateSignature signs hash with secret, whose public key is pub (generate_signature)
+func GenerateSignature(hash [32]byte, pub [32]byte, secret *edwards25519.Scalar) (Signature, error) {
+	k, err := RandomScalar()
+	if err != nil {
+		return Signature{}, err
+	}
+	comm := new(edwards25519.Point).ScalarBaseMult(k)
+	c := HashToScalar(hash[:], pub[:], comm.Bytes())
+	r := new(edwards25519.Scalar).Subtract(k, new(edwards25519.Scalar).Multiply(c, secret))
+	return Signature{C: Bytes(c), R: Bytes(r)}, nil
+}
+
+// CheckSignature verifies a signature of hash by the public key pub (check_signature)
+func CheckSignature(hash [32]byte, pub [32]byte, sig Signature) bool {
+	p, err := ParsePoint(pub)
+	if err != nil {
+		return false
+	}
+	c, err := ParseScalar(sig.C)
+	if err != nil || c.Equal(edwards25519.NewScalar()) == 1 {
+		return false
+	}
+	r, err := ParseScalar(sig.R)
+	if err != nil {
+		return false
+	}
+	comm := new(edwards25519.Point).VarTimeDoubleScalarBaseMult(c, p, r)
+	if comm.Equal(edwards25519.NewIdentityPoint()) == 1 {
+		return false
+	}
+	expected := HashToScalar(hash[:], pub[:], comm.Bytes())
+	return expected.Equal(c) == 1
+}
@@ -0,0 +1,84 @@
+package crypto
+
+import (
+	"filippo.io/edwards25519"
+)
+
+// txProofKey is the domain separator of version 2 tx proofs, hashed into the challenge
+const txProofKey = "TXPROOF_V2"
+
+// txProofChallenge returns Hs(hash || D || X || Y), followed by the separator and R, A and B from version 2
+func txProofChallenge(hash [32]byte, r, a [32]byte, b *[32]byte, d [32]byte, x, y *edwards25519.Point, version int) *edwards25519.Scalar {
+	data := [][]byte{hash[:], d[:], x.Bytes(), y.Bytes()}
+	if version > 1 {
+		sep := Keccak256([]byte(txProofKey))
+		var base [32]byte
+		if b != nil {
+			base = *b
+		}
+		data = append(data, sep[:], r[:], a[:], base[:])
+	}
+	return HashToScalar(data...)
+}
+
+// GenerateTxProof proves that d = secret·a and r = secret·b, or secret·G when b is nil, without revealing
+// secret (generate_tx_proof). Out proofs use the tx secret key, with the tx public key as r and the view key of
+// the recipient as a. In proofs use the secret view key, with the view key as r and the tx public key as a.
+// b is the spend key of subaddresses.
+func GenerateTxProof(hash [32]byte, r, a [32]byte, b *[32]byte, d [32]byte, secret *edwards25519.Scalar, version int) (Signature, error) {
+	ap, err := ParsePoint(a)
+	if err != nil {
+		return Signature{}, err
+	}
+	k, err := RandomScalar()
+	if err != nil {
+		return Signature{}, err
+	}
+	x := new(edwards25519.Point).ScalarBaseMult(k)
+	if b != nil {
+		bp, err := ParsePoint(*b)
+		if err != nil {
+			return Signature{}, err
+		}
+		x.ScalarMult(k, bp)
+	}
+	y := new(edwards25519.Point).ScalarMult(k, ap)
+	c := txProofChallenge(hash, r, a, b, d, x, y, version)
+	s := new(edwards25519.Scalar).Subtract(k, new(edwards25519.Scalar).Multiply(c, secret))
+	return Signature{C: Bytes(c), R: Bytes(s)}, nil
+}
+
+// CheckTxProof verifies a proof made by GenerateTxProof (check_tx_proof)
+func CheckTxProof(hash [32]byte, r, a [32]byte, b *[32]byte, d [32]byte, sig Signature, version int) bool {
+	rp, errR := ParsePoint(r)
+	ap, errA := ParsePoint(a)
+	dp, errD := ParsePoint(d)
+	if errR != nil || errA != nil || errD != nil {
+		return false
+	}
+	var bp *edwards25519.Point
+	if b != nil {
+		var err error
+		if bp, err = ParsePoint(*b); err != nil {
+			return false
+		}
+	}
+	c, err := ParseScalar(sig.C)
+	if err != nil {
+		return false
+	}
+	s, err := ParseScalar(sig.R)
+	if err != nil {
+		return false
+	}
+
+	// X = c·R + s·B or c·R + s·G, Y = c·D + s·A
+	var x *edwards25519.Point
+	if bp == nil {
+		x = new(edwards25519.Point).VarTimeDoubleScalarBaseMult(c, rp, s)
+	} else {
+		x = new(edwards25519.Point).VarTimeMultiScalarMult([]*edwards25519.Scalar{c, s}, []*edwards25519.Point{rp, bp})
+	}
+	y := new(edwards25519.Point).VarTimeMultiScalarMult([]*edwards25519.Scalar{c, s}, []*edwards25519.Point{dp, ap})
+	return txProofChallenge(hash, r, a, b, d, x, y, version).Equal(c) == 1
+}
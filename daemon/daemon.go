@@ -1,62 +1,136 @@
 package daemon
 
+import (
+	"context"
+	"errors"
+)
+
 // Daemon interface is a list of the monerod daemon RPC calls, their inputs and outputs, and examples of each.
 // Many RPC calls use the daemon's JSON RPC interface while others use their own interfaces, as demonstrated below.
+// Every call has a Context variant which takes a context.Context used to cancel the request or bound it with a deadline.
 type Daemon interface {
 	GenerateBlocks(req *GenerateBlocksRequest) (*GenerateBlocksResponse, error)
+	GenerateBlocksContext(ctx context.Context, req *GenerateBlocksRequest) (*GenerateBlocksResponse, error)
 	// GetBlockCount Look up how many blocks are in the longest chain known to the node.
 	GetBlockCount() (*GetBlockCountResponse, error)
+	GetBlockCountContext(ctx context.Context) (*GetBlockCountResponse, error)
 	// OnGetBlockHash Look up a block's hash by its height.
 	OnGetBlockHash(req []uint64) (string, error)
+	OnGetBlockHashContext(ctx context.Context, req []uint64) (string, error)
 	// GetBlockTemplate Get a block template on which mining a new block.
 	GetBlockTemplate(req *GetBlockTemplateRequest) (*GetBlockTemplateResponse, error)
+	GetBlockTemplateContext(ctx context.Context, req *GetBlockTemplateRequest) (*GetBlockTemplateResponse, error)
 	// SubmitBlock Submit a mined block to the network.
 	SubmitBlock(req []string) (*SubmitBlockResponse, error)
+	SubmitBlockContext(ctx context.Context, req []string) (*SubmitBlockResponse, error)
 	// GetLastBlockHeader Block header information for the most recent block is easily retrieved with this method. No inputs are needed.
 	GetLastBlockHeader() (*GetLastBlockHeaderResponse, error)
+	GetLastBlockHeaderContext(ctx context.Context) (*GetLastBlockHeaderResponse, error)
 	// GetBlockHeaderByHash Block header information can be retrieved using either a block's hash or height.
 	// This method includes a block's hash as an input parameter to retrieve basic information about the block.
 	GetBlockHeaderByHash(req *GetBlockHeaderByHashRequest) (*GetBlockHeaderByHashResponse, error)
+	GetBlockHeaderByHashContext(ctx context.Context, req *GetBlockHeaderByHashRequest) (*GetBlockHeaderByHashResponse, error)
 	// GetBlockHeaderByHeight Similar to get_block_header_by_hash above.
 	// This method includes a block's height as an input parameter to retrieve basic information about the block.
 	GetBlockHeaderByHeight(req *GetBlockHeaderByHeightRequest) (*GetBlockHeaderByHeightResponse, error)
+	GetBlockHeaderByHeightContext(ctx context.Context, req *GetBlockHeaderByHeightRequest) (*GetBlockHeaderByHeightResponse, error)
 	// GetBlockHeadersRange Similar to get_block_header_by_height above, but for a range of blocks.
 	// This method includes a starting block height and an ending block height as parameters to retrieve basic information about the range of blocks.
 	GetBlockHeadersRange(req *GetBlockHeadersRangeRequest) (*GetBlockHeadersRangeResponse, error)
+	GetBlockHeadersRangeContext(ctx context.Context, req *GetBlockHeadersRangeRequest) (*GetBlockHeadersRangeResponse, error)
 	// GetBlock Full block information can be retrieved by either block height or hash, like with the above block header calls.
 	// For full block information, both lookups use the same method, but with different input parameters.
 	GetBlock(req *GetBlockRequest) (*GetBlockResponse, error)
+	GetBlockContext(ctx context.Context, req *GetBlockRequest) (*GetBlockResponse, error)
 	// GetConnections Retrieve information about incoming and outgoing connections to your node.
 	GetConnections() (*GetConnectionsResponse, error)
+	GetConnectionsContext(ctx context.Context) (*GetConnectionsResponse, error)
 	// GetInfo Retrieve general information about the state of your node and the network.
 	GetInfo() (*GetInfoResponse, error)
+	GetInfoContext(ctx context.Context) (*GetInfoResponse, error)
 	// HardForkInfo Look up information regarding hard fork voting and readiness.
 	HardForkInfo() (*HardForkInfoResponse, error)
+	HardForkInfoContext(ctx context.Context) (*HardForkInfoResponse, error)
 	// SetBans Ban another node by IP.
 	SetBans(req *SetBansRequest) error
+	SetBansContext(ctx context.Context, req *SetBansRequest) error
 	// GetBans Get list of banned IPs.
 	GetBans() (*GetBansResponse, error)
+	GetBansContext(ctx context.Context) (*GetBansResponse, error)
 	// FlushTxpool Flush tx ids from transaction pool
 	FlushTxpool(req *FlushTxpoolRequest) error
+	FlushTxpoolContext(ctx context.Context, req *FlushTxpoolRequest) error
 	// GetOutputHistogram Get a histogram of output amounts. For all amounts (possibly filtered by parameters), gives the number of outputs on the chain for that amount.
 	// RingCT outputs counts as 0 amount.
 	GetOutputHistogram(req *GetOutputHistogramRequest) (*GetOutputHistogramResponse, error)
+	GetOutputHistogramContext(ctx context.Context, req *GetOutputHistogramRequest) (*GetOutputHistogramResponse, error)
 	// GetVersion Give the node current version
 	GetVersion() (*GetVersionResponse, error)
+	GetVersionContext(ctx context.Context) (*GetVersionResponse, error)
 	// GetCoinbaseTxSum Get the coinbase amount and the fees amount for n last blocks starting at particular height
 	GetCoinbaseTxSum(req *GetCoinbaseTxSumRequest) (*GetCoinbaseTxSumResponse, error)
+	GetCoinbaseTxSumContext(ctx context.Context, req *GetCoinbaseTxSumRequest) (*GetCoinbaseTxSumResponse, error)
 	// GetFeeEstimate Gives an estimation on fees per byte.
 	GetFeeEstimate(req *GetFeeEstimateRequest) (*GetFeeEstimateResponse, error)
+	GetFeeEstimateContext(ctx context.Context, req *GetFeeEstimateRequest) (*GetFeeEstimateResponse, error)
 	// GetAlternateChains Display alternative chains seen by the node.
 	GetAlternateChains() (*GetAlternateChainsResponse, error)
+	GetAlternateChainsContext(ctx context.Context) (*GetAlternateChainsResponse, error)
 	// RelayTx Relay a list of transaction IDs.
 	RelayTx(req *RelayTxRequest) error
+	RelayTxContext(ctx context.Context, req *RelayTxRequest) error
 	// SyncInfo Get synchronisation informations
 	SyncInfo() (*SyncInfoResponse, error)
+	SyncInfoContext(ctx context.Context) (*SyncInfoResponse, error)
 	// GetTxpoolBacklog Get all transaction pool backlog
 	GetTxpoolBacklog() (*GetTxpoolBacklogResponse, error)
+	GetTxpoolBacklogContext(ctx context.Context) (*GetTxpoolBacklogResponse, error)
 	// GetOutputDistribution Alias: None.
 	GetOutputDistribution(req *GetOutputDistributionRequest) (*GetOutputDistributionResponse, error)
+	GetOutputDistributionContext(ctx context.Context, req *GetOutputDistributionRequest) (*GetOutputDistributionResponse, error)
+	// GetHeight Get the node's current height.
+	GetHeight() (*GetHeightResponse, error)
+	GetHeightContext(ctx context.Context) (*GetHeightResponse, error)
+	// GetTransactions Look up one or more transactions by hash.
+	GetTransactions(req *GetTransactionsRequest) (*GetTransactionsResponse, error)
+	GetTransactionsContext(ctx context.Context, req *GetTransactionsRequest) (*GetTransactionsResponse, error)
+	// GetAltBlocksHashes Get the known blocks hashes which are not on the main chain.
+	GetAltBlocksHashes() (*GetAltBlocksHashesResponse, error)
+	GetAltBlocksHashesContext(ctx context.Context) (*GetAltBlocksHashesResponse, error)
+	// IsKeyImageSpent Check if outputs have been spent using the key image associated with the output.
+	IsKeyImageSpent(req *IsKeyImageSpentRequest) (*IsKeyImageSpentResponse, error)
+	IsKeyImageSpentContext(ctx context.Context, req *IsKeyImageSpentRequest) (*IsKeyImageSpentResponse, error)
+	// SendRawTransaction Broadcast a raw transaction to the network.
+	SendRawTransaction(req *SendRawTransactionRequest) (*SendRawTransactionResponse, error)
+	SendRawTransactionContext(ctx context.Context, req *SendRawTransactionRequest) (*SendRawTransactionResponse, error)
+	// GetTransactionPool Show information about valid transactions seen by the node but not yet mined into a block,
+	// as well as spent key image information for the txpool in the node's memory.
+	GetTransactionPool() (*GetTransactionPoolResponse, error)
+	GetTransactionPoolContext(ctx context.Context) (*GetTransactionPoolResponse, error)
+	// GetTransactionPoolHashes Get hashes from transaction pool.
+	GetTransactionPoolHashes() (*GetTransactionPoolHashesResponse, error)
+	GetTransactionPoolHashesContext(ctx context.Context) (*GetTransactionPoolHashesResponse, error)
+	// GetOuts Get outputs by amount and global index.
+	GetOuts(req *GetOutsRequest) (*GetOutsResponse, error)
+	GetOutsContext(ctx context.Context, req *GetOutsRequest) (*GetOutsResponse, error)
+	// GetBlocksBin Get all blocks info, starting after the last known block id or at start_height. Binary request.
+	GetBlocksBin(req *GetBlocksBinRequest) (*GetBlocksBinResponse, error)
+	GetBlocksBinContext(ctx context.Context, req *GetBlocksBinRequest) (*GetBlocksBinResponse, error)
+	// GetBlocksByHeightBin Get blocks by height. Binary request.
+	GetBlocksByHeightBin(req *GetBlocksByHeightBinRequest) (*GetBlocksByHeightBinResponse, error)
+	GetBlocksByHeightBinContext(ctx context.Context, req *GetBlocksByHeightBinRequest) (*GetBlocksByHeightBinResponse, error)
+	// GetHashesBin Get hashes. Binary request.
+	GetHashesBin(req *GetHashesBinRequest) (*GetHashesBinResponse, error)
+	GetHashesBinContext(ctx context.Context, req *GetHashesBinRequest) (*GetHashesBinResponse, error)
+	// GetOIndexesBin Get global outputs of transactions. Binary request.
+	GetOIndexesBin(req *GetOIndexesBinRequest) (*GetOIndexesBinResponse, error)
+	GetOIndexesBinContext(ctx context.Context, req *GetOIndexesBinRequest) (*GetOIndexesBinResponse, error)
+	// GetOutsBin Get outputs. Binary request.
+	GetOutsBin(req *GetOutsBinRequest) (*GetOutsBinResponse, error)
+	GetOutsBinContext(ctx context.Context, req *GetOutsBinRequest) (*GetOutsBinResponse, error)
+	// GetTransactionPoolHashesBin Get hashes from transaction pool. Binary request.
+	GetTransactionPoolHashesBin() (*GetTransactionPoolHashesBinResponse, error)
+	GetTransactionPoolHashesBinContext(ctx context.Context) (*GetTransactionPoolHashesBinResponse, error)
 }
 
 // MoneroRPC interface for client
@@ -64,6 +138,31 @@ type MoneroRPC interface {
 	Do(method string, req interface{}, res interface{}) error
 }
 
+// MoneroOtherRPC is implemented by clients which can call monerod's path based endpoints
+// (e.g. /get_height) that take and return plain JSON instead of a json rpc envelope.
+type MoneroOtherRPC interface {
+	DoOther(ctx context.Context, path string, req interface{}, res interface{}) error
+}
+
+// ErrOtherRPCNotSupported is returned by calls to path based endpoints when the client does not implement MoneroOtherRPC
+var ErrOtherRPCNotSupported = errors.New("client does not support monerod path based endpoints")
+
+// MoneroBinaryRPC is implemented by clients which can call monerod's .bin endpoints
+// that take and return epee portable storage bodies.
+type MoneroBinaryRPC interface {
+	DoBinary(ctx context.Context, path string, req interface{}, res interface{}) error
+}
+
+// ErrBinaryRPCNotSupported is returned by calls to .bin endpoints when the client does not implement MoneroBinaryRPC
+var ErrBinaryRPCNotSupported = errors.New("client does not support monerod binary endpoints")
+
+// MoneroRPCContext is implemented by clients which can bound a call with a context.
+// Clients that only implement MoneroRPC still work, but the context is only checked before the call is made.
+type MoneroRPCContext interface {
+	MoneroRPC
+	DoContext(ctx context.Context, method string, req interface{}, res interface{}) error
+}
+
 type daemon struct {
 	client MoneroRPC
 }
@@ -75,149 +174,418 @@ func New(client MoneroRPC) Daemon {
 	}
 }
 
+// do calls the RPC method through the context aware client when available
+func (d *daemon) do(ctx context.Context, method string, req interface{}, res interface{}) error {
+	if client, ok := d.client.(MoneroRPCContext); ok {
+		return client.DoContext(ctx, method, req, res)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return d.client.Do(method, req, res)
+}
+
+// doOther calls a path based endpoint through the client when it supports them
+func (d *daemon) doOther(ctx context.Context, path string, req interface{}, res interface{}) error {
+	client, ok := d.client.(MoneroOtherRPC)
+	if !ok {
+		return ErrOtherRPCNotSupported
+	}
+	return client.DoOther(ctx, path, req, res)
+}
+
+// doBinary calls a .bin endpoint through the client when it supports them
+func (d *daemon) doBinary(ctx context.Context, path string, req interface{}, res interface{}) error {
+	client, ok := d.client.(MoneroBinaryRPC)
+	if !ok {
+		return ErrBinaryRPCNotSupported
+	}
+	return client.DoBinary(ctx, path, req, res)
+}
+
 func (d *daemon) GenerateBlocks(req *GenerateBlocksRequest) (*GenerateBlocksResponse, error) {
+	return d.GenerateBlocksContext(context.Background(), req)
+}
+
+func (d *daemon) GenerateBlocksContext(ctx context.Context, req *GenerateBlocksRequest) (*GenerateBlocksResponse, error) {
 	res := new(GenerateBlocksResponse)
-	err := d.client.Do("generateblocks", req, res)
+	err := d.do(ctx, "generateblocks", req, res)
 	return res, err
 }
 
 func (d *daemon) GetBlockCount() (*GetBlockCountResponse, error) {
+	return d.GetBlockCountContext(context.Background())
+}
+
+func (d *daemon) GetBlockCountContext(ctx context.Context) (*GetBlockCountResponse, error) {
 	res := new(GetBlockCountResponse)
-	err := d.client.Do("get_block_count", nil, res)
+	err := d.do(ctx, "get_block_count", nil, res)
 	return res, err
 }
 
 func (d *daemon) OnGetBlockHash(req []uint64) (string, error) {
+	return d.OnGetBlockHashContext(context.Background(), req)
+}
+
+func (d *daemon) OnGetBlockHashContext(ctx context.Context, req []uint64) (string, error) {
 	var res string
-	err := d.client.Do("on_get_block_hash", req, &res)
+	err := d.do(ctx, "on_get_block_hash", req, &res)
 	return res, err
 }
 
 func (d *daemon) GetBlockTemplate(req *GetBlockTemplateRequest) (*GetBlockTemplateResponse, error) {
+	return d.GetBlockTemplateContext(context.Background(), req)
+}
+
+func (d *daemon) GetBlockTemplateContext(ctx context.Context, req *GetBlockTemplateRequest) (*GetBlockTemplateResponse, error) {
 	res := new(GetBlockTemplateResponse)
-	err := d.client.Do("get_block_template", req, res)
+	err := d.do(ctx, "get_block_template", req, res)
 	return res, err
 }
 
 func (d *daemon) SubmitBlock(req []string) (*SubmitBlockResponse, error) {
+	return d.SubmitBlockContext(context.Background(), req)
+}
+
+func (d *daemon) SubmitBlockContext(ctx context.Context, req []string) (*SubmitBlockResponse, error) {
 	res := new(SubmitBlockResponse)
-	err := d.client.Do("submit_block", &req, res)
+	err := d.do(ctx, "submit_block", &req, res)
 	return res, err
 }
 
 func (d *daemon) GetLastBlockHeader() (*GetLastBlockHeaderResponse, error) {
+	return d.GetLastBlockHeaderContext(context.Background())
+}
+
+func (d *daemon) GetLastBlockHeaderContext(ctx context.Context) (*GetLastBlockHeaderResponse, error) {
 	res := new(GetLastBlockHeaderResponse)
-	err := d.client.Do("get_last_block_header", nil, res)
+	err := d.do(ctx, "get_last_block_header", nil, res)
 	return res, err
 }
 
 func (d *daemon) GetBlockHeaderByHash(req *GetBlockHeaderByHashRequest) (*GetBlockHeaderByHashResponse, error) {
+	return d.GetBlockHeaderByHashContext(context.Background(), req)
+}
+
+func (d *daemon) GetBlockHeaderByHashContext(ctx context.Context, req *GetBlockHeaderByHashRequest) (*GetBlockHeaderByHashResponse, error) {
 	res := new(GetBlockHeaderByHashResponse)
-	err := d.client.Do("get_block_header_by_hash", req, res)
+	err := d.do(ctx, "get_block_header_by_hash", req, res)
 	return res, err
 }
 
 func (d *daemon) GetBlockHeaderByHeight(req *GetBlockHeaderByHeightRequest) (*GetBlockHeaderByHeightResponse, error) {
+	return d.GetBlockHeaderByHeightContext(context.Background(), req)
+}
+
+func (d *daemon) GetBlockHeaderByHeightContext(ctx context.Context, req *GetBlockHeaderByHeightRequest) (*GetBlockHeaderByHeightResponse, error) {
 	res := new(GetBlockHeaderByHeightResponse)
-	err := d.client.Do("get_block_header_by_height", req, res)
+	err := d.do(ctx, "get_block_header_by_height", req, res)
 	return res, err
 }
 
 func (d *daemon) GetBlockHeadersRange(req *GetBlockHeadersRangeRequest) (*GetBlockHeadersRangeResponse, error) {
+	return d.GetBlockHeadersRangeContext(context.Background(), req)
+}
+
+func (d *daemon) GetBlockHeadersRangeContext(ctx context.Context, req *GetBlockHeadersRangeRequest) (*GetBlockHeadersRangeResponse, error) {
 	res := new(GetBlockHeadersRangeResponse)
-	err := d.client.Do("get_block_headers_range", req, res)
+	err := d.do(ctx, "get_block_headers_range", req, res)
 	return res, err
 }
 
 func (d *daemon) GetBlock(req *GetBlockRequest) (*GetBlockResponse, error) {
+	return d.GetBlockContext(context.Background(), req)
+}
+
+func (d *daemon) GetBlockContext(ctx context.Context, req *GetBlockRequest) (*GetBlockResponse, error) {
 	res := new(GetBlockResponse)
-	err := d.client.Do("get_block", req, res)
+	err := d.do(ctx, "get_block", req, res)
 	return res, err
 }
 
 func (d *daemon) GetConnections() (*GetConnectionsResponse, error) {
+	return d.GetConnectionsContext(context.Background())
+}
+
+func (d *daemon) GetConnectionsContext(ctx context.Context) (*GetConnectionsResponse, error) {
 	res := new(GetConnectionsResponse)
-	err := d.client.Do("get_connections", nil, res)
+	err := d.do(ctx, "get_connections", nil, res)
 	return res, err
 }
 
 func (d *daemon) GetInfo() (*GetInfoResponse, error) {
+	return d.GetInfoContext(context.Background())
+}
+
+func (d *daemon) GetInfoContext(ctx context.Context) (*GetInfoResponse, error) {
 	res := new(GetInfoResponse)
-	err := d.client.Do("get_info", nil, res)
+	err := d.do(ctx, "get_info", nil, res)
 	return res, err
 }
 
 func (d *daemon) HardForkInfo() (*HardForkInfoResponse, error) {
+	return d.HardForkInfoContext(context.Background())
+}
+
+func (d *daemon) HardForkInfoContext(ctx context.Context) (*HardForkInfoResponse, error) {
 	res := new(HardForkInfoResponse)
-	err := d.client.Do("hard_fork_info", nil, res)
+	err := d.do(ctx, "hard_fork_info", nil, res)
 	return res, err
 }
 
 func (d *daemon) SetBans(req *SetBansRequest) error {
-	err := d.client.Do("set_bans", req, nil)
+	return d.SetBansContext(context.Background(), req)
+}
+
+func (d *daemon) SetBansContext(ctx context.Context, req *SetBansRequest) error {
+	err := d.do(ctx, "set_bans", req, nil)
 	return err
 }
 
 func (d *daemon) GetBans() (*GetBansResponse, error) {
+	return d.GetBansContext(context.Background())
+}
+
+func (d *daemon) GetBansContext(ctx context.Context) (*GetBansResponse, error) {
 	res := new(GetBansResponse)
-	err := d.client.Do("get_bans", nil, res)
+	err := d.do(ctx, "get_bans", nil, res)
 	return res, err
 }
 
 func (d *daemon) FlushTxpool(req *FlushTxpoolRequest) error {
-	err := d.client.Do("flush_txpool", req, nil)
+	return d.FlushTxpoolContext(context.Background(), req)
+}
+
+func (d *daemon) FlushTxpoolContext(ctx context.Context, req *FlushTxpoolRequest) error {
+	err := d.do(ctx, "flush_txpool", req, nil)
 	return err
 }
 
 func (d *daemon) GetOutputHistogram(req *GetOutputHistogramRequest) (*GetOutputHistogramResponse, error) {
+	return d.GetOutputHistogramContext(context.Background(), req)
+}
+
+func (d *daemon) GetOutputHistogramContext(ctx context.Context, req *GetOutputHistogramRequest) (*GetOutputHistogramResponse, error) {
 	res := new(GetOutputHistogramResponse)
-	err := d.client.Do("get_output_histogram", req, res)
+	err := d.do(ctx, "get_output_histogram", req, res)
 	return res, err
 }
 
 func (d *daemon) GetVersion() (*GetVersionResponse, error) {
+	return d.GetVersionContext(context.Background())
+}
+
+func (d *daemon) GetVersionContext(ctx context.Context) (*GetVersionResponse, error) {
 	res := new(GetVersionResponse)
-	err := d.client.Do("get_version", nil, res)
+	err := d.do(ctx, "get_version", nil, res)
 	return res, err
 }
 
 func (d *daemon) GetCoinbaseTxSum(req *GetCoinbaseTxSumRequest) (*GetCoinbaseTxSumResponse, error) {
+	return d.GetCoinbaseTxSumContext(context.Background(), req)
+}
+
+func (d *daemon) GetCoinbaseTxSumContext(ctx context.Context, req *GetCoinbaseTxSumRequest) (*GetCoinbaseTxSumResponse, error) {
 	res := new(GetCoinbaseTxSumResponse)
-	err := d.client.Do("get_coinbase_tx_sum", req, res)
+	err := d.do(ctx, "get_coinbase_tx_sum", req, res)
 	return res, err
 }
 
 func (d *daemon) GetFeeEstimate(req *GetFeeEstimateRequest) (*GetFeeEstimateResponse, error) {
+	return d.GetFeeEstimateContext(context.Background(), req)
+}
+
+func (d *daemon) GetFeeEstimateContext(ctx context.Context, req *GetFeeEstimateRequest) (*GetFeeEstimateResponse, error) {
 	res := new(GetFeeEstimateResponse)
-	err := d.client.Do("get_fee_estimate", req, res)
+	err := d.do(ctx, "get_fee_estimate", req, res)
 	return res, err
 }
 
 func (d *daemon) GetAlternateChains() (*GetAlternateChainsResponse, error) {
+	return d.GetAlternateChainsContext(context.Background())
+}
+
+func (d *daemon) GetAlternateChainsContext(ctx context.Context) (*GetAlternateChainsResponse, error) {
 	res := new(GetAlternateChainsResponse)
-	err := d.client.Do("get_alternate_chains", nil, res)
+	err := d.do(ctx, "get_alternate_chains", nil, res)
 	return res, err
 }
 
 func (d *daemon) RelayTx(req *RelayTxRequest) error {
-	err := d.client.Do("relay_tx", req, nil)
+	return d.RelayTxContext(context.Background(), req)
+}
+
+func (d *daemon) RelayTxContext(ctx context.Context, req *RelayTxRequest) error {
+	err := d.do(ctx, "relay_tx", req, nil)
 	return err
 }
 
 func (d *daemon) SyncInfo() (*SyncInfoResponse, error) {
+	return d.SyncInfoContext(context.Background())
+}
+
+func (d *daemon) SyncInfoContext(ctx context.Context) (*SyncInfoResponse, error) {
 	res := new(SyncInfoResponse)
-	err := d.client.Do("sync_info", nil, res)
+	err := d.do(ctx, "sync_info", nil, res)
 	return res, err
 }
 
 func (d *daemon) GetTxpoolBacklog() (*GetTxpoolBacklogResponse, error) {
+	return d.GetTxpoolBacklogContext(context.Background())
+}
+
+func (d *daemon) GetTxpoolBacklogContext(ctx context.Context) (*GetTxpoolBacklogResponse, error) {
 	res := new(GetTxpoolBacklogResponse)
-	err := d.client.Do("get_txpool_backlog", nil, res)
+	err := d.do(ctx, "get_txpool_backlog", nil, res)
 	return res, err
 }
 
 func (d *daemon) GetOutputDistribution(req *GetOutputDistributionRequest) (*GetOutputDistributionResponse, error) {
+	return d.GetOutputDistributionContext(context.Background(), req)
+}
+
+func (d *daemon) GetOutputDistributionContext(ctx context.Context, req *GetOutputDistributionRequest) (*GetOutputDistributionResponse, error) {
 	res := new(GetOutputDistributionResponse)
-	err := d.client.Do("get_output_distribution", req, res)
+	err := d.do(ctx, "get_output_distribution", req, res)
+	return res, err
+}
+
+func (d *daemon) GetHeight() (*GetHeightResponse, error) {
+	return d.GetHeightContext(context.Background())
+}
+
+func (d *daemon) GetHeightContext(ctx context.Context) (*GetHeightResponse, error) {
+	res := new(GetHeightResponse)
+	err := d.doOther(ctx, "/get_height", nil, res)
+	return res, err
+}
+
+func (d *daemon) GetTransactions(req *GetTransactionsRequest) (*GetTransactionsResponse, error) {
+	return d.GetTransactionsContext(context.Background(), req)
+}
+
+func (d *daemon) GetTransactionsContext(ctx context.Context, req *GetTransactionsRequest) (*GetTransactionsResponse, error) {
+	res := new(GetTransactionsResponse)
+	err := d.doOther(ctx, "/get_transactions", req, res)
+	return res, err
+}
+
+func (d *daemon) GetAltBlocksHashes() (*GetAltBlocksHashesResponse, error) {
+	return d.GetAltBlocksHashesContext(context.Background())
+}
+
+func (d *daemon) GetAltBlocksHashesContext(ctx context.Context) (*GetAltBlocksHashesResponse, error) {
+	res := new(GetAltBlocksHashesResponse)
+	err := d.doOther(ctx, "/get_alt_blocks_hashes", nil, res)
+	return res, err
+}
+
+func (d *daemon) IsKeyImageSpent(req *IsKeyImageSpentRequest) (*IsKeyImageSpentResponse, error) {
+	return d.IsKeyImageSpentContext(context.Background(), req)
+}
+
+func (d *daemon) IsKeyImageSpentContext(ctx context.Context, req *IsKeyImageSpentRequest) (*IsKeyImageSpentResponse, error) {
+	res := new(IsKeyImageSpentResponse)
+	err := d.doOther(ctx, "/is_key_image_spent", req, res)
+	return res, err
+}
+
+func (d *daemon) SendRawTransaction(req *SendRawTransactionRequest) (*SendRawTransactionResponse, error) {
+	return d.SendRawTransactionContext(context.Background(), req)
+}
+
+func (d *daemon) SendRawTransactionContext(ctx context.Context, req *SendRawTransactionRequest) (*SendRawTransactionResponse, error) {
+	res := new(SendRawTransactionResponse)
+	err := d.doOther(ctx, "/send_raw_transaction", req, res)
+	return res, err
+}
+
+func (d *daemon) GetTransactionPool() (*GetTransactionPoolResponse, error) {
+	return d.GetTransactionPoolContext(context.Background())
+}
+
+func (d *daemon) GetTransactionPoolContext(ctx context.Context) (*GetTransactionPoolResponse, error) {
+	res := new(GetTransactionPoolResponse)
+	err := d.doOther(ctx, "/get_transaction_pool", nil, res)
+	return res, err
+}
+
+func (d *daemon) GetTransactionPoolHashes() (*GetTransactionPoolHashesResponse, error) {
+	return d.GetTransactionPoolHashesContext(context.Background())
+}
+
+func (d *daemon) GetTransactionPoolHashesContext(ctx context.Context) (*GetTransactionPoolHashesResponse, error) {
+	res := new(GetTransactionPoolHashesResponse)
+	err := d.doOther(ctx, "/get_transaction_pool_hashes", nil, res)
+	return res, err
+}
+
+func (d *daemon) GetOuts(req *GetOutsRequest) (*GetOutsResponse, error) {
+	return d.GetOutsContext(context.Background(), req)
+}
+
+func (d *daemon) GetOutsContext(ctx context.Context, req *GetOutsRequest) (*GetOutsResponse, error) {
+	res := new(GetOutsResponse)
+	err := d.doOther(ctx, "/get_outs", req, res)
+	return res, err
+}
+
+func (d *daemon) GetBlocksBin(req *GetBlocksBinRequest) (*GetBlocksBinResponse, error) {
+	return d.GetBlocksBinContext(context.Background(), req)
+}
+
+func (d *daemon) GetBlocksBinContext(ctx context.Context, req *GetBlocksBinRequest) (*GetBlocksBinResponse, error) {
+	res := new(GetBlocksBinResponse)
+	err := d.doBinary(ctx, "/get_blocks.bin", req, res)
+	return res, err
+}
+
+func (d *daemon) GetBlocksByHeightBin(req *GetBlocksByHeightBinRequest) (*GetBlocksByHeightBinResponse, error) {
+	return d.GetBlocksByHeightBinContext(context.Background(), req)
+}
+
+func (d *daemon) GetBlocksByHeightBinContext(ctx context.Context, req *GetBlocksByHeightBinRequest) (*GetBlocksByHeightBinResponse, error) {
+	res := new(GetBlocksByHeightBinResponse)
+	err := d.doBinary(ctx, "/get_blocks_by_height.bin", req, res)
+	return res, err
+}
+
+func (d *daemon) GetHashesBin(req *GetHashesBinRequest) (*GetHashesBinResponse, error) {
+	return d.GetHashesBinContext(context.Background(), req)
+}
+
+func (d *daemon) GetHashesBinContext(ctx context.Context, req *GetHashesBinRequest) (*GetHashesBinResponse, error) {
+	res := new(GetHashesBinResponse)
+	err := d.doBinary(ctx, "/get_hashes.bin", req, res)
+	return res, err
+}
+
+func (d *daemon) GetOIndexesBin(req *GetOIndexesBinRequest) (*GetOIndexesBinResponse, error) {
+	return d.GetOIndexesBinContext(context.Background(), req)
+}
+
+func (d *daemon) GetOIndexesBinContext(ctx context.Context, req *GetOIndexesBinRequest) (*GetOIndexesBinResponse, error) {
+	res := new(GetOIndexesBinResponse)
+	err := d.doBinary(ctx, "/get_o_indexes.bin", req, res)
+	return res, err
+}
+
+func (d *daemon) GetOutsBin(req *GetOutsBinRequest) (*GetOutsBinResponse, error) {
+	return d.GetOutsBinContext(context.Background(), req)
+}
+
+func (d *daemon) GetOutsBinContext(ctx context.Context, req *GetOutsBinRequest) (*GetOutsBinResponse, error) {
+	res := new(GetOutsBinResponse)
+	err := d.doBinary(ctx, "/get_outs.bin", req, res)
+	return res, err
+}
+
+func (d *daemon) GetTransactionPoolHashesBin() (*GetTransactionPoolHashesBinResponse, error) {
+	return d.GetTransactionPoolHashesBinContext(context.Background())
+}
+
+func (d *daemon) GetTransactionPoolHashesBinContext(ctx context.Context) (*GetTransactionPoolHashesBinResponse, error) {
+	res := new(GetTransactionPoolHashesBinResponse)
+	err := d.doBinary(ctx, "/get_transaction_pool_hashes.bin", struct{}{}, res)
 	return res, err
 }
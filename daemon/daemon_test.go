@@ -2,12 +2,16 @@ package daemon
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	"github.com/MarinX/monerorpc/epee"
 	"github.com/gorilla/rpc/v2/json2"
 	"github.com/matryer/is"
 )
@@ -18,12 +22,16 @@ type MockMoneroRPC struct {
 }
 
 func (m *MockMoneroRPC) Do(method string, req interface{}, res interface{}) error {
+	return m.DoContext(context.Background(), method, req, res)
+}
+
+func (m *MockMoneroRPC) DoContext(ctx context.Context, method string, req interface{}, res interface{}) error {
 	buff, err := json2.EncodeClientRequest(method, req)
 	if err != nil {
 		return fmt.Errorf("error creating encoded request %v", err)
 	}
 
-	httpReq, err := http.NewRequest("POST", m.uri, bytes.NewReader(buff))
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", m.uri, bytes.NewReader(buff))
 	if err != nil {
 		return fmt.Errorf("error creating http request %v", err)
 	}
@@ -47,6 +55,44 @@ func (m *MockMoneroRPC) Do(method string, req interface{}, res interface{}) erro
 	return err
 }
 
+func (m *MockMoneroRPC) DoOther(ctx context.Context, path string, req interface{}, res interface{}) error {
+	buff, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", m.uri+path, bytes.NewReader(buff))
+	if err != nil {
+		return err
+	}
+	httpResp, err := m.client.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer httpResp.Body.Close()
+	return json.NewDecoder(httpResp.Body).Decode(res)
+}
+
+func (m *MockMoneroRPC) DoBinary(ctx context.Context, path string, req interface{}, res interface{}) error {
+	buff, err := epee.Marshal(req)
+	if err != nil {
+		return err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", m.uri+path, bytes.NewReader(buff))
+	if err != nil {
+		return err
+	}
+	httpResp, err := m.client.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer httpResp.Body.Close()
+	body, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return err
+	}
+	return epee.Unmarshal(body, res)
+}
+
 func getClient(uri string, client *http.Client) *MockMoneroRPC {
 	return &MockMoneroRPC{
 		uri:    uri,
@@ -464,7 +510,8 @@ func TestDaemonGetBlock(t *testing.T) {
 	is.Equal(res.BlockHeader.PrevHash, "b61c58b2e0be53fad5ef9d9731a55e8a81d972b8d90ed07c04fd37ca6403ff78")
 	is.Equal(res.BlockHeader.Reward, uint64(7388968946286))
 	is.Equal(res.BlockHeader.Timestamp, uint64(1452793716))
-
+	is.Equal(res.MinerTxHash, "c7da3965f25c19b8eb7dd8db48dcd4e7c885e2491db77e289f0609bf8e08ec30")
+	is.Equal(len(res.TxHashes), 0)
 }
 
 func TestDaemonGetConnections(t *testing.T) {
@@ -1090,3 +1137,349 @@ func TestDaemonGenerateBlocks(t *testing.T) {
 		Untrusted: false,
 	})
 }
+
+func TestDaemonGetBlockCountContext(t *testing.T) {
+	output := `{
+		"id": "0",
+		"jsonrpc": "2.0",
+		"result": {
+		  "count": 993163,
+		  "status": "OK",
+		  "untrusted": false
+		}
+	  }`
+	server := setupServer(t, "get_block_count", output)
+	defer server.Close()
+
+	w := New(getClient(server.URL, server.Client()))
+
+	res, err := w.GetBlockCountContext(context.Background())
+	if err != nil {
+		t.Error(err)
+	}
+	is.New(t).Equal(res.Count, uint64(993163))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = w.GetBlockCountContext(ctx)
+	is.New(t).True(errors.Is(err, context.Canceled))
+}
+
+type legacyMoneroRPC struct {
+	calls int
+}
+
+func (l *legacyMoneroRPC) Do(method string, req interface{}, res interface{}) error {
+	l.calls++
+	return nil
+}
+
+func TestDaemonContextLegacyClient(t *testing.T) {
+	client := &legacyMoneroRPC{}
+	w := New(client)
+
+	_, err := w.GetInfoContext(context.Background())
+	is.New(t).NoErr(err)
+	is.New(t).Equal(client.calls, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = w.GetInfoContext(ctx)
+	is.New(t).True(errors.Is(err, context.Canceled))
+	is.New(t).Equal(client.calls, 1)
+}
+
+func setupOtherServer(t *testing.T, path string, output string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != path {
+			t.Errorf("unexpected path %s, expected %s", req.URL.Path, path)
+		}
+		buff, _ := io.ReadAll(req.Body)
+		t.Log(string(buff))
+		rw.Write([]byte(output))
+	}))
+	return server
+}
+
+func TestDaemonGetHeight(t *testing.T) {
+	output := `{
+		"hash": "7e23a28cfa6df925d5b63940baf60b83c0cbb65da95f49b19e7cf0ce7dd709ce",
+		"height": 2287217,
+		"status": "OK",
+		"untrusted": false
+	  }`
+	server := setupOtherServer(t, "/get_height", output)
+	defer server.Close()
+
+	w := New(getClient(server.URL, server.Client()))
+
+	res, err := w.GetHeight()
+	if err != nil {
+		t.Error(err)
+	}
+	is.New(t).Equal(res, &GetHeightResponse{
+		Hash:   "7e23a28cfa6df925d5b63940baf60b83c0cbb65da95f49b19e7cf0ce7dd709ce",
+		Height: 2287217,
+	})
+}
+
+func TestDaemonGetTransactions(t *testing.T) {
+	output := `{
+		"credits": 0,
+		"status": "OK",
+		"top_hash": "",
+		"txs": [{
+		  "as_hex": "",
+		  "as_json": "",
+		  "block_height": 993442,
+		  "block_timestamp": 1457749396,
+		  "double_spend_seen": false,
+		  "in_pool": false,
+		  "output_indices": [198769,418598,176616,50345,509],
+		  "prunable_as_hex": "",
+		  "prunable_hash": "0000000000000000000000000000000000000000000000000000000000000000",
+		  "pruned_as_hex": "",
+		  "tx_hash": "d6e48158472848e6687173a91ae6eebfa3e1d778e65252ee99d7515d63090408"
+		}],
+		"txs_as_hex": [""],
+		"untrusted": false
+	  }`
+	server := setupOtherServer(t, "/get_transactions", output)
+	defer server.Close()
+
+	w := New(getClient(server.URL, server.Client()))
+
+	res, err := w.GetTransactions(&GetTransactionsRequest{
+		TxsHashes: []string{"d6e48158472848e6687173a91ae6eebfa3e1d778e65252ee99d7515d63090408"},
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	is.New(t).Equal(res, &GetTransactionsResponse{
+		Txs: []Transaction{
+			{
+				BlockHeight:    993442,
+				BlockTimestamp: 1457749396,
+				OutputIndices:  []uint64{198769, 418598, 176616, 50345, 509},
+				PrunableHash:   "0000000000000000000000000000000000000000000000000000000000000000",
+				TxHash:         "d6e48158472848e6687173a91ae6eebfa3e1d778e65252ee99d7515d63090408",
+			},
+		},
+		TxsAsHex: []string{""},
+	})
+}
+
+func TestDaemonIsKeyImageSpent(t *testing.T) {
+	output := `{
+		"credits": 0,
+		"spent_status": [1,1],
+		"status": "OK",
+		"top_hash": "",
+		"untrusted": false
+	  }`
+	server := setupOtherServer(t, "/is_key_image_spent", output)
+	defer server.Close()
+
+	w := New(getClient(server.URL, server.Client()))
+
+	res, err := w.IsKeyImageSpent(&IsKeyImageSpentRequest{
+		KeyImages: []string{
+			"8d1bd8181bf7d857bdb281e0153d84cd55a3fcaa57c3e570f4a49f935850b5e3",
+			"7319134bfc50668251f5b899c66b005805ee255c136f0e1cecbb0f3a912e09d4",
+		},
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	is.New(t).Equal(res, &IsKeyImageSpentResponse{
+		SpentStatus: []uint64{KeyImageSpentInChain, KeyImageSpentInChain},
+	})
+}
+
+func TestDaemonSendRawTransaction(t *testing.T) {
+	output := `{
+		"double_spend": false,
+		"fee_too_low": false,
+		"invalid_input": false,
+		"invalid_output": false,
+		"low_mixin": false,
+		"not_relayed": false,
+		"overspend": false,
+		"reason": "",
+		"status": "OK",
+		"too_big": false,
+		"untrusted": false
+	  }`
+	server := setupOtherServer(t, "/send_raw_transaction", output)
+	defer server.Close()
+
+	w := New(getClient(server.URL, server.Client()))
+
+	res, err := w.SendRawTransaction(&SendRawTransactionRequest{TxAsHex: "de6a3..."})
+	if err != nil {
+		t.Error(err)
+	}
+	is.New(t).Equal(res, &SendRawTransactionResponse{})
+}
+
+func TestDaemonGetTransactionPoolHashes(t *testing.T) {
+	output := `{
+		"credits": 0,
+		"status": "OK",
+		"top_hash": "",
+		"tx_hashes": ["aaf7c6f9af0e5a2d1ac62df34ee76be6e04ac1e2a2a0c2aea1ad40339c8b6bc2"],
+		"untrusted": false
+	  }`
+	server := setupOtherServer(t, "/get_transaction_pool_hashes", output)
+	defer server.Close()
+
+	w := New(getClient(server.URL, server.Client()))
+
+	res, err := w.GetTransactionPoolHashes()
+	if err != nil {
+		t.Error(err)
+	}
+	is.New(t).Equal(res, &GetTransactionPoolHashesResponse{
+		TxHashes: []string{"aaf7c6f9af0e5a2d1ac62df34ee76be6e04ac1e2a2a0c2aea1ad40339c8b6bc2"},
+	})
+}
+
+func TestDaemonGetOuts(t *testing.T) {
+	output := `{
+		"credits": 0,
+		"outs": [{
+		  "height": 1222460,
+		  "key": "9c7055cb5b790f1eebb88025d4d77d3c9eedc37f1f2a2bc78b46e2d397c31a2d",
+		  "mask": "2b0d5bd8c8c6fd5e0bd4ec3f8db1e4e1ad04a41c34a6ecab1a1c14b57e3b1d09",
+		  "txid": "",
+		  "unlocked": true
+		}],
+		"status": "OK",
+		"top_hash": "",
+		"untrusted": false
+	  }`
+	server := setupOtherServer(t, "/get_outs", output)
+	defer server.Close()
+
+	w := New(getClient(server.URL, server.Client()))
+
+	res, err := w.GetOuts(&GetOutsRequest{Outputs: []OutRequest{{Index: 1}}})
+	if err != nil {
+		t.Error(err)
+	}
+	is.New(t).Equal(res, &GetOutsResponse{
+		Outs: []Out{
+			{
+				Height:   1222460,
+				Key:      "9c7055cb5b790f1eebb88025d4d77d3c9eedc37f1f2a2bc78b46e2d397c31a2d",
+				Mask:     "2b0d5bd8c8c6fd5e0bd4ec3f8db1e4e1ad04a41c34a6ecab1a1c14b57e3b1d09",
+				Unlocked: true,
+			},
+		},
+	})
+}
+
+func TestDaemonOtherRPCNotSupported(t *testing.T) {
+	w := New(&legacyMoneroRPC{})
+
+	_, err := w.GetHeight()
+	is.New(t).True(errors.Is(err, ErrOtherRPCNotSupported))
+}
+
+func setupBinaryServer(t *testing.T, path string, output interface{}) *httptest.Server {
+	buff, err := epee.Marshal(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != path {
+			t.Errorf("unexpected path %s, expected %s", req.URL.Path, path)
+		}
+		body, _ := io.ReadAll(req.Body)
+		if _, err := epee.Decode(body); err != nil {
+			t.Error(err)
+		}
+		rw.Write(buff)
+	}))
+	return server
+}
+
+func TestDaemonGetBlocksBin(t *testing.T) {
+	output := map[string]interface{}{
+		"blocks": []map[string]interface{}{
+			{"block": []byte{1, 2, 3}, "txs": []string{"tx1", "tx2"}},
+			{"pruned": true, "block": []byte{4}, "block_weight": uint64(100), "txs": []TxBlobEntry{{Blob: []byte("tx3"), PrunableHash: [32]byte{9}}}},
+		},
+		"start_height":   uint64(10),
+		"current_height": uint64(12),
+		"output_indices": []BlockOutputIndices{{Indices: []TxOutputIndices{{Indices: []uint64{1, 2}}}}},
+		"status":         "OK",
+	}
+	server := setupBinaryServer(t, "/get_blocks.bin", output)
+	defer server.Close()
+
+	w := New(getClient(server.URL, server.Client()))
+
+	res, err := w.GetBlocksBin(&GetBlocksBinRequest{StartHeight: 10, BlockIDs: [][32]byte{{1}}})
+	if err != nil {
+		t.Error(err)
+	}
+	is.New(t).Equal(res, &GetBlocksBinResponse{
+		Blocks: []BlockCompleteEntry{
+			{Block: []byte{1, 2, 3}, Txs: []TxBlobEntry{{Blob: []byte("tx1")}, {Blob: []byte("tx2")}}},
+			{Pruned: true, Block: []byte{4}, BlockWeight: 100, Txs: []TxBlobEntry{{Blob: []byte("tx3"), PrunableHash: [32]byte{9}}}},
+		},
+		StartHeight:   10,
+		CurrentHeight: 12,
+		OutputIndices: []BlockOutputIndices{{Indices: []TxOutputIndices{{Indices: []uint64{1, 2}}}}},
+	})
+}
+
+func TestDaemonGetOutsBin(t *testing.T) {
+	outs := []OutKey{{Key: [32]byte{1}, Mask: [32]byte{2}, Unlocked: true, Height: 1234, TxID: [32]byte{3}}}
+	blob := make([]byte, 0, 105)
+	blob = append(blob, outs[0].Key[:]...)
+	blob = append(blob, outs[0].Mask[:]...)
+	blob = append(blob, 1, 0xd2, 0x04, 0, 0, 0, 0, 0, 0)
+	blob = append(blob, outs[0].TxID[:]...)
+	server := setupBinaryServer(t, "/get_outs.bin", map[string]interface{}{
+		"outs":   blob,
+		"status": "OK",
+	})
+	defer server.Close()
+
+	w := New(getClient(server.URL, server.Client()))
+
+	res, err := w.GetOutsBin(&GetOutsBinRequest{Outputs: []GetOutputsOut{{Index: 1}}, GetTxID: true})
+	if err != nil {
+		t.Error(err)
+	}
+	is.New(t).Equal(res, &GetOutsBinResponse{Outs: outs})
+}
+
+func TestDaemonGetHashesBin(t *testing.T) {
+	server := setupBinaryServer(t, "/get_hashes.bin", map[string]interface{}{
+		"m_block_ids":    append(bytes.Repeat([]byte{1}, 32), bytes.Repeat([]byte{2}, 32)...),
+		"start_height":   uint64(5),
+		"current_height": uint64(7),
+		"status":         "OK",
+	})
+	defer server.Close()
+
+	w := New(getClient(server.URL, server.Client()))
+
+	res, err := w.GetHashesBin(&GetHashesBinRequest{StartHeight: 5})
+	if err != nil {
+		t.Error(err)
+	}
+	is.New(t).Equal(len(res.BlockIDs), 2)
+	is.New(t).Equal(res.BlockIDs[1][0], byte(2))
+	is.New(t).Equal(res.CurrentHeight, uint64(7))
+}
+
+func TestDaemonBinaryRPCNotSupported(t *testing.T) {
+	w := New(&legacyMoneroRPC{})
+
+	_, err := w.GetTransactionPoolHashesBin()
+	is.New(t).True(errors.Is(err, ErrBinaryRPCNotSupported))
+}
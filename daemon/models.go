@@ -133,8 +133,12 @@ type GetBlockResponse struct {
 	// A structure containing block header information
 	BlockHeader BlockHeader `json:"block_header"`
 	// SON formatted block details
-	JSON      string `json:"json"`
-	Untrusted bool   `json:"untrusted"`
+	JSON string `json:"json"`
+	// The hash of the coinbase transaction of the block.
+	MinerTxHash string `json:"miner_tx_hash"`
+	// List of hashes of the non-coinbase transactions in the block.
+	TxHashes  []string `json:"tx_hashes"`
+	Untrusted bool     `json:"untrusted"`
 }
 
 // Connection model
@@ -506,3 +510,238 @@ type GenerateBlocksResponse struct {
 	Height    uint64   `json:"height"`
 	Untrusted bool     `json:"untrusted"`
 }
+
+// GetHeightResponse represents the response model for GetHeight
+type GetHeightResponse struct {
+	// Hash of the block at the current height.
+	Hash string `json:"hash"`
+	// The current blockchain height according to the queried daemon.
+	Height uint64 `json:"height"`
+	// States if the result is obtained using the bootstrap mode, and is therefore not trusted (true), or when the daemon is fully synced (false).
+	Untrusted bool `json:"untrusted"`
+}
+
+// GetTransactionsRequest represents the request model for GetTransactions
+type GetTransactionsRequest struct {
+	// List of transaction hashes to look up.
+	TxsHashes []string `json:"txs_hashes"`
+	// Optional (false by default). If set true, the returned transaction information will be decoded rather than binary.
+	DecodeAsJSON bool `json:"decode_as_json,omitempty"`
+	// Optional (false by default).
+	Prune bool `json:"prune,omitempty"`
+	// Optional (false by default).
+	Split bool `json:"split,omitempty"`
+}
+
+// Transaction model
+type Transaction struct {
+	// Full transaction information as a hex string.
+	AsHex string `json:"as_hex"`
+	// List of transaction info.
+	AsJSON string `json:"as_json"`
+	// block height including the transaction
+	BlockHeight uint64 `json:"block_height"`
+	// Unix time at chich the block has been added to the blockchain
+	BlockTimestamp uint64 `json:"block_timestamp"`
+	// Number of confirmations of the transaction.
+	Confirmations uint64 `json:"confirmations"`
+	// States if the transaction is a double-spend (true) or not (false)
+	DoubleSpendSeen bool `json:"double_spend_seen"`
+	// States if the transaction is in pool (true) or included in a block (false)
+	InPool bool `json:"in_pool"`
+	// transaction indexes
+	OutputIndices []uint64 `json:"output_indices"`
+	// Prunable part of the transaction as a hex string.
+	PrunableAsHex string `json:"prunable_as_hex"`
+	// Hash of the prunable part of the transaction.
+	PrunableHash string `json:"prunable_hash"`
+	// Pruned part of the transaction as a hex string.
+	PrunedAsHex string `json:"pruned_as_hex"`
+	// transaction hash
+	TxHash string `json:"tx_hash"`
+}
+
+// GetTransactionsResponse represents the response model for GetTransactions
+type GetTransactionsResponse struct {
+	// (Optional - returned if not empty) Transaction hashes that could not be found.
+	MissedTx []string `json:"missed_tx"`
+	// array of structure entry as follows:
+	Txs []Transaction `json:"txs"`
+	// Full transaction information as a hex strings (old compatibility parameter)
+	TxsAsHex []string `json:"txs_as_hex"`
+	// Transactions decoded as json (old compatibility parameter)
+	TxsAsJSON []string `json:"txs_as_json"`
+	// States if the result is obtained using the bootstrap mode, and is therefore not trusted (true), or when the daemon is fully synced (false).
+	Untrusted bool `json:"untrusted"`
+}
+
+// GetAltBlocksHashesResponse represents the response model for GetAltBlocksHashes
+type GetAltBlocksHashesResponse struct {
+	// list of alternative blocks hashes to main chain
+	BlksHashes []string `json:"blks_hashes"`
+	// States if the result is obtained using the bootstrap mode, and is therefore not trusted (true), or when the daemon is fully synced (false).
+	Untrusted bool `json:"untrusted"`
+}
+
+// IsKeyImageSpentRequest represents the request model for IsKeyImageSpent
+type IsKeyImageSpentRequest struct {
+	// List of key image hex strings to check.
+	KeyImages []string `json:"key_images"`
+}
+
+// Key image spent status, as returned in IsKeyImageSpentResponse.SpentStatus
+const (
+	KeyImageUnspent       = 0
+	KeyImageSpentInChain  = 1
+	KeyImageSpentInTxpool = 2
+)
+
+// IsKeyImageSpentResponse represents the response model for IsKeyImageSpent
+type IsKeyImageSpentResponse struct {
+	// List of statuses for each image checked. Statuses are follows: 0 = unspent, 1 = spent in blockchain, 2 = spent in transaction pool
+	SpentStatus []uint64 `json:"spent_status"`
+	// States if the result is obtained using the bootstrap mode, and is therefore not trusted (true), or when the daemon is fully synced (false).
+	Untrusted bool `json:"untrusted"`
+}
+
+// SendRawTransactionRequest represents the request model for SendRawTransaction
+type SendRawTransactionRequest struct {
+	// Full transaction information as hexidecimal string.
+	TxAsHex string `json:"tx_as_hex"`
+	// Stop relaying transaction to other nodes (default is false).
+	DoNotRelay bool `json:"do_not_relay,omitempty"`
+	// Verify transaction's fee and outputs before relaying.
+	DoSanityChecks bool `json:"do_sanity_checks,omitempty"`
+}
+
+// SendRawTransactionResponse represents the response model for SendRawTransaction
+type SendRawTransactionResponse struct {
+	// Transaction is a double spend (true) or not (false).
+	DoubleSpend bool `json:"double_spend"`
+	// Fee is too low (true) or OK (false).
+	FeeTooLow bool `json:"fee_too_low"`
+	// Input is invalid (true) or valid (false).
+	InvalidInput bool `json:"invalid_input"`
+	// Output is invalid (true) or valid (false).
+	InvalidOutput bool `json:"invalid_output"`
+	// Mixin count is too low (true) or OK (false).
+	LowMixin bool `json:"low_mixin"`
+	// Unlock time is set (true) or not (false).
+	NonzeroUnlockTime bool `json:"nonzero_unlock_time"`
+	// Transaction was not relayed (true) or relayed (false).
+	NotRelayed bool `json:"not_relayed"`
+	// Transaction uses more money than available (true) or not (false).
+	Overspend bool `json:"overspend"`
+	// Additional information. Currently empty or "Not relayed" if transaction was accepted but not relayed.
+	Reason string `json:"reason"`
+	// Transaction failed the sanity checks (true) or not (false).
+	SanityCheckFailed bool `json:"sanity_check_failed"`
+	// Transaction size is too big (true) or OK (false).
+	TooBig bool `json:"too_big"`
+	// Transaction has too few outputs (true) or not (false).
+	TooFewOutputs bool `json:"too_few_outputs"`
+	// Transaction extra is too big (true) or not (false).
+	TxExtraTooBig bool `json:"tx_extra_too_big"`
+	// States if the result is obtained using the bootstrap mode, and is therefore not trusted (true), or when the daemon is fully synced (false).
+	Untrusted bool `json:"untrusted"`
+}
+
+// SpentKeyImage model
+type SpentKeyImage struct {
+	// Key image.
+	IDHash string `json:"id_hash"`
+	// tx hashes of the txes (usually one) spending that key image.
+	TxsHashes []string `json:"txs_hashes"`
+}
+
+// PoolTransaction model
+type PoolTransaction struct {
+	// The size of the full transaction blob.
+	BlobSize uint64 `json:"blob_size"`
+	// States if this transaction should not be relayed
+	DoNotRelay bool `json:"do_not_relay"`
+	// States if this transaction has been seen as double spend.
+	DoubleSpendSeen bool `json:"double_spend_seen"`
+	// The amount of the mining fee included in the transaction, in atomic units.
+	Fee uint64 `json:"fee"`
+	// The transaction ID hash.
+	IDHash string `json:"id_hash"`
+	// States if the tx was included in a block at least once (true) or not (false).
+	KeptByBlock bool `json:"kept_by_block"`
+	// If the transaction validation has previously failed, this tells at what height that occured.
+	LastFailedHeight uint64 `json:"last_failed_height"`
+	// Like the previous, this tells the previous transaction ID hash.
+	LastFailedIDHash string `json:"last_failed_id_hash"`
+	// Last unix time at which the transaction has been relayed.
+	LastRelayedTime uint64 `json:"last_relayed_time"`
+	// Tells the height of the most recent block with an output used in this transaction.
+	MaxUsedBlockHeight uint64 `json:"max_used_block_height"`
+	// Tells the hash of the most recent block with an output used in this transaction.
+	MaxUsedBlockIDHash string `json:"max_used_block_id_hash"`
+	// The Unix time that the transaction was first seen on the network by the node.
+	ReceiveTime uint64 `json:"receive_time"`
+	// States if this transaction has been relayed
+	Relayed bool `json:"relayed"`
+	// Hexadecimal blob represnting the transaction.
+	TxBlob string `json:"tx_blob"`
+	// JSON structure of all information in the transaction.
+	TxJSON string `json:"tx_json"`
+	// Transaction weight.
+	Weight uint64 `json:"weight"`
+}
+
+// GetTransactionPoolResponse represents the response model for GetTransactionPool
+type GetTransactionPoolResponse struct {
+	// List of spent output key images.
+	SpentKeyImages []SpentKeyImage `json:"spent_key_images"`
+	// List of transactions in the mempool are not in a block on the main chain at the moment.
+	Transactions []PoolTransaction `json:"transactions"`
+	// States if the result is obtained using the bootstrap mode, and is therefore not trusted (true), or when the daemon is fully synced (false).
+	Untrusted bool `json:"untrusted"`
+}
+
+// GetTransactionPoolHashesResponse represents the response model for GetTransactionPoolHashes
+type GetTransactionPoolHashesResponse struct {
+	// List of transaction hashes.
+	TxHashes []string `json:"tx_hashes"`
+	// States if the result is obtained using the bootstrap mode, and is therefore not trusted (true), or when the daemon is fully synced (false).
+	Untrusted bool `json:"untrusted"`
+}
+
+// OutRequest model
+type OutRequest struct {
+	// Amount of the output, 0 for RingCT outputs.
+	Amount uint64 `json:"amount"`
+	// Global index of the output for that amount.
+	Index uint64 `json:"index"`
+}
+
+// GetOutsRequest represents the request model for GetOuts
+type GetOutsRequest struct {
+	// Array of outputs to look up.
+	Outputs []OutRequest `json:"outputs"`
+	// If true, a txid will included for each output in the response.
+	GetTxID bool `json:"get_txid,omitempty"`
+}
+
+// Out model
+type Out struct {
+	// Block height of the output
+	Height uint64 `json:"height"`
+	// The public key of the output
+	Key string `json:"key"`
+	// The RingCT commitment of the output
+	Mask string `json:"mask"`
+	// Transaction id
+	TxID string `json:"txid"`
+	// States if output is locked (false) or not (true)
+	Unlocked bool `json:"unlocked"`
+}
+
+// GetOutsResponse represents the response model for GetOuts
+type GetOutsResponse struct {
+	// List of outputs
+	Outs []Out `json:"outs"`
+	// States if the result is obtained using the bootstrap mode, and is therefore not trusted (true), or when the daemon is fully synced (false).
+	Untrusted bool `json:"untrusted"`
+}
@@ -0,0 +1,186 @@
+package daemon
+
+import "github.com/MarinX/monerorpc/epee"
+
+// Hashes and keys in binary requests and responses are raw 32 byte values, not hex strings.
+
+// Requested info values for GetBlocksBinRequest
+const (
+	BlocksOnly    = 0
+	BlocksAndPool = 1
+	PoolOnly      = 2
+)
+
+// GetBlocksBinRequest represents the request model for GetBlocksBin
+type GetBlocksBinRequest struct {
+	// What to return: 0 blocks only, 1 blocks and pool info, 2 pool info only.
+	RequestedInfo uint8 `epee:"requested_info,omitempty"`
+	// First 10 blocks id goes sequential, next goes in pow(2,n) offset, like 2, 4, 8, 16, 32, 64 and so on, and the last one is always genesis block.
+	BlockIDs [][32]byte `epee:"block_ids,blob"`
+	// Height of the first block to return when none of BlockIDs is known.
+	StartHeight uint64 `epee:"start_height"`
+	// Return pruned transactions.
+	Prune bool `epee:"prune"`
+	// Do not return the miner transaction.
+	NoMinerTx bool `epee:"no_miner_tx,omitempty"`
+	// Return pool changes since this time.
+	PoolInfoSince uint64 `epee:"pool_info_since,omitempty"`
+}
+
+// TxBlobEntry model
+type TxBlobEntry struct {
+	// Binary transaction, pruned if the block was requested pruned.
+	Blob []byte `epee:"blob"`
+	// Hash of the prunable part of the transaction.
+	PrunableHash [32]byte `epee:"prunable_hash"`
+}
+
+// UnmarshalEpee decodes a transaction entry, which monerod sends as a bare blob for unpruned blocks
+func (t *TxBlobEntry) UnmarshalEpee(v interface{}) error {
+	switch v := v.(type) {
+	case string:
+		t.Blob = []byte(v)
+		return nil
+	case epee.Section:
+		if blob, ok := v["blob"].(string); ok {
+			t.Blob = []byte(blob)
+		}
+		if hash, ok := v["prunable_hash"].(string); ok && len(hash) == len(t.PrunableHash) {
+			copy(t.PrunableHash[:], hash)
+		}
+		return nil
+	}
+	return &epee.TypeError{Field: "txs", Value: v, Type: "daemon.TxBlobEntry"}
+}
+
+// BlockCompleteEntry model
+type BlockCompleteEntry struct {
+	// States if the transactions are pruned.
+	Pruned bool `epee:"pruned"`
+	// Binary block.
+	Block []byte `epee:"block"`
+	// Block weight, only set for pruned blocks.
+	BlockWeight uint64 `epee:"block_weight"`
+	// Transactions of the block, not counting the miner transaction.
+	Txs []TxBlobEntry `epee:"txs"`
+}
+
+// TxOutputIndices model
+type TxOutputIndices struct {
+	// Global output indices of the transaction outputs.
+	Indices []uint64 `epee:"indices"`
+}
+
+// BlockOutputIndices model
+type BlockOutputIndices struct {
+	// Output indices of each transaction in the block, miner transaction first.
+	Indices []TxOutputIndices `epee:"indices"`
+}
+
+// GetBlocksBinResponse represents the response model for GetBlocksBin
+type GetBlocksBinResponse struct {
+	// Array of block complete entries.
+	Blocks []BlockCompleteEntry `epee:"blocks"`
+	// The starting block's height.
+	StartHeight uint64 `epee:"start_height"`
+	// The current block height.
+	CurrentHeight uint64 `epee:"current_height"`
+	// Output indices for each block.
+	OutputIndices []BlockOutputIndices `epee:"output_indices"`
+	// States if the result is obtained using the bootstrap mode, and is therefore not trusted (true), or when the daemon is fully synced (false).
+	Untrusted bool `epee:"untrusted"`
+}
+
+// GetBlocksByHeightBinRequest represents the request model for GetBlocksByHeightBin
+type GetBlocksByHeightBinRequest struct {
+	// List of block heights.
+	Heights []uint64 `epee:"heights"`
+}
+
+// GetBlocksByHeightBinResponse represents the response model for GetBlocksByHeightBin
+type GetBlocksByHeightBinResponse struct {
+	// Array of block complete entries.
+	Blocks []BlockCompleteEntry `epee:"blocks"`
+	// States if the result is obtained using the bootstrap mode, and is therefore not trusted (true), or when the daemon is fully synced (false).
+	Untrusted bool `epee:"untrusted"`
+}
+
+// GetHashesBinRequest represents the request model for GetHashesBin
+type GetHashesBinRequest struct {
+	// First 10 blocks id goes sequential, next goes in pow(2,n) offset, like 2, 4, 8, 16, 32, 64 and so on, and the last one is always genesis block.
+	BlockIDs [][32]byte `epee:"block_ids,blob"`
+	// Height of the first block to return when none of BlockIDs is known.
+	StartHeight uint64 `epee:"start_height"`
+}
+
+// GetHashesBinResponse represents the response model for GetHashesBin
+type GetHashesBinResponse struct {
+	// Block hashes.
+	BlockIDs [][32]byte `epee:"m_block_ids,blob"`
+	// The starting block's height.
+	StartHeight uint64 `epee:"start_height"`
+	// The current block height.
+	CurrentHeight uint64 `epee:"current_height"`
+	// States if the result is obtained using the bootstrap mode, and is therefore not trusted (true), or when the daemon is fully synced (false).
+	Untrusted bool `epee:"untrusted"`
+}
+
+// GetOIndexesBinRequest represents the request model for GetOIndexesBin
+type GetOIndexesBinRequest struct {
+	// Transaction hash.
+	TxID [32]byte `epee:"txid"`
+}
+
+// GetOIndexesBinResponse represents the response model for GetOIndexesBin
+type GetOIndexesBinResponse struct {
+	// List of global output indices of the transaction.
+	OIndexes []uint64 `epee:"o_indexes"`
+	// States if the result is obtained using the bootstrap mode, and is therefore not trusted (true), or when the daemon is fully synced (false).
+	Untrusted bool `epee:"untrusted"`
+}
+
+// GetOutputsOut model
+type GetOutputsOut struct {
+	// Amount of the output, 0 for RingCT outputs.
+	Amount uint64 `epee:"amount"`
+	// Global index of the output for that amount.
+	Index uint64 `epee:"index"`
+}
+
+// GetOutsBinRequest represents the request model for GetOutsBin
+type GetOutsBinRequest struct {
+	// Array of outputs to look up.
+	Outputs []GetOutputsOut `epee:"outputs"`
+	// If true, a txid will included for each output in the response.
+	GetTxID bool `epee:"get_txid"`
+}
+
+// OutKey model, packed exactly as monerod's outkey structure
+type OutKey struct {
+	// The public key of the output.
+	Key [32]byte
+	// The RingCT commitment of the output.
+	Mask [32]byte
+	// States if output is locked (false) or not (true).
+	Unlocked bool
+	// Block height of the output.
+	Height uint64
+	// Transaction id, only set when GetTxID was requested.
+	TxID [32]byte
+}
+
+// GetOutsBinResponse represents the response model for GetOutsBin
+type GetOutsBinResponse struct {
+	// List of outputs.
+	Outs []OutKey `epee:"outs,blob"`
+	// States if the result is obtained using the bootstrap mode, and is therefore not trusted (true), or when the daemon is fully synced (false).
+	Untrusted bool `epee:"untrusted"`
+}
+
+// GetTransactionPoolHashesBinResponse represents the response model for GetTransactionPoolHashesBin
+type GetTransactionPoolHashesBinResponse struct {
+	// List of transaction hashes.
+	TxHashes [][32]byte `epee:"tx_hashes,blob"`
+	// States if the result is obtained using the bootstrap mode, and is therefore not trusted (true), or when the daemon is fully synced (false).
+	Untrusted bool `epee:"untrusted"`
+}
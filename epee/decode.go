@@ -0,0 +1,417 @@
+package epee
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// Decode parses a portable storage payload into a generic Section
+func Decode(data []byte) (Section, error) {
+	d := &decoder{data: data}
+	if err := d.readHeader(); err != nil {
+		return nil, err
+	}
+	return d.readSection(0)
+}
+
+// Unmarshal parses a portable storage payload and stores the result in the value pointed to by v
+func Unmarshal(data []byte, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("epee: Unmarshal requires a non-nil pointer, got %T", v)
+	}
+	sec, err := Decode(data)
+	if err != nil {
+		return err
+	}
+	return assign(rv, sec, "")
+}
+
+type decoder struct {
+	data []byte
+	off  int
+}
+
+func (d *decoder) next(n int) ([]byte, error) {
+	if n < 0 || len(d.data)-d.off < n {
+		return nil, ErrUnexpectedEOF
+	}
+	b := d.data[d.off : d.off+n]
+	d.off += n
+	return b, nil
+}
+
+func (d *decoder) readHeader() error {
+	b, err := d.next(9)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if binary.LittleEndian.Uint32(b[0:4]) != SignatureA || binary.LittleEndian.Uint32(b[4:8]) != SignatureB || b[8] != Version {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func (d *decoder) readVarint() (uint64, error) {
+	if d.off >= len(d.data) {
+		return 0, ErrUnexpectedEOF
+	}
+	size := 1 << (d.data[d.off] & 3)
+	b, err := d.next(size)
+	if err != nil {
+		return 0, err
+	}
+	var buf [8]byte
+	copy(buf[:], b)
+	return binary.LittleEndian.Uint64(buf[:]) >> 2, nil
+}
+
+// readCount reads an element count and makes sure the remaining data could hold it
+func (d *decoder) readCount() (int, error) {
+	n, err := d.readVarint()
+	if err != nil {
+		return 0, err
+	}
+	if n > uint64(len(d.data)-d.off) {
+		return 0, ErrUnexpectedEOF
+	}
+	return int(n), nil
+}
+
+func (d *decoder) readSection(depth int) (Section, error) {
+	if depth > maxDepth {
+		return nil, fmt.Errorf("epee: maximum nesting depth exceeded")
+	}
+	n, err := d.readCount()
+	if err != nil {
+		return nil, err
+	}
+	sec := make(Section, n)
+	for i := 0; i < n; i++ {
+		l, err := d.next(1)
+		if err != nil {
+			return nil, err
+		}
+		name, err := d.next(int(l[0]))
+		if err != nil {
+			return nil, err
+		}
+		t, err := d.next(1)
+		if err != nil {
+			return nil, err
+		}
+		v, err := d.readEntry(t[0], depth)
+		if err != nil {
+			return nil, err
+		}
+		sec[string(name)] = v
+	}
+	return sec, nil
+}
+
+func (d *decoder) readEntry(t byte, depth int) (interface{}, error) {
+	if t&FlagArray != 0 {
+		return d.readArray(t&^FlagArray, depth+1)
+	}
+	return d.readRaw(t, depth)
+}
+
+func (d *decoder) readArray(t byte, depth int) (interface{}, error) {
+	if depth > maxDepth {
+		return nil, fmt.Errorf("epee: maximum nesting depth exceeded")
+	}
+	n, err := d.readCount()
+	if err != nil {
+		return nil, err
+	}
+	arr := make([]interface{}, 0, n)
+	for i := 0; i < n; i++ {
+		var v interface{}
+		if t == TypeArray {
+			// nested arrays carry their own type marker
+			et, err := d.next(1)
+			if err != nil {
+				return nil, err
+			}
+			if et[0]&FlagArray == 0 {
+				return nil, fmt.Errorf("epee: expected array entry, got type %d", et[0])
+			}
+			v, err = d.readArray(et[0]&^FlagArray, depth+1)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			v, err = d.readRaw(t, depth)
+			if err != nil {
+				return nil, err
+			}
+		}
+		arr = append(arr, v)
+	}
+	return arr, nil
+}
+
+func (d *decoder) readRaw(t byte, depth int) (interface{}, error) {
+	switch t {
+	case TypeInt64, TypeUint64, TypeDouble:
+		b, err := d.next(8)
+		if err != nil {
+			return nil, err
+		}
+		v := binary.LittleEndian.Uint64(b)
+		switch t {
+		case TypeInt64:
+			return int64(v), nil
+		case TypeDouble:
+			return math.Float64frombits(v), nil
+		}
+		return v, nil
+	case TypeInt32, TypeUint32:
+		b, err := d.next(4)
+		if err != nil {
+			return nil, err
+		}
+		v := binary.LittleEndian.Uint32(b)
+		if t == TypeInt32 {
+			return int32(v), nil
+		}
+		return v, nil
+	case TypeInt16, TypeUint16:
+		b, err := d.next(2)
+		if err != nil {
+			return nil, err
+		}
+		v := binary.LittleEndian.Uint16(b)
+		if t == TypeInt16 {
+			return int16(v), nil
+		}
+		return v, nil
+	case TypeInt8, TypeUint8, TypeBool:
+		b, err := d.next(1)
+		if err != nil {
+			return nil, err
+		}
+		switch t {
+		case TypeInt8:
+			return int8(b[0]), nil
+		case TypeBool:
+			return b[0] != 0, nil
+		}
+		return b[0], nil
+	case TypeString:
+		n, err := d.readCount()
+		if err != nil {
+			return nil, err
+		}
+		b, err := d.next(n)
+		if err != nil {
+			return nil, err
+		}
+		return string(b), nil
+	case TypeObject:
+		return d.readSection(depth + 1)
+	}
+	return nil, fmt.Errorf("epee: unknown type %d", t)
+}
+
+var unmarshalerType = reflect.TypeOf((*Unmarshaler)(nil)).Elem()
+
+// assign stores the generic value src into dst
+func assign(dst reflect.Value, src interface{}, name string) error {
+	if dst.Kind() != reflect.Ptr && dst.CanAddr() && dst.Addr().Type().Implements(unmarshalerType) {
+		return dst.Addr().Interface().(Unmarshaler).UnmarshalEpee(src)
+	}
+	if dst.Kind() == reflect.Ptr {
+		if dst.IsNil() {
+			dst.Set(reflect.New(dst.Type().Elem()))
+		}
+		if dst.Type().Implements(unmarshalerType) {
+			return dst.Interface().(Unmarshaler).UnmarshalEpee(src)
+		}
+		return assign(dst.Elem(), src, name)
+	}
+
+	mismatch := &TypeError{Field: name, Value: src, Type: dst.Type().String()}
+	switch dst.Kind() {
+	case reflect.Interface:
+		if dst.NumMethod() != 0 {
+			return mismatch
+		}
+		dst.Set(reflect.ValueOf(src))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, neg, ok := toInteger(src)
+		if !ok {
+			return mismatch
+		}
+		i := int64(n)
+		if (neg && i >= 0) || (!neg && i < 0) || dst.OverflowInt(i) {
+			return fmt.Errorf("epee: value out of range for %s", mismatch.Type)
+		}
+		dst.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, neg, ok := toInteger(src)
+		if !ok {
+			return mismatch
+		}
+		if neg || dst.OverflowUint(n) {
+			return fmt.Errorf("epee: value out of range for %s", mismatch.Type)
+		}
+		dst.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		switch f := src.(type) {
+		case float64:
+			dst.SetFloat(f)
+		default:
+			n, neg, ok := toInteger(src)
+			if !ok {
+				return mismatch
+			}
+			if neg {
+				dst.SetFloat(float64(int64(n)))
+			} else {
+				dst.SetFloat(float64(n))
+			}
+		}
+	case reflect.Bool:
+		b, ok := src.(bool)
+		if !ok {
+			return mismatch
+		}
+		dst.SetBool(b)
+	case reflect.String:
+		s, ok := src.(string)
+		if !ok {
+			return mismatch
+		}
+		dst.SetString(s)
+	case reflect.Struct:
+		sec, ok := src.(Section)
+		if !ok {
+			return mismatch
+		}
+		for _, f := range structFields(dst.Type()) {
+			v, ok := sec[f.name]
+			if !ok {
+				continue
+			}
+			fv := fieldByIndex(dst, f.index, true)
+			var err error
+			if s, isString := v.(string); f.blob && isString {
+				err = unpackBlob(fv, s)
+			} else {
+				err = assign(fv, v, f.name)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		sec, ok := src.(Section)
+		if !ok || dst.Type().Key().Kind() != reflect.String {
+			return mismatch
+		}
+		if dst.IsNil() {
+			dst.Set(reflect.MakeMapWithSize(dst.Type(), len(sec)))
+		}
+		for k, v := range sec {
+			ev := reflect.New(dst.Type().Elem()).Elem()
+			if err := assign(ev, v, k); err != nil {
+				return err
+			}
+			dst.SetMapIndex(reflect.ValueOf(k).Convert(dst.Type().Key()), ev)
+		}
+	case reflect.Slice:
+		if dst.Type().Elem().Kind() == reflect.Uint8 {
+			s, ok := src.(string)
+			if !ok {
+				return mismatch
+			}
+			dst.SetBytes([]byte(s))
+			return nil
+		}
+		arr, ok := src.([]interface{})
+		if !ok {
+			return mismatch
+		}
+		sl := reflect.MakeSlice(dst.Type(), len(arr), len(arr))
+		for i, v := range arr {
+			if err := assign(sl.Index(i), v, name); err != nil {
+				return err
+			}
+		}
+		dst.Set(sl)
+	case reflect.Array:
+		if dst.Type().Elem().Kind() == reflect.Uint8 {
+			s, ok := src.(string)
+			if !ok || len(s) != dst.Len() {
+				return mismatch
+			}
+			reflect.Copy(dst, reflect.ValueOf([]byte(s)))
+			return nil
+		}
+		arr, ok := src.([]interface{})
+		if !ok || len(arr) != dst.Len() {
+			return mismatch
+		}
+		for i, v := range arr {
+			if err := assign(dst.Index(i), v, name); err != nil {
+				return err
+			}
+		}
+	default:
+		return mismatch
+	}
+	return nil
+}
+
+// toInteger returns the magnitude bits of an integer value and whether it is negative
+func toInteger(src interface{}) (uint64, bool, bool) {
+	switch n := src.(type) {
+	case int64:
+		return uint64(n), n < 0, true
+	case int32:
+		return uint64(int64(n)), n < 0, true
+	case int16:
+		return uint64(int64(n)), n < 0, true
+	case int8:
+		return uint64(int64(n)), n < 0, true
+	case uint64:
+		return n, false, true
+	case uint32:
+		return uint64(n), false, true
+	case uint16:
+		return uint64(n), false, true
+	case uint8:
+		return uint64(n), false, true
+	}
+	return 0, false, false
+}
+
+// unpackBlob decodes a little endian byte string of fixed size values into dst
+func unpackBlob(dst reflect.Value, s string) error {
+	for dst.Kind() == reflect.Ptr {
+		if dst.IsNil() {
+			dst.Set(reflect.New(dst.Type().Elem()))
+		}
+		dst = dst.Elem()
+	}
+	if dst.Kind() != reflect.Slice {
+		return binary.Read(bytes.NewReader([]byte(s)), binary.LittleEndian, dst.Addr().Interface())
+	}
+	size := binary.Size(reflect.New(dst.Type().Elem()).Elem().Interface())
+	if size <= 0 {
+		return fmt.Errorf("epee: cannot unpack blob into %s", dst.Type())
+	}
+	if len(s)%size != 0 {
+		return fmt.Errorf("epee: blob length %d is not a multiple of %d", len(s), size)
+	}
+	sl := reflect.MakeSlice(dst.Type(), len(s)/size, len(s)/size)
+	if err := binary.Read(bytes.NewReader([]byte(s)), binary.LittleEndian, sl.Interface()); err != nil {
+		return err
+	}
+	dst.Set(sl)
+	return nil
+}
@@ -0,0 +1,316 @@
+package epee
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+)
+
+// Marshal returns the portable storage encoding of v, which must be a struct
+// or a map with string keys (or a pointer to one).
+func Marshal(v interface{}) ([]byte, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil, fmt.Errorf("epee: cannot marshal nil %s", rv.Type())
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct && rv.Kind() != reflect.Map {
+		return nil, fmt.Errorf("epee: cannot marshal %s, expected struct or map", rv.Type())
+	}
+
+	e := &encoder{}
+	e.writeUint32(SignatureA)
+	e.writeUint32(SignatureB)
+	e.buf.WriteByte(Version)
+	if err := e.writeSection(rv); err != nil {
+		return nil, err
+	}
+	return e.buf.Bytes(), nil
+}
+
+type encoder struct {
+	buf bytes.Buffer
+}
+
+type entry struct {
+	name  string
+	value reflect.Value
+	blob  bool
+}
+
+func (e *encoder) writeUint32(v uint32) {
+	var b [4]byte
+	binary.LittleEndian.PutUint32(b[:], v)
+	e.buf.Write(b[:])
+}
+
+// writeVarint writes n using the two low bits as a size marker
+func (e *encoder) writeVarint(n uint64) error {
+	var b [8]byte
+	switch {
+	case n <= 63:
+		e.buf.WriteByte(byte(n << 2))
+	case n <= 16383:
+		binary.LittleEndian.PutUint16(b[:], uint16(n<<2|1))
+		e.buf.Write(b[:2])
+	case n <= 1073741823:
+		binary.LittleEndian.PutUint32(b[:], uint32(n<<2|2))
+		e.buf.Write(b[:4])
+	case n <= 4611686018427387903:
+		binary.LittleEndian.PutUint64(b[:], n<<2|3)
+		e.buf.Write(b[:8])
+	default:
+		return fmt.Errorf("epee: varint %d out of range", n)
+	}
+	return nil
+}
+
+func (e *encoder) writeSection(v reflect.Value) error {
+	entries, err := sectionEntries(v)
+	if err != nil {
+		return err
+	}
+	if err := e.writeVarint(uint64(len(entries))); err != nil {
+		return err
+	}
+	for _, en := range entries {
+		if len(en.name) > 255 {
+			return fmt.Errorf("epee: entry name %q too long", en.name)
+		}
+		e.buf.WriteByte(byte(len(en.name)))
+		e.buf.WriteString(en.name)
+		if en.blob {
+			b, err := packBlob(en.value)
+			if err != nil {
+				return fmt.Errorf("epee: entry %s: %v", en.name, err)
+			}
+			e.buf.WriteByte(TypeString)
+			if err := e.writeBytes(b); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := e.writeValue(en.value); err != nil {
+			return fmt.Errorf("epee: entry %s: %v", en.name, err)
+		}
+	}
+	return nil
+}
+
+// sectionEntries lists the entries of a struct or map, skipping nil and omitted values
+func sectionEntries(v reflect.Value) ([]entry, error) {
+	var entries []entry
+	switch v.Kind() {
+	case reflect.Struct:
+		for _, f := range structFields(v.Type()) {
+			fv := fieldByIndex(v, f.index, false)
+			if !fv.IsValid() || isNil(fv) || (f.omitEmpty && fv.IsZero()) {
+				continue
+			}
+			entries = append(entries, entry{name: f.name, value: fv, blob: f.blob})
+		}
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("epee: unsupported map key type %s", v.Type().Key())
+		}
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+		for _, k := range keys {
+			mv := v.MapIndex(k)
+			if isNil(mv) {
+				continue
+			}
+			entries = append(entries, entry{name: k.String(), value: mv})
+		}
+	default:
+		return nil, fmt.Errorf("epee: cannot encode %s as object", v.Type())
+	}
+	return entries, nil
+}
+
+func isNil(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map:
+		return v.IsNil()
+	}
+	return false
+}
+
+// writeValue writes the type byte followed by the value
+func (e *encoder) writeValue(v reflect.Value) error {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+	t, err := typeOf(v.Type(), v)
+	if err != nil {
+		return err
+	}
+	if t == TypeArray {
+		return e.writeArray(v)
+	}
+	e.buf.WriteByte(t)
+	return e.writeRaw(v, t)
+}
+
+func (e *encoder) writeArray(v reflect.Value) error {
+	et, err := elemType(v)
+	if err != nil {
+		return err
+	}
+	e.buf.WriteByte(et | FlagArray)
+	if err := e.writeVarint(uint64(v.Len())); err != nil {
+		return err
+	}
+	for i := 0; i < v.Len(); i++ {
+		ev := v.Index(i)
+		for ev.Kind() == reflect.Ptr || ev.Kind() == reflect.Interface {
+			ev = ev.Elem()
+		}
+		if et == TypeArray {
+			// nested arrays carry their own type marker
+			if err := e.writeArray(ev); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := e.writeRaw(ev, et); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// elemType returns the serialized type of the elements of an array value
+func elemType(v reflect.Value) (byte, error) {
+	t := v.Type().Elem()
+	if t.Kind() == reflect.Interface {
+		if v.Len() == 0 {
+			return TypeObject, nil
+		}
+		ev := v.Index(0).Elem()
+		return typeOf(ev.Type(), ev)
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return typeOf(t, reflect.Value{})
+}
+
+// typeOf maps a Go type to its serialized type
+func typeOf(t reflect.Type, v reflect.Value) (byte, error) {
+	switch t.Kind() {
+	case reflect.Int64, reflect.Int:
+		return TypeInt64, nil
+	case reflect.Int32:
+		return TypeInt32, nil
+	case reflect.Int16:
+		return TypeInt16, nil
+	case reflect.Int8:
+		return TypeInt8, nil
+	case reflect.Uint64, reflect.Uint:
+		return TypeUint64, nil
+	case reflect.Uint32:
+		return TypeUint32, nil
+	case reflect.Uint16:
+		return TypeUint16, nil
+	case reflect.Uint8:
+		return TypeUint8, nil
+	case reflect.Float64, reflect.Float32:
+		return TypeDouble, nil
+	case reflect.String:
+		return TypeString, nil
+	case reflect.Bool:
+		return TypeBool, nil
+	case reflect.Struct, reflect.Map:
+		return TypeObject, nil
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return TypeString, nil
+		}
+		return TypeArray, nil
+	}
+	return 0, fmt.Errorf("epee: unsupported type %s", t)
+}
+
+// writeRaw writes the value without its type byte
+func (e *encoder) writeRaw(v reflect.Value, t byte) error {
+	var b [8]byte
+	switch t {
+	case TypeInt64:
+		binary.LittleEndian.PutUint64(b[:], uint64(v.Int()))
+		e.buf.Write(b[:8])
+	case TypeInt32:
+		binary.LittleEndian.PutUint32(b[:], uint32(v.Int()))
+		e.buf.Write(b[:4])
+	case TypeInt16:
+		binary.LittleEndian.PutUint16(b[:], uint16(v.Int()))
+		e.buf.Write(b[:2])
+	case TypeInt8:
+		e.buf.WriteByte(byte(v.Int()))
+	case TypeUint64:
+		binary.LittleEndian.PutUint64(b[:], v.Uint())
+		e.buf.Write(b[:8])
+	case TypeUint32:
+		binary.LittleEndian.PutUint32(b[:], uint32(v.Uint()))
+		e.buf.Write(b[:4])
+	case TypeUint16:
+		binary.LittleEndian.PutUint16(b[:], uint16(v.Uint()))
+		e.buf.Write(b[:2])
+	case TypeUint8:
+		e.buf.WriteByte(byte(v.Uint()))
+	case TypeDouble:
+		binary.LittleEndian.PutUint64(b[:], math.Float64bits(v.Float()))
+		e.buf.Write(b[:8])
+	case TypeBool:
+		if v.Bool() {
+			e.buf.WriteByte(1)
+		} else {
+			e.buf.WriteByte(0)
+		}
+	case TypeString:
+		if v.Kind() == reflect.String {
+			return e.writeBytes([]byte(v.String()))
+		}
+		if v.Kind() == reflect.Slice {
+			return e.writeBytes(v.Bytes())
+		}
+		b := make([]byte, v.Len())
+		reflect.Copy(reflect.ValueOf(b), v)
+		return e.writeBytes(b)
+	case TypeObject:
+		return e.writeSection(v)
+	default:
+		return fmt.Errorf("epee: unsupported type %d", t)
+	}
+	return nil
+}
+
+func (e *encoder) writeBytes(b []byte) error {
+	if err := e.writeVarint(uint64(len(b))); err != nil {
+		return err
+	}
+	e.buf.Write(b)
+	return nil
+}
+
+// packBlob packs a slice or array of fixed size values into a little endian byte string
+func packBlob(v reflect.Value) ([]byte, error) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		if binary.Size(v.Interface()) < 0 {
+			return nil, fmt.Errorf("cannot pack %s as blob", v.Type())
+		}
+	}
+	var buf bytes.Buffer
+	if err := binary.Write(&buf, binary.LittleEndian, v.Interface()); err != nil {
+		return nil, fmt.Errorf("cannot pack %s as blob: %v", v.Type(), err)
+	}
+	return buf.Bytes(), nil
+}
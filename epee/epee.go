@@ -0,0 +1,80 @@
+// Package epee implements the portable storage binary format used by monerod's .bin endpoints.
+//
+// Structs are mapped to epee objects using the "epee" struct tag:
+//
+//	type Request struct {
+//		StartHeight uint64     `epee:"start_height"`
+//		BlockIDs    [][32]byte `epee:"block_ids,blob"`
+//		Prune       bool       `epee:"prune,omitempty"`
+//		Ignored     string     `epee:"-"`
+//	}
+//
+// Fields without a tag use their Go name. The "blob" option packs a slice of fixed size
+// values (integers, byte arrays or structs of those) into a single string, as monerod does
+// for lists of hashes. []byte and [N]byte values are always stored as strings.
+package epee
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Portable storage signature and version written at the start of every payload
+const (
+	SignatureA = 0x01011101
+	SignatureB = 0x01020101
+	Version    = 1
+)
+
+// Serialized type identifiers
+const (
+	TypeInt64  byte = 1
+	TypeInt32  byte = 2
+	TypeInt16  byte = 3
+	TypeInt8   byte = 4
+	TypeUint64 byte = 5
+	TypeUint32 byte = 6
+	TypeUint16 byte = 7
+	TypeUint8  byte = 8
+	TypeDouble byte = 9
+	TypeString byte = 10
+	TypeBool   byte = 11
+	TypeObject byte = 12
+	TypeArray  byte = 13
+
+	// FlagArray marks a value as an array of the type in the low bits
+	FlagArray byte = 0x80
+)
+
+// maxDepth limits nesting of objects and arrays when decoding untrusted input
+const maxDepth = 100
+
+var (
+	// ErrInvalidSignature is returned when data does not start with the portable storage header
+	ErrInvalidSignature = errors.New("epee: invalid storage signature")
+	// ErrUnexpectedEOF is returned when data ends in the middle of a value
+	ErrUnexpectedEOF = errors.New("epee: unexpected end of data")
+)
+
+// Section is a decoded epee object
+type Section map[string]interface{}
+
+// Unmarshaler is implemented by types that decode themselves from a generic epee value.
+// The value is one of Section, []interface{}, string, bool, float64 or a sized integer.
+type Unmarshaler interface {
+	UnmarshalEpee(v interface{}) error
+}
+
+// TypeError describes a value that could not be stored into a Go value of a specific type
+type TypeError struct {
+	Field string
+	Value interface{}
+	Type  string
+}
+
+func (e *TypeError) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("epee: cannot decode %T into Go value of type %s", e.Value, e.Type)
+	}
+	return fmt.Sprintf("epee: cannot decode %T into Go struct field %s of type %s", e.Value, e.Field, e.Type)
+}
@@ -0,0 +1,195 @@
+package epee
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+const header = "011101010101020101"
+
+func TestMarshalStatus(t *testing.T) {
+	is := is.New(t)
+
+	buff, err := Marshal(struct {
+		Status string `epee:"status"`
+	}{Status: "OK"})
+	is.NoErr(err)
+	is.Equal(hex.EncodeToString(buff), header+"04"+"06"+hex.EncodeToString([]byte("status"))+"0a"+"08"+hex.EncodeToString([]byte("OK")))
+}
+
+func TestVarint(t *testing.T) {
+	tests := []struct {
+		n   uint64
+		hex string
+	}{
+		{0, "00"},
+		{63, "fc"},
+		{64, "0101"},
+		{16383, "fdff"},
+		{16384, "02000100"},
+		{1073741823, "feffffff"},
+		{1073741824, "0300000001000000"},
+	}
+	for _, test := range tests {
+		e := &encoder{}
+		is.New(t).NoErr(e.writeVarint(test.n))
+		is.New(t).Equal(hex.EncodeToString(e.buf.Bytes()), test.hex)
+
+		d := &decoder{data: e.buf.Bytes()}
+		n, err := d.readVarint()
+		is.New(t).NoErr(err)
+		is.New(t).Equal(n, test.n)
+	}
+}
+
+type outKey struct {
+	Key      [32]byte
+	Mask     [32]byte
+	Unlocked bool
+	Height   uint64
+	TxID     [32]byte
+}
+
+type txEntry struct {
+	Blob []byte `epee:"blob"`
+}
+
+type base struct {
+	Status    string `epee:"status"`
+	Untrusted bool   `epee:"untrusted"`
+}
+
+type sample struct {
+	base
+	I64      int64              `epee:"i64"`
+	I32      int32              `epee:"i32"`
+	I16      int16              `epee:"i16"`
+	I8       int8               `epee:"i8"`
+	U64      uint64             `epee:"u64"`
+	U32      uint32             `epee:"u32"`
+	U16      uint16             `epee:"u16"`
+	U8       uint8              `epee:"u8"`
+	Double   float64            `epee:"double"`
+	Hash     [32]byte           `epee:"hash"`
+	Data     []byte             `epee:"data"`
+	Heights  []uint64           `epee:"heights"`
+	Hashes   [][32]byte         `epee:"hashes,blob"`
+	Outs     []outKey           `epee:"outs,blob"`
+	Txs      []txEntry          `epee:"txs"`
+	Nested   [][]uint32         `epee:"nested"`
+	Extra    map[string]string  `epee:"extra"`
+	Optional *txEntry           `epee:"optional"`
+	Omitted  uint64             `epee:"omitted,omitempty"`
+	Skipped  string             `epee:"-"`
+	Generic  map[string]uint8   `epee:"generic,omitempty"`
+	Entries  map[string]txEntry `epee:"entries"`
+}
+
+func TestRoundTrip(t *testing.T) {
+	is := is.New(t)
+
+	in := sample{
+		base:    base{Status: "OK", Untrusted: true},
+		I64:     -1 << 40,
+		I32:     -70000,
+		I16:     -300,
+		I8:      -5,
+		U64:     1 << 63,
+		U32:     1 << 31,
+		U16:     1 << 15,
+		U8:      200,
+		Double:  1.5,
+		Hash:    [32]byte{1, 2, 3},
+		Data:    []byte{0, 1, 2, 255},
+		Heights: []uint64{1, 2, 3},
+		Hashes:  [][32]byte{{4}, {5}},
+		Outs: []outKey{
+			{Key: [32]byte{6}, Unlocked: true, Height: 1234, TxID: [32]byte{7}},
+		},
+		Txs:     []txEntry{{Blob: []byte("tx1")}, {Blob: []byte("tx2")}},
+		Nested:  [][]uint32{{1, 2}, {}, {3}},
+		Extra:   map[string]string{"a": "b"},
+		Skipped: "skipped",
+		Entries: map[string]txEntry{"x": {Blob: []byte("y")}},
+	}
+	buff, err := Marshal(&in)
+	is.NoErr(err)
+
+	var out sample
+	is.NoErr(Unmarshal(buff, &out))
+	in.Skipped = ""
+	is.Equal(out, in)
+
+	sec, err := Decode(buff)
+	is.NoErr(err)
+	is.Equal(sec["status"], "OK")
+	is.Equal(sec["u8"], uint8(200))
+	is.Equal(len(sec["hashes"].(string)), 64)
+	_, ok := sec["omitted"]
+	is.True(!ok)
+	_, ok = sec["optional"]
+	is.True(!ok)
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	is := is.New(t)
+
+	var res struct {
+		Status string `epee:"status"`
+	}
+	err := Unmarshal([]byte{1, 2, 3}, &res)
+	is.True(errors.Is(err, ErrInvalidSignature))
+
+	buff, err := Marshal(map[string]interface{}{"status": "OK"})
+	is.NoErr(err)
+	err = Unmarshal(buff[:len(buff)-1], &res)
+	is.True(errors.Is(err, ErrUnexpectedEOF))
+
+	buff, err = Marshal(map[string]interface{}{"status": uint64(1)})
+	is.NoErr(err)
+	err = Unmarshal(buff, &res)
+	var typeErr *TypeError
+	is.True(errors.As(err, &typeErr))
+
+	var small struct {
+		Status uint8 `epee:"status"`
+	}
+	buff, err = Marshal(map[string]interface{}{"status": uint64(256)})
+	is.NoErr(err)
+	is.True(Unmarshal(buff, &small) != nil)
+}
+
+type either struct {
+	Blob string
+}
+
+func (e *either) UnmarshalEpee(v interface{}) error {
+	switch v := v.(type) {
+	case string:
+		e.Blob = v
+	case Section:
+		e.Blob, _ = v["blob"].(string)
+	}
+	return nil
+}
+
+func TestUnmarshaler(t *testing.T) {
+	is := is.New(t)
+
+	buff, err := Marshal(map[string]interface{}{
+		"strings": []string{"a", "b"},
+		"objects": []txEntry{{Blob: []byte("c")}},
+	})
+	is.NoErr(err)
+
+	var res struct {
+		Strings []either `epee:"strings"`
+		Objects []either `epee:"objects"`
+	}
+	is.NoErr(Unmarshal(buff, &res))
+	is.Equal(res.Strings, []either{{Blob: "a"}, {Blob: "b"}})
+	is.Equal(res.Objects, []either{{Blob: "c"}})
+}
@@ -0,0 +1,80 @@
+package epee
+
+import (
+	"reflect"
+	"strings"
+)
+
+// field describes how a struct field maps to an epee entry
+type field struct {
+	name      string
+	index     []int
+	omitEmpty bool
+	blob      bool
+}
+
+// structFields returns the serialized fields of t, flattening untagged embedded structs
+func structFields(t reflect.Type) []field {
+	var fields []field
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := sf.Tag.Get("epee")
+		if tag == "-" {
+			continue
+		}
+		if sf.Anonymous && tag == "" {
+			ft := sf.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				for _, f := range structFields(ft) {
+					f.index = append([]int{i}, f.index...)
+					fields = append(fields, f)
+				}
+				continue
+			}
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		f := field{
+			name:  sf.Name,
+			index: []int{i},
+		}
+		if tag != "" {
+			parts := strings.Split(tag, ",")
+			if parts[0] != "" {
+				f.name = parts[0]
+			}
+			for _, opt := range parts[1:] {
+				switch opt {
+				case "omitempty":
+					f.omitEmpty = true
+				case "blob":
+					f.blob = true
+				}
+			}
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+// fieldByIndex returns the field at index, allocating nil embedded pointers on the way when alloc is set.
+// The returned value is invalid if a nil embedded pointer is found and alloc is false.
+func fieldByIndex(v reflect.Value, index []int, alloc bool) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !alloc {
+					return reflect.Value{}
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
@@ -0,0 +1,294 @@
+package monerorpc
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrUnauthorized is returned when the server rejects the credentials
+	ErrUnauthorized = errors.New("unauthorized - invalid username or password")
+	// ErrTransport is matched by errors that happened before a reply was received,
+	// e.g. connection refused, reset or a canceled context.
+	ErrTransport = errors.New("transport failure")
+)
+
+// Error is returned by every call made through MoneroRPC.
+// It carries the json rpc error code and message sent by the server, if any,
+// and wraps the underlying cause for auth, transport and decoding failures.
+type Error struct {
+	// Code is the json rpc error code, 0 if the server did not send one
+	Code int
+	// Message is the json rpc error message
+	Message string
+	// Data is the optional json rpc error data
+	Data interface{}
+	// Method is the json rpc method or the path of the endpoint called
+	Method string
+	// HTTPStatus is the http status code of the reply, 0 if none was received
+	HTTPStatus int
+	// Status is the status field of a path based endpoint reply (e.g. BUSY)
+	Status string
+	// Err is the underlying error
+	Err error
+	// Daemon is true when the error was sent by monerod, whose error codes differ from monero-wallet-rpc's
+	Daemon bool
+
+	transport bool
+}
+
+func (e *Error) Error() string {
+	switch {
+	case e.Code != 0:
+		return fmt.Sprintf("%s: %s (code %d)", e.Method, e.Message, e.Code)
+	case e.Status != "":
+		return fmt.Sprintf("%s failed with status %s", e.Method, e.Status)
+	case e.Err != nil:
+		return fmt.Sprintf("%s: %v", e.Method, e.Err)
+	}
+	return fmt.Sprintf("%s: unexpected http status %d", e.Method, e.HTTPStatus)
+}
+
+// Unwrap returns the underlying error
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the error matches target, which may be ErrTransport, an ErrorCode or a DaemonErrorCode.
+// ErrorCode matches errors of monero-wallet-rpc and DaemonErrorCode errors of monerod, only the standard
+// json rpc codes match both. A BUSY status matches ErrDaemonIsBusy and ErrCoreBusy.
+func (e *Error) Is(target error) bool {
+	switch code := target.(type) {
+	case ErrorCode:
+		if code == ErrDaemonIsBusy && e.Status == "BUSY" {
+			return true
+		}
+		if e.Daemon && !code.standard() {
+			return false
+		}
+		return e.Code != 0 && e.Code == int(code)
+	case DaemonErrorCode:
+		if code == ErrCoreBusy && e.Status == "BUSY" {
+			return true
+		}
+		return e.Daemon && e.Code != 0 && e.Code == int(code)
+	}
+	return target == ErrTransport && e.transport
+}
+
+// ErrorCode is a json rpc error code sent by monero-wallet-rpc.
+// The constants below can be used as sentinel errors with errors.Is:
+//
+//	if errors.Is(err, monerorpc.ErrNotEnoughMoney) {
+//		...
+//	}
+type ErrorCode int
+
+// monero-wallet-rpc error codes, see wallet_rpc_server_error_codes.h
+const (
+	ErrUnknownError            ErrorCode = -1
+	ErrWrongAddress            ErrorCode = -2
+	ErrDaemonIsBusy            ErrorCode = -3
+	ErrGenericTransferError    ErrorCode = -4
+	ErrWrongPaymentID          ErrorCode = -5
+	ErrTransferType            ErrorCode = -6
+	ErrDenied                  ErrorCode = -7
+	ErrWrongTxID               ErrorCode = -8
+	ErrWrongSignature          ErrorCode = -9
+	ErrWrongKeyImage           ErrorCode = -10
+	ErrWrongURI                ErrorCode = -11
+	ErrWrongIndex              ErrorCode = -12
+	ErrNotOpenWallet           ErrorCode = -13
+	ErrAccountIndexOutOfBounds ErrorCode = -14
+	ErrAddressIndexOutOfBounds ErrorCode = -15
+	ErrTxNotPossible           ErrorCode = -16
+	ErrNotEnoughMoney          ErrorCode = -17
+	ErrTxTooLarge              ErrorCode = -18
+	ErrNotEnoughOutsToMix      ErrorCode = -19
+	ErrZeroDestination         ErrorCode = -20
+	ErrWalletAlreadyExists     ErrorCode = -21
+	ErrInvalidPassword         ErrorCode = -22
+	ErrNoWalletDir             ErrorCode = -23
+	ErrNoTxKey                 ErrorCode = -24
+	ErrWrongKey                ErrorCode = -25
+	ErrBadHex                  ErrorCode = -26
+	ErrBadTxMetadata           ErrorCode = -27
+	ErrAlreadyMultisig         ErrorCode = -28
+	ErrWatchOnly               ErrorCode = -29
+	ErrBadMultisigInfo         ErrorCode = -30
+	ErrNotMultisig             ErrorCode = -31
+	ErrWrongLR                 ErrorCode = -32
+	ErrThresholdNotReached     ErrorCode = -33
+	ErrBadMultisigTxData       ErrorCode = -34
+	ErrMultisigSignature       ErrorCode = -35
+	ErrMultisigSubmission      ErrorCode = -36
+	ErrNotEnoughUnlockedMoney  ErrorCode = -37
+	ErrNoDaemonConnection      ErrorCode = -38
+	ErrBadUnsignedTxData       ErrorCode = -39
+	ErrBadSignedTxData         ErrorCode = -40
+	ErrSignedSubmission        ErrorCode = -41
+	ErrSignUnsigned            ErrorCode = -42
+	ErrNonDeterministic        ErrorCode = -43
+	ErrInvalidLogLevel         ErrorCode = -44
+	ErrAttributeNotFound       ErrorCode = -45
+	ErrZeroAmount              ErrorCode = -46
+	ErrInvalidSignatureType    ErrorCode = -47
+	ErrDisabled                ErrorCode = -48
+	ErrProxyAlreadyDefined     ErrorCode = -49
+	ErrNonzeroUnlockTime       ErrorCode = -50
+	ErrIsBackgroundWallet      ErrorCode = -51
+
+	// standard json rpc error codes
+	ErrParseError           ErrorCode = -32700
+	ErrInvalidRequest       ErrorCode = -32600
+	ErrMethodNotFound       ErrorCode = -32601
+	ErrInvalidParams        ErrorCode = -32602
+	ErrInternalJSONRPCError ErrorCode = -32603
+)
+
+var errorCodeNames = map[ErrorCode]string{
+	ErrUnknownError:            "unknown error",
+	ErrWrongAddress:            "wrong address",
+	ErrDaemonIsBusy:            "daemon is busy",
+	ErrGenericTransferError:    "generic transfer error",
+	ErrWrongPaymentID:          "wrong payment id",
+	ErrTransferType:            "wrong transfer type",
+	ErrDenied:                  "denied",
+	ErrWrongTxID:               "wrong txid",
+	ErrWrongSignature:          "wrong signature",
+	ErrWrongKeyImage:           "wrong key image",
+	ErrWrongURI:                "wrong uri",
+	ErrWrongIndex:              "wrong index",
+	ErrNotOpenWallet:           "no wallet file open",
+	ErrAccountIndexOutOfBounds: "account index out of bounds",
+	ErrAddressIndexOutOfBounds: "address index out of bounds",
+	ErrTxNotPossible:           "transaction not possible",
+	ErrNotEnoughMoney:          "not enough money",
+	ErrTxTooLarge:              "transaction too large",
+	ErrNotEnoughOutsToMix:      "not enough outputs to mix",
+	ErrZeroDestination:         "zero destination",
+	ErrWalletAlreadyExists:     "wallet already exists",
+	ErrInvalidPassword:         "invalid password",
+	ErrNoWalletDir:             "no wallet dir",
+	ErrNoTxKey:                 "no tx key",
+	ErrWrongKey:                "wrong key",
+	ErrBadHex:                  "bad hex",
+	ErrBadTxMetadata:           "bad tx metadata",
+	ErrAlreadyMultisig:         "already multisig",
+	ErrWatchOnly:               "watch only wallet",
+	ErrBadMultisigInfo:         "bad multisig info",
+	ErrNotMultisig:             "not multisig",
+	ErrWrongLR:                 "wrong LR",
+	ErrThresholdNotReached:     "threshold not reached",
+	ErrBadMultisigTxData:       "bad multisig tx data",
+	ErrMultisigSignature:       "multisig signature error",
+	ErrMultisigSubmission:      "multisig submission error",
+	ErrNotEnoughUnlockedMoney:  "not enough unlocked money",
+	ErrNoDaemonConnection:      "no daemon connection",
+	ErrBadUnsignedTxData:       "bad unsigned tx data",
+	ErrBadSignedTxData:         "bad signed tx data",
+	ErrSignedSubmission:        "signed submission error",
+	ErrSignUnsigned:            "sign unsigned error",
+	ErrNonDeterministic:        "non deterministic wallet",
+	ErrInvalidLogLevel:         "invalid log level",
+	ErrAttributeNotFound:       "attribute not found",
+	ErrZeroAmount:              "zero amount",
+	ErrInvalidSignatureType:    "invalid signature type",
+	ErrDisabled:                "disabled",
+	ErrProxyAlreadyDefined:     "proxy already defined",
+	ErrNonzeroUnlockTime:       "nonzero unlock time",
+	ErrIsBackgroundWallet:      "background wallet",
+	ErrParseError:              "parse error",
+	ErrInvalidRequest:          "invalid request",
+	ErrMethodNotFound:          "method not found",
+	ErrInvalidParams:           "invalid params",
+	ErrInternalJSONRPCError:    "internal json rpc error",
+}
+
+// standard reports whether c is one of the error codes reserved by the json rpc specification
+func (c ErrorCode) standard() bool {
+	return c >= -32768 && c <= -32000
+}
+
+func (c ErrorCode) Error() string {
+	if name, ok := errorCodeNames[c]; ok {
+		return name
+	}
+	return fmt.Sprintf("error code %d", int(c))
+}
+
+// DaemonErrorCode is a json rpc error code sent by monerod, matched with errors.Is like ErrorCode
+type DaemonErrorCode int
+
+// monerod error codes, see core_rpc_server_error_codes.h
+const (
+	ErrWrongParam         DaemonErrorCode = -1
+	ErrTooBigHeight       DaemonErrorCode = -2
+	ErrTooBigReserveSize  DaemonErrorCode = -3
+	ErrWrongWalletAddress DaemonErrorCode = -4
+	ErrInternalError      DaemonErrorCode = -5
+	ErrWrongBlockblob     DaemonErrorCode = -6
+	ErrBlockNotAccepted   DaemonErrorCode = -7
+	ErrCoreBusy           DaemonErrorCode = -9
+	ErrWrongBlockblobSize DaemonErrorCode = -10
+	ErrUnsupportedRPC     DaemonErrorCode = -11
+	ErrMiningToSubaddress DaemonErrorCode = -12
+	ErrRegtestRequired    DaemonErrorCode = -13
+)
+
+var daemonErrorCodeNames = map[DaemonErrorCode]string{
+	ErrWrongParam:         "wrong param",
+	ErrTooBigHeight:       "too big height",
+	ErrTooBigReserveSize:  "too big reserve size",
+	ErrWrongWalletAddress: "wrong wallet address",
+	ErrInternalError:      "internal error",
+	ErrWrongBlockblob:     "wrong block blob",
+	ErrBlockNotAccepted:   "block not accepted",
+	ErrCoreBusy:           "core is busy",
+	ErrWrongBlockblobSize: "wrong block blob size",
+	ErrUnsupportedRPC:     "unsupported rpc",
+	ErrMiningToSubaddress: "mining to subaddress",
+	ErrRegtestRequired:    "regtest required",
+}
+
+func (c DaemonErrorCode) Error() string {
+	if name, ok := daemonErrorCodeNames[c]; ok {
+		return name
+	}
+	return fmt.Sprintf("daemon error code %d", int(c))
+}
+
+// daemonMethods lists the json rpc methods of monerod, used to tell where an error comes from when the
+// endpoint serves both Wallet and Daemon calls. get_version and relay_tx exist on both servers and are
+// left out.
+var daemonMethods = map[string]bool{
+	"add_aux_pow":                true,
+	"banned":                     true,
+	"calc_pow":                   true,
+	"flush_cache":                true,
+	"flush_txpool":               true,
+	"generateblocks":             true,
+	"get_alternate_chains":       true,
+	"get_bans":                   true,
+	"get_block":                  true,
+	"get_block_count":            true,
+	"get_block_header_by_hash":   true,
+	"get_block_header_by_height": true,
+	"get_block_headers_range":    true,
+	"get_block_template":         true,
+	"get_coinbase_tx_sum":        true,
+	"get_connections":            true,
+	"get_fee_estimate":           true,
+	"get_info":                   true,
+	"get_last_block_header":      true,
+	"get_miner_data":             true,
+	"get_output_distribution":    true,
+	"get_output_histogram":       true,
+	"get_txpool_backlog":         true,
+	"hard_fork_info":             true,
+	"on_get_block_hash":          true,
+	"prune_blockchain":           true,
+	"set_bans":                   true,
+	"submit_block":               true,
+	"sync_info":                  true,
+}
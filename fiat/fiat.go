@@ -0,0 +1,180 @@
+// Package fiat converts between XMR amounts and fiat currencies using pluggable exchange rate providers.
+//
+// Fiat values are handled as *big.Rat so prices such as "19.99" are converted without float rounding.
+package fiat
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/MarinX/monerorpc"
+	"github.com/MarinX/monerorpc/wallet"
+)
+
+var (
+	// ErrRateNotFound is returned when a provider has no rate for the currency and time
+	ErrRateNotFound = errors.New("fiat: rate not found")
+	// ErrStaleRate is returned when the closest rate is older than allowed
+	ErrStaleRate = errors.New("fiat: rate is stale")
+	// ErrInvalidPrice is returned for malformed or negative prices
+	ErrInvalidPrice = errors.New("fiat: invalid price")
+)
+
+// Rate is the price of one XMR in a fiat currency at a point in time
+type Rate struct {
+	// Currency is an ISO 4217 code such as USD
+	Currency string
+	// Price of one XMR in Currency
+	Price *big.Rat
+	// Time the rate was observed
+	Time time.Time
+}
+
+// Provider supplies exchange rates
+type Provider interface {
+	// Rate returns the most recent rate of currency observed at or before at.
+	// A zero at asks for the latest rate.
+	Rate(ctx context.Context, currency string, at time.Time) (Rate, error)
+}
+
+type rateJSON struct {
+	Currency string    `json:"currency"`
+	Price    string    `json:"price"`
+	Time     time.Time `json:"time"`
+}
+
+// MarshalJSON encodes the rate with its price as a decimal string
+func (r Rate) MarshalJSON() ([]byte, error) {
+	if r.Price == nil {
+		return nil, fmt.Errorf("%w: missing price", ErrInvalidPrice)
+	}
+	price := strings.TrimRight(strings.TrimRight(r.Price.FloatString(12), "0"), ".")
+	return json.Marshal(rateJSON{Currency: r.Currency, Price: price, Time: r.Time})
+}
+
+// UnmarshalJSON decodes a rate whose price is a decimal string
+func (r *Rate) UnmarshalJSON(data []byte) error {
+	var v rateJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	price, err := ParsePrice(v.Price)
+	if err != nil {
+		return err
+	}
+	*r = Rate{Currency: strings.ToUpper(v.Currency), Price: price, Time: v.Time}
+	return nil
+}
+
+// ParsePrice parses a non negative decimal price such as "19.99".
+// Only digits with an optional fractional part are accepted, no sign, exponent, base prefix or underscore.
+func ParsePrice(s string) (*big.Rat, error) {
+	if !decimal(s) {
+		return nil, fmt.Errorf("%w %q", ErrInvalidPrice, s)
+	}
+	price, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrInvalidPrice, s)
+	}
+	return price, nil
+}
+
+// decimal reports whether s is made of digits with an optional fractional part, like "19" or "19.99"
+func decimal(s string) bool {
+	whole, frac, hasFrac := strings.Cut(s, ".")
+	return digits(whole) && (!hasFrac || digits(frac))
+}
+
+func digits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// ToAtomic converts a fiat price to an XMR amount at rate, rounding up to the next atomic unit
+// so the payer never sends less than the price
+func ToAtomic(price *big.Rat, rate Rate) (monerorpc.Amount, error) {
+	if price == nil || price.Sign() < 0 {
+		return 0, ErrInvalidPrice
+	}
+	if rate.Price == nil || rate.Price.Sign() <= 0 {
+		return 0, fmt.Errorf("%w: rate of %s must be positive", ErrInvalidPrice, rate.Currency)
+	}
+	atomic := new(big.Rat).Quo(price, rate.Price)
+	atomic.Mul(atomic, new(big.Rat).SetUint64(uint64(monerorpc.XMR)))
+
+	q, m := new(big.Int).QuoRem(atomic.Num(), atomic.Denom(), new(big.Int))
+	if m.Sign() != 0 {
+		q.Add(q, big.NewInt(1))
+	}
+	if !q.IsUint64() {
+		return 0, monerorpc.ErrAmountOverflow
+	}
+	return monerorpc.Amount(q.Uint64()), nil
+}
+
+// ToFiat converts an XMR amount to its value in the currency of rate
+func ToFiat(amount monerorpc.Amount, rate Rate) *big.Rat {
+	if rate.Price == nil {
+		return new(big.Rat)
+	}
+	value := new(big.Rat).SetFrac(new(big.Int).SetUint64(uint64(amount)), new(big.Int).SetUint64(uint64(monerorpc.XMR)))
+	return value.Mul(value, rate.Price)
+}
+
+// Destination builds a transfer destination paying price, given in currency, to address at the latest rate
+func Destination(ctx context.Context, p Provider, address string, price string, currency string) (wallet.Destination, error) {
+	amount, err := Convert(ctx, p, price, currency)
+	if err != nil {
+		return wallet.Destination{}, err
+	}
+	return wallet.Destination{Amount: amount.Uint64(), Address: address}, nil
+}
+
+// Convert turns a fiat price given in currency into an XMR amount at the latest rate
+func Convert(ctx context.Context, p Provider, price string, currency string) (monerorpc.Amount, error) {
+	value, err := ParsePrice(price)
+	if err != nil {
+		return 0, err
+	}
+	rate, err := p.Rate(ctx, currency, time.Time{})
+	if err != nil {
+		return 0, err
+	}
+	return ToAtomic(value, rate)
+}
+
+// TransferValue is a transfer together with its fiat value at the time of the transfer
+type TransferValue struct {
+	Transfer *wallet.Transfer
+	// Rate used for the conversion
+	Rate Rate
+	// Value of the transfer amount in the currency of Rate
+	Value *big.Rat
+}
+
+// Annotate values transfers, e.g. from GetTransfers, in currency at their timestamps
+func Annotate(ctx context.Context, p Provider, currency string, transfers []*wallet.Transfer) ([]TransferValue, error) {
+	values := make([]TransferValue, 0, len(transfers))
+	for _, t := range transfers {
+		at := time.Unix(int64(t.Timestamp), 0)
+		rate, err := p.Rate(ctx, currency, at)
+		if err != nil {
+			return nil, fmt.Errorf("transfer %s: %w", t.TxID, err)
+		}
+		values = append(values, TransferValue{
+			Transfer: t,
+			Rate:     rate,
+			Value:    ToFiat(monerorpc.Amount(t.Amount), rate),
+		})
+	}
+	return values, nil
+}
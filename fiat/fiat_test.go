@@ -0,0 +1,198 @@
+package fiat
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/MarinX/monerorpc"
+	"github.com/MarinX/monerorpc/wallet"
+	"github.com/matryer/is"
+)
+
+var (
+	day1 = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	day2 = day1.Add(24 * time.Hour)
+)
+
+func price(s string) *big.Rat {
+	r, err := ParsePrice(s)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+func TestToAtomic(t *testing.T) {
+	is := is.New(t)
+
+	rate := Rate{Currency: "USD", Price: price("150")}
+	a, err := ToAtomic(price("15"), rate)
+	is.NoErr(err)
+	is.Equal(a.String(), "0.1")
+
+	// 19.99 / 150 = 0.133266666... rounded up
+	a, err = ToAtomic(price("19.99"), rate)
+	is.NoErr(err)
+	is.Equal(a, monerorpc.Amount(133266666667))
+
+	_, err = ToAtomic(price("1"), Rate{Currency: "USD", Price: price("0")})
+	is.True(errors.Is(err, ErrInvalidPrice))
+
+	for _, in := range []string{"-1", "1e3", "1/3", "abc", "", "0x10", "0b11", "0o7", "1_000", "+5", ".5", "5.", "1.2.3", " 1"} {
+		_, err = ParsePrice(in)
+		is.True(errors.Is(err, ErrInvalidPrice))
+	}
+}
+
+func TestToFiat(t *testing.T) {
+	value := ToFiat(monerorpc.MustParseAmount("0.133266666667"), Rate{Currency: "USD", Price: price("150")})
+	is.New(t).Equal(value.FloatString(2), "19.99")
+}
+
+func TestStaticProvider(t *testing.T) {
+	p := NewStaticProvider(
+		Rate{Currency: "usd", Price: price("160"), Time: day2},
+		Rate{Currency: "USD", Price: price("150"), Time: day1},
+	)
+	ctx := context.Background()
+	is := is.New(t)
+
+	r, err := p.Rate(ctx, "USD", time.Time{})
+	is.NoErr(err)
+	is.Equal(r.Price, price("160"))
+
+	r, err = p.Rate(ctx, "usd", day1.Add(time.Hour))
+	is.NoErr(err)
+	is.Equal(r.Price, price("150"))
+
+	_, err = p.Rate(ctx, "USD", day1.Add(-time.Hour))
+	is.True(errors.Is(err, ErrRateNotFound))
+	_, err = p.Rate(ctx, "EUR", time.Time{})
+	is.True(errors.Is(err, ErrRateNotFound))
+}
+
+func TestLoadFile(t *testing.T) {
+	is := is.New(t)
+
+	buff, err := json.Marshal([]Rate{{Currency: "EUR", Price: price("140.5"), Time: day1}})
+	is.NoErr(err)
+	is.Equal(string(buff), `[{"currency":"EUR","price":"140.5","time":"2024-01-01T00:00:00Z"}]`)
+
+	path := filepath.Join(t.TempDir(), "rates.json")
+	is.NoErr(os.WriteFile(path, buff, 0600))
+	p, err := LoadFile(path)
+	is.NoErr(err)
+
+	r, err := p.Rate(context.Background(), "EUR", time.Time{})
+	is.NoErr(err)
+	is.Equal(r.Price, price("140.5"))
+	is.True(r.Time.Equal(day1))
+}
+
+type countingProvider struct {
+	Provider
+	calls int
+}
+
+func (c *countingProvider) Rate(ctx context.Context, currency string, at time.Time) (Rate, error) {
+	c.calls++
+	return c.Provider.Rate(ctx, currency, at)
+}
+
+func TestCache(t *testing.T) {
+	static := NewStaticProvider(Rate{Currency: "USD", Price: price("150"), Time: day1})
+	counting := &countingProvider{Provider: static}
+	cache := NewCache(counting, time.Minute, time.Hour)
+	now := day1.Add(10 * time.Minute)
+	cache.now = func() time.Time { return now }
+	ctx := context.Background()
+	is := is.New(t)
+
+	_, err := cache.Rate(ctx, "USD", time.Time{})
+	is.NoErr(err)
+	_, err = cache.Rate(ctx, "USD", time.Time{})
+	is.NoErr(err)
+	is.Equal(counting.calls, 1)
+
+	now = now.Add(2 * time.Minute)
+	_, err = cache.Rate(ctx, "USD", time.Time{})
+	is.NoErr(err)
+	is.Equal(counting.calls, 2)
+
+	// the latest rate is older than the max age
+	now = day1.Add(2 * time.Hour)
+	_, err = cache.Rate(ctx, "USD", time.Time{})
+	is.True(errors.Is(err, ErrStaleRate))
+
+	// historical rates are checked against the requested time and cached
+	_, err = cache.Rate(ctx, "USD", day1.Add(30*time.Minute))
+	is.NoErr(err)
+	_, err = cache.Rate(ctx, "USD", day1.Add(30*time.Minute))
+	is.NoErr(err)
+	is.Equal(counting.calls, 4)
+	_, err = cache.Rate(ctx, "USD", day2)
+	is.True(errors.Is(err, ErrStaleRate))
+
+	// historical rates expire after the ttl too
+	now = now.Add(2 * time.Minute)
+	_, err = cache.Rate(ctx, "USD", day1.Add(30*time.Minute))
+	is.NoErr(err)
+	is.Equal(counting.calls, 6)
+}
+
+func TestCacheHistorySize(t *testing.T) {
+	static := NewStaticProvider(Rate{Currency: "USD", Price: price("150"), Time: day1})
+	counting := &countingProvider{Provider: static}
+	cache := NewCache(counting, time.Hour, 0)
+	cache.now = func() time.Time { return day2 }
+	ctx := context.Background()
+	is := is.New(t)
+
+	for i := 0; i <= HistorySize; i++ {
+		_, err := cache.Rate(ctx, "USD", day1.Add(time.Duration(i)*time.Second))
+		is.NoErr(err)
+	}
+	is.Equal(len(cache.history), HistorySize)
+	is.Equal(cache.lru.Len(), HistorySize)
+
+	// the first rate was evicted, the last one is still cached
+	calls := counting.calls
+	_, err := cache.Rate(ctx, "USD", day1.Add(time.Duration(HistorySize)*time.Second))
+	is.NoErr(err)
+	is.Equal(counting.calls, calls)
+	_, err = cache.Rate(ctx, "USD", day1)
+	is.NoErr(err)
+	is.Equal(counting.calls, calls+1)
+}
+
+func TestDestinationAndAnnotate(t *testing.T) {
+	p := NewStaticProvider(
+		Rate{Currency: "USD", Price: price("100"), Time: day1},
+		Rate{Currency: "USD", Price: price("200"), Time: day2},
+	)
+	ctx := context.Background()
+	is := is.New(t)
+
+	dest, err := Destination(ctx, p, "addr", "50", "USD")
+	is.NoErr(err)
+	is.Equal(dest, wallet.Destination{Amount: uint64(monerorpc.XMR / 4), Address: "addr"})
+
+	transfers := []*wallet.Transfer{
+		{TxID: "a", Amount: uint64(monerorpc.XMR), Timestamp: uint64(day1.Add(time.Hour).Unix())},
+		{TxID: "b", Amount: uint64(monerorpc.XMR), Timestamp: uint64(day2.Add(time.Hour).Unix())},
+	}
+	values, err := Annotate(ctx, p, "USD", transfers)
+	is.NoErr(err)
+	is.Equal(values[0].Value.FloatString(2), "100.00")
+	is.Equal(values[1].Value.FloatString(2), "200.00")
+	is.Equal(values[1].Transfer, transfers[1])
+
+	_, err = Annotate(ctx, p, "EUR", transfers)
+	is.True(errors.Is(err, ErrRateNotFound))
+}
@@ -0,0 +1,185 @@
+package fiat
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// StaticProvider serves rates from memory, e.g. loaded from a file or added by a background job
+type StaticProvider struct {
+	mu    sync.RWMutex
+	rates map[string][]Rate
+}
+
+// NewStaticProvider creates a provider holding rates
+func NewStaticProvider(rates ...Rate) *StaticProvider {
+	p := &StaticProvider{rates: make(map[string][]Rate)}
+	p.Add(rates...)
+	return p
+}
+
+// LoadFile creates a provider from a JSON file holding an array of rates:
+//
+//	[{"currency": "USD", "price": "150.25", "time": "2024-01-01T00:00:00Z"}]
+func LoadFile(path string) (*StaticProvider, error) {
+	buff, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var rates []Rate
+	if err = json.Unmarshal(buff, &rates); err != nil {
+		return nil, fmt.Errorf("fiat: error decoding %s %v", path, err)
+	}
+	return NewStaticProvider(rates...), nil
+}
+
+// Add stores rates, keeping them ordered by time
+func (p *StaticProvider) Add(rates ...Rate) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	changed := make(map[string]bool)
+	for _, r := range rates {
+		currency := strings.ToUpper(r.Currency)
+		r.Currency = currency
+		p.rates[currency] = append(p.rates[currency], r)
+		changed[currency] = true
+	}
+	for currency := range changed {
+		list := p.rates[currency]
+		sort.SliceStable(list, func(i, j int) bool { return list[i].Time.Before(list[j].Time) })
+	}
+}
+
+// Rate returns the last rate observed at or before at, or the latest rate if at is zero
+func (p *StaticProvider) Rate(ctx context.Context, currency string, at time.Time) (Rate, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	list := p.rates[strings.ToUpper(currency)]
+	i := len(list)
+	if !at.IsZero() {
+		i = sort.Search(len(list), func(i int) bool { return list[i].Time.After(at) })
+	}
+	if i == 0 {
+		return Rate{}, fmt.Errorf("%w for %s at %s", ErrRateNotFound, currency, at)
+	}
+	return list[i-1], nil
+}
+
+// Cache wraps a provider, caching rates and rejecting rates that are too old
+type Cache struct {
+	provider Provider
+	ttl      time.Duration
+	maxAge   time.Duration
+	now      func() time.Time
+
+	mu      sync.Mutex
+	latest  map[string]cacheEntry
+	history map[historyKey]*list.Element
+	lru     *list.List
+}
+
+// HistorySize is the number of historical rates kept by a Cache, the least recently used are evicted first
+const HistorySize = 4096
+
+type cacheEntry struct {
+	rate    Rate
+	fetched time.Time
+}
+
+type historyKey struct {
+	currency string
+	at       int64
+}
+
+type historyEntry struct {
+	key historyKey
+	cacheEntry
+}
+
+// NewCache creates a cache over provider. Rates are fetched again after ttl, so corrections
+// of the provider are picked up, and at most HistorySize historical rates are kept. Rates observed
+// more than maxAge before the requested time (or before now for the latest rate) fail with
+// ErrStaleRate, 0 disables the check.
+func NewCache(provider Provider, ttl, maxAge time.Duration) *Cache {
+	return &Cache{
+		provider: provider,
+		ttl:      ttl,
+		maxAge:   maxAge,
+		now:      time.Now,
+		latest:   make(map[string]cacheEntry),
+		history:  make(map[historyKey]*list.Element),
+		lru:      list.New(),
+	}
+}
+
+// Rate returns the rate from the cache or the wrapped provider
+func (c *Cache) Rate(ctx context.Context, currency string, at time.Time) (Rate, error) {
+	currency = strings.ToUpper(currency)
+	now := c.now()
+	rate, err := c.rate(ctx, currency, at, now)
+	if err != nil {
+		return Rate{}, err
+	}
+	ref := at
+	if ref.IsZero() {
+		ref = now
+	}
+	if c.maxAge > 0 && ref.Sub(rate.Time) > c.maxAge {
+		return Rate{}, fmt.Errorf("%w: %s rate from %s", ErrStaleRate, currency, rate.Time)
+	}
+	return rate, nil
+}
+
+func (c *Cache) rate(ctx context.Context, currency string, at time.Time, now time.Time) (Rate, error) {
+	c.mu.Lock()
+	if at.IsZero() {
+		if e, ok := c.latest[currency]; ok && now.Sub(e.fetched) < c.ttl {
+			c.mu.Unlock()
+			return e.rate, nil
+		}
+	} else if el, ok := c.history[historyKey{currency, at.UnixNano()}]; ok {
+		if e := el.Value.(*historyEntry); now.Sub(e.fetched) < c.ttl {
+			c.lru.MoveToFront(el)
+			c.mu.Unlock()
+			return e.rate, nil
+		}
+	}
+	c.mu.Unlock()
+
+	rate, err := c.provider.Rate(ctx, currency, at)
+	if err != nil {
+		return Rate{}, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if at.IsZero() {
+		c.latest[currency] = cacheEntry{rate: rate, fetched: now}
+	} else {
+		c.addHistory(historyKey{currency, at.UnixNano()}, cacheEntry{rate: rate, fetched: now})
+	}
+	return rate, nil
+}
+
+// addHistory stores a historical rate, evicting the least recently used one when the cache is full.
+// c.mu must be held.
+func (c *Cache) addHistory(key historyKey, e cacheEntry) {
+	if el, ok := c.history[key]; ok {
+		el.Value.(*historyEntry).cacheEntry = e
+		c.lru.MoveToFront(el)
+		return
+	}
+	c.history[key] = c.lru.PushFront(&historyEntry{key: key, cacheEntry: e})
+	if c.lru.Len() > HistorySize {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.history, oldest.Value.(*historyEntry).key)
+	}
+}
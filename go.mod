@@ -3,8 +3,14 @@ module github.com/MarinX/monerorpc
 go 1.19
 
 require (
+	filippo.io/edwards25519 v1.0.0
 	github.com/gabstv/httpdigest v0.0.0-20230306144402-1057ac3638b3
 	github.com/gorilla/rpc v1.2.0
+	github.com/tyler-smith/go-bip39 v1.1.0
+	golang.org/x/crypto v0.20.0
+	golang.org/x/text v0.14.0
 )
 
 require github.com/matryer/is v1.4.0
+
+require golang.org/x/sys v0.17.0 // indirect
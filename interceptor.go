@@ -0,0 +1,99 @@
+package monerorpc
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Invoker makes a call. method is the json rpc method, or the path of monerod's
+// path based and .bin endpoints (e.g. /get_height).
+type Invoker func(ctx context.Context, method string, req interface{}, res interface{}) error
+
+// Interceptor wraps every call made through MoneroRPC, including Wallet, Daemon and raw Do calls.
+// It may change ctx, method and req before handing the call to next, and inspect res and the
+// returned error afterwards. Retries happen inside next, so an interceptor sees one call per
+// Wallet or Daemon method invoked. Batches are seen as a single call to the "batch" method
+// with the []*BatchCall as request.
+type Interceptor func(ctx context.Context, method string, req interface{}, res interface{}, next Invoker) error
+
+// CallInfo describes a finished call
+type CallInfo struct {
+	Method   string
+	Request  interface{}
+	Response interface{}
+	Err      error
+	Duration time.Duration
+}
+
+// ObserveCalls returns an interceptor which hands every finished call to fn,
+// useful for logging and metrics
+func ObserveCalls(fn func(info CallInfo)) Interceptor {
+	return func(ctx context.Context, method string, req interface{}, res interface{}, next Invoker) error {
+		start := time.Now()
+		err := next(ctx, method, req, res)
+		fn(CallInfo{
+			Method:   method,
+			Request:  req,
+			Response: res,
+			Err:      err,
+			Duration: time.Since(start),
+		})
+		return err
+	}
+}
+
+// Use appends interceptors to the chain of both Wallet and Daemon calls.
+// Interceptors run in the order they are added, the first one being the outermost.
+func (c *MoneroRPC) Use(interceptors ...Interceptor) *MoneroRPC {
+	c.wallet.interceptors = append(c.wallet.interceptors, interceptors...)
+	if c.daemon != c.wallet {
+		c.daemon.interceptors = append(c.daemon.interceptors, interceptors...)
+	}
+	return c
+}
+
+// WithInterceptors appends interceptors to the chain of both Wallet and Daemon calls
+func WithInterceptors(interceptors ...Interceptor) Option {
+	return func(c *config) {
+		c.interceptors = append(c.interceptors, interceptors...)
+	}
+}
+
+// intercept runs invoker through the interceptors of the endpoint
+func (e *Endpoint) intercept(ctx context.Context, method string, req interface{}, res interface{}, invoker Invoker) error {
+	next := invoker
+	for i := len(e.interceptors) - 1; i >= 0; i-- {
+		interceptor, inner := e.interceptors[i], next
+		next = func(ctx context.Context, method string, req interface{}, res interface{}) error {
+			return interceptor(ctx, method, req, res, inner)
+		}
+	}
+	return next(ctx, method, req, res)
+}
+
+type headerKey struct{}
+
+// ContextWithHeader returns a copy of ctx carrying an http header which is added to
+// requests made with it, e.g. to inject an auth token from an interceptor
+func ContextWithHeader(ctx context.Context, key, value string) context.Context {
+	header := http.Header{}
+	if h, ok := ctx.Value(headerKey{}).(http.Header); ok {
+		header = h.Clone()
+	}
+	header.Add(key, value)
+	return context.WithValue(ctx, headerKey{}, header)
+}
+
+// setHeaders copies the headers carried by ctx into req
+func setHeaders(ctx context.Context, req *http.Request) {
+	h, ok := ctx.Value(headerKey{}).(http.Header)
+	if !ok {
+		return
+	}
+	for key, values := range h {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package monerorpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/MarinX/monerorpc/wallet"
+	"github.com/matryer/is"
+)
+
+func TestInterceptorOrder(t *testing.T) {
+	server := setupServer(t, `{"id": "0", "jsonrpc": "2.0", "result": {"count": 993163}}`)
+	defer server.Close()
+
+	var order []string
+	trace := func(name string) Interceptor {
+		return func(ctx context.Context, method string, req interface{}, res interface{}, next Invoker) error {
+			order = append(order, name+" "+method)
+			err := next(ctx, method, req, res)
+			order = append(order, name+" done")
+			return err
+		}
+	}
+	client := New(server.URL, server.Client()).Use(trace("first"), trace("second"))
+	is := is.New(t)
+
+	_, err := client.Daemon.GetBlockCount()
+	is.NoErr(err)
+	is.Equal(order, []string{"first get_block_count", "second get_block_count", "second done", "first done"})
+}
+
+func TestInterceptorObserveCalls(t *testing.T) {
+	walletServer := setupServer(t, `{"id": "0", "jsonrpc": "2.0", "error": {"code": -13, "message": "No wallet file"}}`)
+	defer walletServer.Close()
+	daemonServer := setupServer(t, `{"height": 10, "status": "OK"}`)
+	defer daemonServer.Close()
+
+	var calls []CallInfo
+	client := NewClient(
+		WithWallet(walletServer.URL, walletServer.Client()),
+		WithDaemon(daemonServer.URL+"/json_rpc", daemonServer.Client()),
+		WithInterceptors(ObserveCalls(func(info CallInfo) {
+			calls = append(calls, info)
+		})),
+	)
+	is := is.New(t)
+
+	_, err := client.Wallet.GetBalance(&wallet.GetBalanceRequest{AccountIndex: 1})
+	is.True(errors.Is(err, ErrNotOpenWallet))
+	_, err = client.Daemon.GetHeight()
+	is.NoErr(err)
+	var res struct{}
+	_ = client.Do("get_version", nil, &res)
+
+	is.Equal(len(calls), 3)
+	is.Equal(calls[0].Method, "get_balance")
+	is.Equal(calls[0].Request, &wallet.GetBalanceRequest{AccountIndex: 1})
+	is.True(errors.Is(calls[0].Err, ErrNotOpenWallet))
+	is.Equal(calls[1].Method, "/get_height")
+	is.NoErr(calls[1].Err)
+	is.True(calls[1].Duration > 0)
+	is.Equal(calls[2].Method, "get_version")
+}
+
+func TestInterceptorMutation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		buff, _ := io.ReadAll(req.Body)
+		var r struct {
+			Method string `json:"method"`
+		}
+		json.Unmarshal(buff, &r)
+		if req.Header.Get("Authorization") != "Bearer token" || r.Method != "get_info" {
+			rw.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		rw.Write([]byte(`{"id": "0", "jsonrpc": "2.0", "result": {"height": 42}}`))
+	}))
+	defer server.Close()
+
+	client := New(server.URL, server.Client()).Use(func(ctx context.Context, method string, req interface{}, res interface{}, next Invoker) error {
+		ctx = ContextWithHeader(ctx, "Authorization", "Bearer token")
+		return next(ctx, "get_info", req, res)
+	})
+	is := is.New(t)
+
+	var res struct {
+		Height uint64 `json:"height"`
+	}
+	is.NoErr(client.Do("renamed", nil, &res))
+	is.Equal(res.Height, uint64(42))
+}
@@ -0,0 +1,75 @@
+package keys
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"filippo.io/edwards25519"
+	"github.com/MarinX/monerorpc/crypto"
+	"github.com/MarinX/monerorpc/wallet"
+)
+
+// OutputSecret returns the private key of a received output of one time key out, at index in a transaction
+// whose tx public key, or additional tx public key for the output, is txPublicKey, paid to the subaddress at
+// subaddr: Hs(8·a·R || index) + b + m (generate_key_image_helper)
+func (k *Keys) OutputSecret(txPublicKey [32]byte, index uint64, out [32]byte, subaddr wallet.Index) (*edwards25519.Scalar, error) {
+	if k.viewOnly {
+		return nil, ErrViewOnly
+	}
+	view, err := crypto.ParseScalar(k.ViewSecret)
+	if err != nil {
+		return nil, err
+	}
+	spend, err := crypto.ParseScalar(k.SpendSecret)
+	if err != nil {
+		return nil, err
+	}
+	derivation, err := crypto.GenerateKeyDerivation(txPublicKey, view)
+	if err != nil {
+		return nil, err
+	}
+	x := new(edwards25519.Scalar).Add(crypto.DerivationToScalar(derivation, index), spend)
+	if subaddr != (wallet.Index{}) {
+		g, err := k.Subaddresses()
+		if err != nil {
+			return nil, err
+		}
+		m, err := crypto.ParseScalar(g.SecretKey(subaddr.Major, subaddr.Minor))
+		if err != nil {
+			return nil, err
+		}
+		x.Add(x, m)
+	}
+	if crypto.Bytes(new(edwards25519.Point).ScalarBaseMult(x)) != out {
+		return nil, fmt.Errorf("%w: output %x", ErrMismatch, out)
+	}
+	return x, nil
+}
+
+// KeyImage returns the key image of a received output, see OutputSecret. The output is spent by the
+// transaction whose input has this key image.
+func (k *Keys) KeyImage(txPublicKey [32]byte, index uint64, out [32]byte, subaddr wallet.Index) ([32]byte, error) {
+	x, err := k.OutputSecret(txPublicKey, index, out, subaddr)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	return crypto.KeyImage(out, x), nil
+}
+
+// SignedKeyImage returns the key image of a received output signed like wallet-rpc's export_key_images, with a
+// ring signature of the key image by the output alone, ready for import_key_images
+func (k *Keys) SignedKeyImage(txPublicKey [32]byte, index uint64, out [32]byte, subaddr wallet.Index) (*wallet.SignedImage, error) {
+	x, err := k.OutputSecret(txPublicKey, index, out, subaddr)
+	if err != nil {
+		return nil, err
+	}
+	image := crypto.KeyImage(out, x)
+	sigs, err := crypto.GenerateRingSignature(image, image, [][32]byte{out}, x, 0)
+	if err != nil {
+		return nil, err
+	}
+	return &wallet.SignedImage{
+		KeyImage:  hex.EncodeToString(image[:]),
+		Signature: hex.EncodeToString(sigs[0].Bytes()),
+	}, nil
+}
@@ -0,0 +1,75 @@
+package keys
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"filippo.io/edwards25519"
+	"github.com/MarinX/monerorpc/address"
+	"github.com/MarinX/monerorpc/crypto"
+	"github.com/MarinX/monerorpc/wallet"
+	"github.com/matryer/is"
+)
+
+// output 1 of a stagenet transaction paying the primary address
+const (
+	txPublicKey = "7302dd77bf4095baf868de43b7a32f4a36fe9d8b48ccfff537157a4a786fa364"
+	outputKey   = "7e4f4427539b206740bed78b81b0dc10acb89aa1545880863f73264492ee0c16"
+)
+
+func TestKeyImage(t *testing.T) {
+	is := is.New(t)
+	k, err := FromSpendKey(address.Stagenet, key(spendKey))
+	is.NoErr(err)
+
+	image, err := k.KeyImage(key(txPublicKey), 1, key(outputKey), wallet.Index{})
+	is.NoErr(err)
+	signed, err := k.SignedKeyImage(key(txPublicKey), 1, key(outputKey), wallet.Index{})
+	is.NoErr(err)
+	is.Equal(signed.KeyImage, hex.EncodeToString(image[:]))
+	sig, err := hex.DecodeString(signed.Signature)
+	is.NoErr(err)
+	s, ok := crypto.SignatureFromBytes(sig)
+	is.True(ok)
+	is.True(crypto.CheckRingSignature(image, image, [][32]byte{key(outputKey)}, []crypto.Signature{s}))
+
+	_, err = k.KeyImage(key(txPublicKey), 0, key(outputKey), wallet.Index{})
+	is.True(errors.Is(err, ErrMismatch))
+	_, err = k.KeyImage(key(txPublicKey), 1, key(outputKey), wallet.Index{Minor: 1})
+	is.True(errors.Is(err, ErrMismatch))
+
+	view, err := FromViewKey(address.Stagenet, k.ViewSecret, k.SpendPublic)
+	is.NoErr(err)
+	_, err = view.KeyImage(key(txPublicKey), 1, key(outputKey), wallet.Index{})
+	is.Equal(err, ErrViewOnly)
+}
+
+func TestKeyImageSubaddress(t *testing.T) {
+	is := is.New(t)
+	k, err := FromSpendKey(address.Stagenet, key(spendKey))
+	is.NoErr(err)
+	g, err := k.Subaddresses()
+	is.NoErr(err)
+	view, err := crypto.ParseScalar(k.ViewSecret)
+	is.NoErr(err)
+
+	// a payment to the subaddress (2, 7), with the tx public key r·D
+	r, err := crypto.RandomScalar()
+	is.NoErr(err)
+	d, err := crypto.ParsePoint(g.SpendKey(2, 7))
+	is.NoErr(err)
+	txKey := crypto.Bytes(new(edwards25519.Point).ScalarMult(r, d))
+	derivation, err := crypto.GenerateKeyDerivation(txKey, view)
+	is.NoErr(err)
+	out, err := crypto.DerivePublicKey(derivation, 3, g.SpendKey(2, 7))
+	is.NoErr(err)
+
+	x, err := k.OutputSecret(txKey, 3, out, wallet.Index{Major: 2, Minor: 7})
+	is.NoErr(err)
+	image, err := k.KeyImage(txKey, 3, out, wallet.Index{Major: 2, Minor: 7})
+	is.NoErr(err)
+	is.Equal(image, crypto.KeyImage(out, x))
+	_, err = k.KeyImage(txKey, 3, out, wallet.Index{})
+	is.True(errors.Is(err, ErrMismatch))
+}
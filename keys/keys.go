@@ -0,0 +1,214 @@
+// Package keys derives the full key set and primary address of a Monero wallet offline,
+// from a mnemonic seed, a polyseed or the private keys.
+package keys
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/MarinX/monerorpc/address"
+	"github.com/MarinX/monerorpc/crypto"
+	"github.com/MarinX/monerorpc/mnemonic"
+	"github.com/MarinX/monerorpc/polyseed"
+	"github.com/MarinX/monerorpc/wallet"
+)
+
+// Key types of wallet-rpc's query_key
+const (
+	KeyTypeMnemonic = "mnemonic"
+	KeyTypeViewKey  = "view_key"
+	KeyTypeSpendKey = "spend_key"
+)
+
+var (
+	// ErrMismatch is returned when a key or address does not belong to the keys
+	ErrMismatch = errors.New("keys: key does not match")
+	// ErrViewOnly is returned when the private spend key of view only keys is needed
+	ErrViewOnly = errors.New("keys: view only keys have no private spend key")
+	// ErrUnknownKeyType is returned for query_key types other than mnemonic, view_key and spend_key
+	ErrUnknownKeyType = errors.New("keys: unknown key type")
+)
+
+// Keys is the key set of a wallet
+type Keys struct {
+	Network address.Network
+	// SpendSecret is the private spend key, zero for view only keys
+	SpendSecret [32]byte
+	// ViewSecret is the private view key
+	ViewSecret [32]byte
+	// SpendPublic is the public spend key
+	SpendPublic [32]byte
+	// ViewPublic is the public view key
+	ViewPublic [32]byte
+
+	viewOnly bool
+	// recoveryKey is the key encoded by the seed, before its reduction into the spend key
+	recoveryKey [32]byte
+}
+
+// FromSpendKey derives the keys of a wallet from its private spend key.
+// The view key is Keccak256 of the spend key reduced modulo the group order, like monero's deterministic wallets.
+func FromSpendKey(network address.Network, spend [32]byte) (*Keys, error) {
+	if _, err := crypto.ParseScalar(spend); err != nil {
+		return nil, err
+	}
+	return fromRecoveryKey(network, spend)
+}
+
+// fromRecoveryKey derives the keys from the key encoded by a seed like monero's account_base::generate:
+// the spend key is the recovery key reduced modulo the group order, the view key is Keccak256 of the
+// unreduced recovery key, reduced
+func fromRecoveryKey(network address.Network, key [32]byte) (*Keys, error) {
+	spend := crypto.Bytes(crypto.Reduce(key))
+	spendPublic, err := crypto.PublicKey(spend)
+	if err != nil {
+		return nil, err
+	}
+	view := crypto.Bytes(crypto.HashToScalar(key[:]))
+	viewPublic, err := crypto.PublicKey(view)
+	if err != nil {
+		return nil, err
+	}
+	return &Keys{
+		Network:     network,
+		SpendSecret: spend,
+		ViewSecret:  view,
+		SpendPublic: spendPublic,
+		ViewPublic:  viewPublic,
+		recoveryKey: key,
+	}, nil
+}
+
+// FromSeed derives the keys from a 25 (or 24) word mnemonic seed or a 16 word polyseed.
+// Encrypted polyseeds must be decrypted with polyseed.Seed.Crypt and passed to FromPolyseed.
+func FromSeed(network address.Network, seed string) (*Keys, error) {
+	if len(strings.Fields(seed)) == polyseed.NumWords {
+		s, _, err := polyseed.Decode(seed)
+		if err != nil {
+			return nil, err
+		}
+		return FromPolyseed(network, s)
+	}
+	key, _, err := mnemonic.Decode(seed)
+	if err != nil {
+		return nil, err
+	}
+	// seeds encode the key before the reduction done when a wallet is restored
+	return fromRecoveryKey(network, key)
+}
+
+// FromPolyseed derives the keys from a polyseed, from its unreduced key like the wallets using polyseed
+func FromPolyseed(network address.Network, seed *polyseed.Seed) (*Keys, error) {
+	key, err := seed.Key()
+	if err != nil {
+		return nil, err
+	}
+	return fromRecoveryKey(network, key)
+}
+
+// FromViewKey returns the view only keys of a wallet from its private view key and public spend key
+func FromViewKey(network address.Network, view [32]byte, spendPublic [32]byte) (*Keys, error) {
+	viewPublic, err := crypto.PublicKey(view)
+	if err != nil {
+		return nil, err
+	}
+	if !crypto.CheckKey(spendPublic) {
+		return nil, crypto.ErrInvalidPoint
+	}
+	return &Keys{
+		Network:     network,
+		ViewSecret:  view,
+		SpendPublic: spendPublic,
+		ViewPublic:  viewPublic,
+		viewOnly:    true,
+	}, nil
+}
+
+// ViewOnly reports whether the private spend key is unknown
+func (k *Keys) ViewOnly() bool {
+	return k.viewOnly
+}
+
+// Address returns the primary address
+func (k *Keys) Address() *address.Address {
+	return &address.Address{
+		Network:  k.Network,
+		Type:     address.Standard,
+		SpendKey: k.SpendPublic,
+		ViewKey:  k.ViewPublic,
+	}
+}
+
+// Subaddresses returns a generator of the subaddresses of the wallet
+func (k *Keys) Subaddresses() (*address.Generator, error) {
+	return address.NewGenerator(k.Network, k.ViewSecret, k.SpendPublic)
+}
+
+// Mnemonic returns the 25 word seed of the keys in lang. The seed encodes the unreduced key of the seed the keys
+// were derived from, so that it restores the same view key.
+func (k *Keys) Mnemonic(lang *mnemonic.Language) (string, error) {
+	if k.viewOnly {
+		return "", ErrViewOnly
+	}
+	return mnemonic.EncodeString(k.recoveryKey, lang), nil
+}
+
+// GenerateFromKeysRequest builds a request creating the wallet with wallet-rpc's generate_from_keys,
+// a view only wallet for view only keys
+func (k *Keys) GenerateFromKeysRequest(filename, password string, restoreHeight uint64) *wallet.GenerateFromKeysRequest {
+	req := &wallet.GenerateFromKeysRequest{
+		RestoreHeight: restoreHeight,
+		Filename:      filename,
+		Address:       k.Address().String(),
+		Viewkey:       hex.EncodeToString(k.ViewSecret[:]),
+		Password:      password,
+	}
+	if !k.viewOnly {
+		req.Spendkey = hex.EncodeToString(k.SpendSecret[:])
+	}
+	return req
+}
+
+// VerifyAddress checks that addr is the primary address of the keys,
+// e.g. the address returned by GenerateFromKeys or RestoreDeterministicWallet
+func (k *Keys) VerifyAddress(addr string) error {
+	if expected := k.Address().String(); addr != expected {
+		return fmt.Errorf("%w: address %s, expected %s", ErrMismatch, addr, expected)
+	}
+	return nil
+}
+
+// VerifyQueryKey checks that the result of wallet-rpc's query_key for keyType matches the keys
+func (k *Keys) VerifyQueryKey(keyType string, res *wallet.QueryKeyResponse) error {
+	switch keyType {
+	case KeyTypeViewKey:
+		return k.verifyHex(keyType, res.Key, k.ViewSecret)
+	case KeyTypeSpendKey:
+		if k.viewOnly {
+			return ErrViewOnly
+		}
+		return k.verifyHex(keyType, res.Key, k.SpendSecret)
+	case KeyTypeMnemonic:
+		if k.viewOnly {
+			return ErrViewOnly
+		}
+		seed, err := FromSeed(k.Network, res.Key)
+		if err != nil {
+			return err
+		}
+		if seed.SpendSecret != k.SpendSecret {
+			return fmt.Errorf("%w: %s", ErrMismatch, keyType)
+		}
+		return nil
+	}
+	return fmt.Errorf("%w %q", ErrUnknownKeyType, keyType)
+}
+
+func (k *Keys) verifyHex(keyType, s string, expected [32]byte) error {
+	if s != hex.EncodeToString(expected[:]) {
+		return fmt.Errorf("%w: %s", ErrMismatch, keyType)
+	}
+	return nil
+}
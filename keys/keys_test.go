@@ -0,0 +1,120 @@
+package keys
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/MarinX/monerorpc/address"
+	"github.com/MarinX/monerorpc/mnemonic"
+	"github.com/MarinX/monerorpc/polyseed"
+	"github.com/MarinX/monerorpc/wallet"
+	"github.com/matryer/is"
+)
+
+const (
+	spendKey = "372fcc2abc6bc5015103aae4763822e45c4cfe775d163f97a9ebdd77b0d12c0c"
+	viewKey  = "8aa763d1c8d9da4ca75cb6ca22a021b5cca376c1367be8d62bcc9cdf4b926009"
+	primary  = "53zEYzu2hi3e97tdMTqTvSRAfFYXwxA7LBJEHLWvFnm699WgcsE8CJujENwNAQotKyY2u94vpbGEZTiwahuMcMfX3x6NFwY"
+)
+
+func key(s string) [32]byte {
+	var k [32]byte
+	hex.Decode(k[:], []byte(s))
+	return k
+}
+
+func TestFromSpendKey(t *testing.T) {
+	is := is.New(t)
+	k, err := FromSpendKey(address.Stagenet, key(spendKey))
+	is.NoErr(err)
+	is.Equal(hex.EncodeToString(k.ViewSecret[:]), viewKey)
+	is.Equal(hex.EncodeToString(k.SpendPublic[:]), "38e9908d33d034de0ba1281aa7afe3907b795cea14852b3d8fe276e8931cb130")
+	is.Equal(hex.EncodeToString(k.ViewPublic[:]), "b4cdbf52851002fc7b098b99536df8b9885aa6cb8db24e9fc46103674dc9421a")
+	is.Equal(k.Address().String(), primary)
+	is.NoErr(k.VerifyAddress(primary))
+	is.True(!k.ViewOnly())
+
+	req := k.GenerateFromKeysRequest("wallet", "pass", 1000)
+	is.Equal(*req, wallet.GenerateFromKeysRequest{
+		RestoreHeight: 1000,
+		Filename:      "wallet",
+		Address:       primary,
+		Spendkey:      spendKey,
+		Viewkey:       viewKey,
+		Password:      "pass",
+	})
+
+	_, err = FromSpendKey(address.Mainnet, [32]byte{31: 0xff})
+	is.True(err != nil)
+}
+
+func TestFromSeed(t *testing.T) {
+	is := is.New(t)
+	k, err := FromSeed(address.Mainnet, "wiggle drowning auburn aquarium attire meant impel phase soothe heron android mechanic inroads energy smog niece enforce syllabus exquisite lush bluntly rage siblings soda syllabus")
+	is.NoErr(err)
+	is.Equal(hex.EncodeToString(k.SpendSecret[:]), "0cca07dc4e90fc738fffdb2561dddd7a94d0dc8977d0229303d7509a10c9d705")
+
+	seed, err := k.Mnemonic(mnemonic.English)
+	is.NoErr(err)
+	is.NoErr(k.VerifyQueryKey(KeyTypeMnemonic, &wallet.QueryKeyResponse{Key: seed}))
+
+	// polyseed
+	ps, _, err := polyseed.Decode("raven tail swear infant grief assist regular lamp duck valid someone little harsh puppy airport language")
+	is.NoErr(err)
+	k, err = FromSeed(address.Mainnet, ps.String())
+	is.NoErr(err)
+	spend, err := ps.SpendKey()
+	is.NoErr(err)
+	is.Equal(k.SpendSecret, spend)
+	// the key of the polyseed is above the group order: the view key is derived from the unreduced key
+	is.Equal(hex.EncodeToString(k.ViewSecret[:]), "14b1a9d0990e76d690b1ddf9e6f06ca5e70f3e9834e22d474bca6f4baa9b9106")
+	is.Equal(k.Address().String(), "47AjPj7DVPQVGGXJXbbTMZWcKQDejGHYZChVkeujy8qPLgZrXzU7nfujSPdwiuHQxdg2XpMmH9GTzijZT5TmupPx6EBGvtr")
+
+	// the 25 word seed of the keys restores the same wallet
+	seed, err = k.Mnemonic(mnemonic.English)
+	is.NoErr(err)
+	legacy, err := FromSeed(address.Mainnet, seed)
+	is.NoErr(err)
+	is.Equal(legacy.Address().String(), k.Address().String())
+
+	_, err = FromSeed(address.Mainnet, "not a seed")
+	is.True(err != nil)
+}
+
+func TestViewOnly(t *testing.T) {
+	is := is.New(t)
+	full, err := FromSpendKey(address.Stagenet, key(spendKey))
+	is.NoErr(err)
+	k, err := FromViewKey(address.Stagenet, full.ViewSecret, full.SpendPublic)
+	is.NoErr(err)
+	is.True(k.ViewOnly())
+	is.Equal(k.Address().String(), primary)
+	is.Equal(k.GenerateFromKeysRequest("wallet", "", 0).Spendkey, "")
+
+	_, err = k.Mnemonic(mnemonic.English)
+	is.Equal(err, ErrViewOnly)
+	is.Equal(k.VerifyQueryKey(KeyTypeSpendKey, &wallet.QueryKeyResponse{Key: spendKey}), ErrViewOnly)
+
+	gen, err := k.Subaddresses()
+	is.NoErr(err)
+	is.Equal(gen.Address(1, 0).String(), "72c2F4L6XMu28Wf4e5yiVfKJcb4uDzvM9DxSAydF9o766RUiVqXawkhUcz7y59EBRrDafZB8DezLbLSrtb5xPL7s6PZ2zoj")
+}
+
+func TestVerify(t *testing.T) {
+	is := is.New(t)
+	k, err := FromSpendKey(address.Stagenet, key(spendKey))
+	is.NoErr(err)
+
+	is.NoErr(k.VerifyQueryKey(KeyTypeViewKey, &wallet.QueryKeyResponse{Key: viewKey}))
+	is.NoErr(k.VerifyQueryKey(KeyTypeSpendKey, &wallet.QueryKeyResponse{Key: spendKey}))
+	is.True(errors.Is(k.VerifyQueryKey(KeyTypeViewKey, &wallet.QueryKeyResponse{Key: spendKey}), ErrMismatch))
+	is.True(errors.Is(k.VerifyQueryKey("private", &wallet.QueryKeyResponse{}), ErrUnknownKeyType))
+
+	other, err := FromSeed(address.Stagenet, "wiggle drowning auburn aquarium attire meant impel phase soothe heron android mechanic inroads energy smog niece enforce syllabus exquisite lush bluntly rage siblings soda syllabus")
+	is.NoErr(err)
+	seed, err := other.Mnemonic(mnemonic.English)
+	is.NoErr(err)
+	is.True(errors.Is(k.VerifyQueryKey(KeyTypeMnemonic, &wallet.QueryKeyResponse{Key: seed}), ErrMismatch))
+	is.True(errors.Is(k.VerifyAddress(other.Address().String()), ErrMismatch))
+}
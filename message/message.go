@@ -0,0 +1,170 @@
+// Package message signs and verifies messages like wallet-rpc's sign and verify, without a wallet.
+//
+// Signatures are strings of the form "SigV2" followed by the base58 encoded (c, r) pair. Version 2
+// signatures hash the message together with both public keys of the address and the key used, version 1
+// signatures only hash the message and are still accepted when verifying.
+package message
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+
+	"filippo.io/edwards25519"
+	"github.com/MarinX/monerorpc/address"
+	"github.com/MarinX/monerorpc/base58"
+	"github.com/MarinX/monerorpc/crypto"
+	"github.com/MarinX/monerorpc/keys"
+	"github.com/MarinX/monerorpc/wallet"
+)
+
+// Signature types of wallet-rpc's sign and verify
+const (
+	SpendKey = "spend"
+	ViewKey  = "view"
+)
+
+const (
+	headerV1 = "SigV1"
+	headerV2 = "SigV2"
+	// hashKey is the domain separator of version 2 signatures, including the trailing NUL
+	hashKey = "MoneroMessageSignature\x00"
+)
+
+var (
+	// ErrUnknownSignatureType is returned for signature types other than spend and view
+	ErrUnknownSignatureType = errors.New("message: unknown signature type")
+	// ErrSubaddressViewKey is returned when signing with the view key of a subaddress
+	ErrSubaddressViewKey = errors.New("message: cannot sign with the view key of a subaddress")
+)
+
+// Result is the outcome of a verification
+type Result struct {
+	// Good is true if the signature is valid
+	Good bool
+	// Version of the signature, 1 or 2
+	Version int
+	// Old is true for version 1 signatures, which do not commit to the address and the key used
+	Old bool
+	// SignatureType is SpendKey or ViewKey
+	SignatureType string
+}
+
+// hash returns the hash signed by a version 2 signature
+func hash(data []byte, addr *address.Address, mode byte) [32]byte {
+	length := make([]byte, binary.MaxVarintLen64)
+	length = length[:binary.PutUvarint(length, uint64(len(data)))]
+	return crypto.Keccak256([]byte(hashKey), addr.SpendKey[:], addr.ViewKey[:], []byte{mode}, length, data)
+}
+
+// Verify checks a signature of data made by the address addr. A malformed signature is not good
+// but not an error, only a malformed address is.
+func Verify(data []byte, addr string, signature string) (*Result, error) {
+	a, err := address.Parse(addr)
+	if err != nil {
+		return nil, err
+	}
+	version := 0
+	switch {
+	case strings.HasPrefix(signature, headerV1):
+		version = 1
+	case strings.HasPrefix(signature, headerV2):
+		version = 2
+	default:
+		return &Result{}, nil
+	}
+	decoded, err := base58.Decode(signature[len(headerV1):])
+	if err != nil {
+		return &Result{}, nil
+	}
+	sig, ok := crypto.SignatureFromBytes(decoded)
+	if !ok {
+		return &Result{}, nil
+	}
+
+	h := crypto.Keccak256(data)
+	for mode, pub := range [][32]byte{a.SpendKey, a.ViewKey} {
+		if version == 2 {
+			h = hash(data, a, byte(mode))
+		}
+		if crypto.CheckSignature(h, pub, sig) {
+			return &Result{
+				Good:          true,
+				Version:       version,
+				Old:           version == 1,
+				SignatureType: []string{SpendKey, ViewKey}[mode],
+			}, nil
+		}
+	}
+	return &Result{}, nil
+}
+
+// VerifyRequest verifies like wallet-rpc's verify
+func VerifyRequest(req *wallet.VerifyRequest) (*wallet.VerifyResponse, error) {
+	res, err := Verify([]byte(req.Data), req.Address, req.Signature)
+	if err != nil {
+		return nil, err
+	}
+	return &wallet.VerifyResponse{
+		Good:          res.Good,
+		Version:       uint64(res.Version),
+		Old:           res.Old,
+		SignatureType: res.SignatureType,
+	}, nil
+}
+
+// Sign signs data with a version 2 signature by the address at index (major, minor) of the wallet k.
+// signatureType is SpendKey or ViewKey, only primary addresses can sign with the view key.
+func Sign(k *keys.Keys, data []byte, signatureType string, major, minor uint32) (string, error) {
+	gen, err := k.Subaddresses()
+	if err != nil {
+		return "", err
+	}
+	addr := gen.Address(major, minor)
+
+	var (
+		secret *edwards25519.Scalar
+		mode   byte
+	)
+	switch signatureType {
+	case SpendKey, "":
+		if k.ViewOnly() {
+			return "", keys.ErrViewOnly
+		}
+		if secret, err = crypto.ParseScalar(k.SpendSecret); err != nil {
+			return "", err
+		}
+		m, err := crypto.ParseScalar(gen.SecretKey(major, minor))
+		if err != nil {
+			return "", err
+		}
+		secret.Add(secret, m)
+	case ViewKey:
+		if major != 0 || minor != 0 {
+			return "", ErrSubaddressViewKey
+		}
+		if secret, err = crypto.ParseScalar(k.ViewSecret); err != nil {
+			return "", err
+		}
+		mode = 1
+	default:
+		return "", fmt.Errorf("%w %q", ErrUnknownSignatureType, signatureType)
+	}
+
+	pub := crypto.Bytes(new(edwards25519.Point).ScalarBaseMult(secret))
+	sig, err := crypto.GenerateSignature(hash(data, addr, mode), pub, secret)
+	if err != nil {
+		return "", err
+	}
+	return headerV2 + base58.Encode(sig.Bytes()), nil
+}
+
+// SignRequest signs like wallet-rpc's sign
+func SignRequest(k *keys.Keys, req *wallet.SignRequest) (*wallet.SignResponse, error) {
+	signature, err := Sign(k, []byte(req.Data), req.SignatureType, uint32(req.AccountIndex), uint32(req.AddressIndex))
+	if err != nil {
+		return nil, err
+	}
+	return &wallet.SignResponse{Signature: signature}, nil
+}
@@ -0,0 +1,121 @@
+package message
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/MarinX/monerorpc/address"
+	"github.com/MarinX/monerorpc/base58"
+	"github.com/MarinX/monerorpc/crypto"
+	"github.com/MarinX/monerorpc/keys"
+	"github.com/MarinX/monerorpc/wallet"
+	"github.com/matryer/is"
+)
+
+func testKeys(t *testing.T) *keys.Keys {
+	var spend [32]byte
+	hex.Decode(spend[:], []byte("372fcc2abc6bc5015103aae4763822e45c4cfe775d163f97a9ebdd77b0d12c0c"))
+	k, err := keys.FromSpendKey(address.Stagenet, spend)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return k
+}
+
+func TestSignVerify(t *testing.T) {
+	is := is.New(t)
+	k := testKeys(t)
+	data := []byte("sign in to example.com, nonce 42")
+	primary := k.Address().String()
+
+	sig, err := Sign(k, data, SpendKey, 0, 0)
+	is.NoErr(err)
+	is.Equal(sig[:5], "SigV2")
+	res, err := Verify(data, primary, sig)
+	is.NoErr(err)
+	is.Equal(*res, Result{Good: true, Version: 2, SignatureType: SpendKey})
+
+	sig, err = Sign(k, data, ViewKey, 0, 0)
+	is.NoErr(err)
+	res, err = Verify(data, primary, sig)
+	is.NoErr(err)
+	is.Equal(*res, Result{Good: true, Version: 2, SignatureType: ViewKey})
+
+	// other data, other address
+	res, err = Verify([]byte("sign in to evil.com, nonce 42"), primary, sig)
+	is.NoErr(err)
+	is.True(!res.Good)
+	res, err = Verify(data, "48ukkZtBSBRL8iva7k3p2sBVMLWTfNwsTbW1aVh5M84g21muDCssvCHTpoZCaSc6rq8M9QLZ3sQMrMn1bq2RD2anGnyHhtq", sig)
+	is.NoErr(err)
+	is.True(!res.Good)
+}
+
+func TestSignSubaddress(t *testing.T) {
+	is := is.New(t)
+	k := testKeys(t)
+	gen, err := k.Subaddresses()
+	is.NoErr(err)
+	sub := gen.Address(1, 0).String()
+
+	res, err := SignRequest(k, &wallet.SignRequest{Data: "hello", AccountIndex: 1})
+	is.NoErr(err)
+	verified, err := VerifyRequest(&wallet.VerifyRequest{Data: "hello", Address: sub, Signature: res.Signature})
+	is.NoErr(err)
+	is.Equal(*verified, wallet.VerifyResponse{Good: true, Version: 2, SignatureType: SpendKey})
+
+	// a subaddress signature does not verify for the primary address
+	verified, err = VerifyRequest(&wallet.VerifyRequest{Data: "hello", Address: k.Address().String(), Signature: res.Signature})
+	is.NoErr(err)
+	is.True(!verified.Good)
+
+	_, err = Sign(k, []byte("hello"), ViewKey, 1, 0)
+	is.Equal(err, ErrSubaddressViewKey)
+	_, err = Sign(k, []byte("hello"), "private", 0, 0)
+	is.True(errors.Is(err, ErrUnknownSignatureType))
+
+	viewOnly, err := keys.FromViewKey(k.Network, k.ViewSecret, k.SpendPublic)
+	is.NoErr(err)
+	_, err = Sign(viewOnly, []byte("hello"), SpendKey, 0, 0)
+	is.Equal(err, keys.ErrViewOnly)
+}
+
+func TestVerifyV1(t *testing.T) {
+	is := is.New(t)
+	k := testKeys(t)
+	data := []byte("legacy")
+	h := crypto.Keccak256(data)
+
+	for _, tt := range []struct {
+		secret, public [32]byte
+		expected       Result
+	}{
+		{k.SpendSecret, k.SpendPublic, Result{Good: true, Version: 1, Old: true, SignatureType: SpendKey}},
+		{k.ViewSecret, k.ViewPublic, Result{Good: true, Version: 1, Old: true, SignatureType: ViewKey}},
+	} {
+		secret, err := crypto.ParseScalar(tt.secret)
+		is.NoErr(err)
+		sig, err := crypto.GenerateSignature(h, tt.public, secret)
+		is.NoErr(err)
+		res, err := Verify(data, k.Address().String(), "SigV1"+base58.Encode(sig.Bytes()))
+		is.NoErr(err)
+		is.Equal(*res, tt.expected)
+	}
+}
+
+func TestVerifyMalformed(t *testing.T) {
+	is := is.New(t)
+	k := testKeys(t)
+	primary := k.Address().String()
+	sig, err := Sign(k, []byte("data"), SpendKey, 0, 0)
+	is.NoErr(err)
+
+	for _, s := range []string{"", "SigV3" + sig[5:], "SigV2", sig[:len(sig)-1], sig[:len(sig)-1] + "0", "SigV2" + base58.Encode(make([]byte, 64))} {
+		res, err := Verify([]byte("data"), primary, s)
+		is.NoErr(err)
+		is.Equal(*res, Result{})
+	}
+
+	_, err = Verify([]byte("data"), "not an address", sig)
+	is.True(errors.Is(err, address.ErrInvalidAddress))
+}
@@ -0,0 +1,282 @@
+// Package mnemonic encodes and decodes Monero's 25 word (Electrum style) mnemonic seeds.
+//
+// A seed is 24 words encoding the 32 bytes of the private spend key, three words per 4 bytes,
+// followed by a checksum word repeating one of the first 24 words. Words are identified by their
+// first PrefixLength characters, so a seed can be typed with prefixes only.
+//
+// Only the English wordlist is built in. Other wordlists of Monero's src/mnemonics can be added with Register.
+package mnemonic
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// WordListSize is the number of words of every wordlist
+const WordListSize = 1626
+
+// Number of words of a seed with and without the checksum word
+const (
+	SeedWords         = 25
+	SeedWordsNoChecks = 24
+)
+
+var (
+	// ErrInvalidSeed is returned for seeds with a wrong number of words or an invalid encoding
+	ErrInvalidSeed = errors.New("mnemonic: invalid seed")
+	// ErrUnknownWord is returned when a word is not in the wordlist
+	ErrUnknownWord = errors.New("mnemonic: unknown word")
+	// ErrInvalidChecksum is returned when the checksum word does not match
+	ErrInvalidChecksum = errors.New("mnemonic: invalid checksum word")
+	// ErrUnknownLanguage is returned when no registered wordlist contains all the words of a seed
+	ErrUnknownLanguage = errors.New("mnemonic: unknown language")
+	// ErrInvalidWordList is returned by NewLanguage for malformed wordlists
+	ErrInvalidWordList = errors.New("mnemonic: invalid wordlist")
+)
+
+// Language is a wordlist
+type Language struct {
+	// Name is the name used by wallet-rpc, e.g. "English" or "Deutsch"
+	Name string
+	// EnglishName is the name of the language in English, e.g. "German"
+	EnglishName string
+	// PrefixLength is the number of leading characters that identify a word
+	PrefixLength int
+
+	words    []string
+	index    map[string]int
+	prefixes map[string]int
+}
+
+// NewLanguage creates a language from one of Monero's wordlists. The list must hold WordListSize
+// unique words, in the order of Monero's source, whose prefixes of prefixLength characters are unique.
+func NewLanguage(name, englishName string, prefixLength int, words []string) (*Language, error) {
+	if len(words) != WordListSize {
+		return nil, fmt.Errorf("%w: %s has %d words, want %d", ErrInvalidWordList, name, len(words), WordListSize)
+	}
+	if prefixLength <= 0 {
+		return nil, fmt.Errorf("%w: %s prefix length %d", ErrInvalidWordList, name, prefixLength)
+	}
+	l := &Language{
+		Name:         name,
+		EnglishName:  englishName,
+		PrefixLength: prefixLength,
+		words:        append([]string(nil), words...),
+		index:        make(map[string]int, len(words)),
+		prefixes:     make(map[string]int, len(words)),
+	}
+	for i, w := range words {
+		key := strings.ToLower(w)
+		p := l.prefix(key)
+		if _, ok := l.index[key]; ok {
+			return nil, fmt.Errorf("%w: %s has duplicate word %q", ErrInvalidWordList, name, w)
+		}
+		if _, ok := l.prefixes[p]; ok {
+			return nil, fmt.Errorf("%w: %s has duplicate prefix %q", ErrInvalidWordList, name, p)
+		}
+		l.index[key] = i
+		l.prefixes[p] = i
+	}
+	return l, nil
+}
+
+func mustLanguage(name, englishName string, prefixLength int, words []string) *Language {
+	l, err := NewLanguage(name, englishName, prefixLength, words)
+	if err != nil {
+		panic(err)
+	}
+	return l
+}
+
+// prefix returns the first PrefixLength characters of word, or word if it is shorter
+func (l *Language) prefix(word string) string {
+	n := 0
+	for i := range word {
+		if n == l.PrefixLength {
+			return word[:i]
+		}
+		n++
+	}
+	return word
+}
+
+// Word returns the word at index i of the wordlist
+func (l *Language) Word(i int) string {
+	return l.words[i]
+}
+
+// Lookup returns the index of word in the wordlist. Like monero-wallet-cli, a word matches
+// when it is a full word of the list or starts with the unique prefix of one, case is ignored.
+func (l *Language) Lookup(word string) (int, bool) {
+	key := strings.ToLower(word)
+	if i, ok := l.index[key]; ok {
+		return i, true
+	}
+	if utf8.RuneCountInString(key) < l.PrefixLength {
+		return 0, false
+	}
+	i, ok := l.prefixes[l.prefix(key)]
+	return i, ok
+}
+
+// Complete returns the words starting with prefix, e.g. to suggest words while a seed is typed
+func (l *Language) Complete(prefix string) []string {
+	prefix = strings.ToLower(prefix)
+	var words []string
+	for _, w := range l.words {
+		if strings.HasPrefix(strings.ToLower(w), prefix) {
+			words = append(words, w)
+		}
+	}
+	return words
+}
+
+var (
+	mu        sync.RWMutex
+	languages = []*Language{English}
+)
+
+// Register adds a language to the ones used by Decode and Detect, replacing a language with the same name
+func Register(l *Language) {
+	mu.Lock()
+	defer mu.Unlock()
+	for i, existing := range languages {
+		if existing.Name == l.Name {
+			languages[i] = l
+			return
+		}
+	}
+	languages = append(languages, l)
+}
+
+// Languages returns the registered languages, in the order they are tried by Detect
+func Languages() []*Language {
+	mu.RLock()
+	defer mu.RUnlock()
+	return append([]*Language(nil), languages...)
+}
+
+// LanguageByName returns the registered language with the name or English name, case is ignored
+func LanguageByName(name string) (*Language, error) {
+	for _, l := range Languages() {
+		if strings.EqualFold(l.Name, name) || strings.EqualFold(l.EnglishName, name) {
+			return l, nil
+		}
+	}
+	return nil, fmt.Errorf("%w %q", ErrUnknownLanguage, name)
+}
+
+// Encode returns the 25 words of the seed of key in lang
+func Encode(key [32]byte, lang *Language) []string {
+	n := uint32(WordListSize)
+	words := make([]string, 0, SeedWords)
+	for i := 0; i < len(key); i += 4 {
+		val := binary.LittleEndian.Uint32(key[i:])
+		w1 := val % n
+		w2 := (val/n + w1) % n
+		w3 := (val/n/n + w2) % n
+		words = append(words, lang.words[w1], lang.words[w2], lang.words[w3])
+	}
+	return append(words, words[lang.checksumIndex(words)])
+}
+
+// EncodeString is like Encode but returns the words separated by spaces
+func EncodeString(key [32]byte, lang *Language) string {
+	return strings.Join(Encode(key, lang), " ")
+}
+
+// checksumIndex returns the index of the checksum word among the first 24 words
+func (l *Language) checksumIndex(words []string) int {
+	var b strings.Builder
+	for _, w := range words[:SeedWordsNoChecks] {
+		b.WriteString(l.prefix(w))
+	}
+	return int(crc32.ChecksumIEEE([]byte(b.String())) % SeedWordsNoChecks)
+}
+
+// Decode detects the language of seed and returns the key it encodes.
+// Seeds of 24 words, without checksum word, are accepted like monero-wallet-cli does.
+func Decode(seed string) ([32]byte, *Language, error) {
+	lang, err := Detect(seed)
+	if err != nil {
+		return [32]byte{}, nil, err
+	}
+	key, err := DecodeLanguage(seed, lang)
+	return key, lang, err
+}
+
+// DecodeLanguage returns the key encoded by seed in lang
+func DecodeLanguage(seed string, lang *Language) ([32]byte, error) {
+	var key [32]byte
+	words := strings.Fields(seed)
+	if len(words) != SeedWords && len(words) != SeedWordsNoChecks {
+		return key, fmt.Errorf("%w: %d words, want %d", ErrInvalidSeed, len(words), SeedWords)
+	}
+	indices := make([]uint32, len(words))
+	for i, w := range words {
+		index, ok := lang.Lookup(w)
+		if !ok {
+			return key, fmt.Errorf("%w %q", ErrUnknownWord, w)
+		}
+		indices[i] = uint32(index)
+		// normalize prefixes and case for the checksum
+		words[i] = lang.words[index]
+	}
+
+	n := uint32(WordListSize)
+	for i := 0; i < SeedWordsNoChecks; i += 3 {
+		w1, w2, w3 := indices[i], indices[i+1], indices[i+2]
+		// uint32 arithmetic wraps like monero's
+		val := w1 + n*((n-w1+w2)%n) + n*n*((n-w2+w3)%n)
+		if val%n != w1 {
+			return key, fmt.Errorf("%w: words %d to %d", ErrInvalidSeed, i+1, i+3)
+		}
+		binary.LittleEndian.PutUint32(key[i/3*4:], val)
+	}
+
+	if len(words) == SeedWords && indices[SeedWords-1] != indices[lang.checksumIndex(words)] {
+		return [32]byte{}, ErrInvalidChecksum
+	}
+	return key, nil
+}
+
+// Detect returns the first registered language containing all the words of seed,
+// preferring full words over prefixes
+func Detect(seed string) (*Language, error) {
+	words := strings.Fields(seed)
+	if len(words) == 0 {
+		return nil, ErrInvalidSeed
+	}
+	langs := Languages()
+	for _, l := range langs {
+		if containsAll(words, func(w string) bool { _, ok := l.index[strings.ToLower(w)]; return ok }) {
+			return l, nil
+		}
+	}
+	for _, l := range langs {
+		if containsAll(words, func(w string) bool { _, ok := l.Lookup(w); return ok }) {
+			return l, nil
+		}
+	}
+	return nil, ErrUnknownLanguage
+}
+
+func containsAll(words []string, contains func(string) bool) bool {
+	for _, w := range words {
+		if !contains(w) {
+			return false
+		}
+	}
+	return true
+}
+
+// Validate checks the words and the checksum of seed, e.g. before calling RestoreDeterministicWallet
+func Validate(seed string) error {
+	_, _, err := Decode(seed)
+	return err
+}
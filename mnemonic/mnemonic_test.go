@@ -0,0 +1,120 @@
+package mnemonic
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+const (
+	seed   = "wiggle drowning auburn aquarium attire meant impel phase soothe heron android mechanic inroads energy smog niece enforce syllabus exquisite lush bluntly rage siblings soda syllabus"
+	seedSK = "0cca07dc4e90fc738fffdb2561dddd7a94d0dc8977d0229303d7509a10c9d705"
+)
+
+func TestDecode(t *testing.T) {
+	is := is.New(t)
+	key, lang, err := Decode(seed)
+	is.NoErr(err)
+	is.Equal(lang, English)
+	is.Equal(hex.EncodeToString(key[:]), seedSK)
+	is.Equal(EncodeString(key, English), seed)
+}
+
+func TestDecodePrefixes(t *testing.T) {
+	is := is.New(t)
+	var prefixes []string
+	for _, w := range strings.Fields(seed) {
+		prefixes = append(prefixes, strings.ToUpper(w[:3])+"x")
+	}
+	key, lang, err := Decode("  " + strings.Join(prefixes, "\n ") + " ")
+	is.NoErr(err)
+	is.Equal(lang, English)
+	is.Equal(hex.EncodeToString(key[:]), seedSK)
+
+	_, ok := English.Lookup("wi")
+	is.True(!ok)
+	is.Equal(English.Complete("zo"), []string{"zodiac", "zombie", "zones", "zoom"})
+}
+
+func TestDecodeWithoutChecksum(t *testing.T) {
+	is := is.New(t)
+	words := strings.Fields(seed)
+	key, err := DecodeLanguage(strings.Join(words[:24], " "), English)
+	is.NoErr(err)
+	is.Equal(hex.EncodeToString(key[:]), seedSK)
+}
+
+func TestRoundTrip(t *testing.T) {
+	is := is.New(t)
+	for i := 0; i < 100; i++ {
+		var key [32]byte
+		_, err := rand.Read(key[:])
+		is.NoErr(err)
+		words := Encode(key, English)
+		is.Equal(len(words), SeedWords)
+		decoded, err := DecodeLanguage(strings.Join(words, " "), English)
+		is.NoErr(err)
+		is.Equal(decoded, key)
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	is := is.New(t)
+	words := strings.Fields(seed)
+
+	err := Validate(strings.Join(words[:23], " "))
+	is.True(errors.Is(err, ErrInvalidSeed))
+
+	bad := append([]string(nil), words...)
+	bad[24] = "zoom"
+	err = Validate(strings.Join(bad, " "))
+	is.True(errors.Is(err, ErrInvalidChecksum))
+
+	bad = append([]string(nil), words...)
+	bad[3] = "xylophone"
+	_, err = DecodeLanguage(strings.Join(bad, " "), English)
+	is.True(errors.Is(err, ErrUnknownWord))
+	err = Validate(strings.Join(bad, " "))
+	is.True(errors.Is(err, ErrUnknownLanguage))
+}
+
+func TestRegister(t *testing.T) {
+	is := is.New(t)
+
+	_, err := NewLanguage("Short", "Short", 3, []string{"a", "b"})
+	is.True(errors.Is(err, ErrInvalidWordList))
+
+	words := make([]string, WordListSize)
+	for i := range words {
+		words[i] = "x" + English.Word(i)
+	}
+	_, err = NewLanguage("Prefix", "Prefix", 3, words)
+	is.True(errors.Is(err, ErrInvalidWordList)) // prefixes are not unique
+
+	lang, err := NewLanguage("Xnglish", "Test", 4, words)
+	is.NoErr(err)
+	Register(lang)
+	defer func() {
+		mu.Lock()
+		languages = languages[:len(languages)-1]
+		mu.Unlock()
+	}()
+
+	found, err := LanguageByName("test")
+	is.NoErr(err)
+	is.Equal(found, lang)
+
+	var key [32]byte
+	key[0] = 42
+	got, detected, err := Decode(EncodeString(key, lang))
+	is.NoErr(err)
+	is.Equal(detected, lang)
+	is.Equal(got, key)
+
+	_, err = LanguageByName("Klingon")
+	is.True(errors.Is(err, ErrUnknownLanguage))
+}
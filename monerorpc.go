@@ -2,12 +2,17 @@ package monerorpc
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"strings"
+	"sync/atomic"
 
 	"github.com/MarinX/monerorpc/daemon"
+	"github.com/MarinX/monerorpc/epee"
 	"github.com/MarinX/monerorpc/wallet"
-	"github.com/gabstv/httpdigest"
 	"github.com/gorilla/rpc/v2/json2"
 )
 
@@ -18,72 +23,386 @@ const (
 	StagnetURI = "http://127.0.0.1:38080/json_rpc"
 	// ProdnetURI local production monerod instance
 	ProdnetURI = "http://127.0.0.1:18080/json_rpc"
+	// WalletTestnetURI local testnet monero-wallet-rpc instance
+	WalletTestnetURI = "http://127.0.0.1:28082/json_rpc"
+	// WalletStagnetURI local stagnet monero-wallet-rpc instance
+	WalletStagnetURI = "http://127.0.0.1:38082/json_rpc"
+	// WalletProdnetURI local production monero-wallet-rpc instance
+	WalletProdnetURI = "http://127.0.0.1:18082/json_rpc"
 )
 
 // MoneroRPC holds json rpc http client for various monero calls
 type MoneroRPC struct {
-	client *http.Client
-	uri    string
+	wallet *Endpoint
+	daemon *Endpoint
 	Wallet wallet.Wallet
 	Daemon daemon.Daemon
 }
 
-// New creates a new MoneroRPC client
+// Endpoint is a single monero json rpc server, either monero-wallet-rpc or monerod
+type Endpoint struct {
+	client *http.Client
+	// base is the client as supplied by the caller, before digest authentication is added
+	base  *http.Client
+	uri   string
+	retry *RetryPolicy
+	// interceptors wrap every call made on the endpoint
+	interceptors []Interceptor
+	// noBatch is set once the server rejected a json rpc batch
+	noBatch int32
+	// kind tells whether the server is monero-wallet-rpc or monerod, when known
+	kind endpointKind
+}
+
+// endpointKind is the kind of server behind an endpoint
+type endpointKind int
+
+const (
+	kindUnknown endpointKind = iota
+	kindWallet
+	kindDaemon
+)
+
+// daemonError reports whether an error sent by the server for method comes from monerod.
+// Endpoints shared by Wallet and Daemon calls tell by the method.
+func (e *Endpoint) daemonError(method string) bool {
+	if e.kind == kindUnknown {
+		return daemonMethods[method]
+	}
+	return e.kind == kindDaemon
+}
+
+// New creates a new MoneroRPC client where both Wallet and Daemon use the same endpoint
 func New(endpoint string, httpClient *http.Client) *MoneroRPC {
 	cli := http.DefaultClient
 	if httpClient != nil {
 		cli = httpClient
 	}
-	client := &MoneroRPC{
+	e := &Endpoint{
 		client: cli,
+		base:   cli,
 		uri:    endpoint,
 	}
-	client.Wallet = wallet.New(client)
-	client.Daemon = daemon.New(client)
+	client := &MoneroRPC{
+		wallet: e,
+		daemon: e,
+	}
+	client.Wallet = wallet.New(client.wallet)
+	client.Daemon = daemon.New(client.daemon)
+	return client
+}
+
+// NewClient creates a new MoneroRPC client configured by options,
+// allowing Wallet and Daemon to use their own URI, http client and credentials.
+func NewClient(opts ...Option) *MoneroRPC {
+	cfg := &config{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	client := &MoneroRPC{
+		wallet: cfg.wallet.endpoint(),
+		daemon: cfg.daemon.endpoint(),
+	}
+	client.wallet.kind = kindWallet
+	client.daemon.kind = kindDaemon
+	client.wallet.retry = cfg.retry
+	client.daemon.retry = cfg.retry
+	client.Use(cfg.interceptors...)
+	client.Wallet = wallet.New(client.wallet)
+	client.Daemon = daemon.New(client.daemon)
 	return client
 }
 
-// SetAuth sets digest username and password to be used with client
+// SetAuth sets digest username and password to be used with both Wallet and Daemon.
+// Authentication wraps the transport of the http client given to New, which is left untouched.
 func (c *MoneroRPC) SetAuth(username, password string) *MoneroRPC {
-	c.client.Transport = httpdigest.New(username, password)
+	c.wallet.setAuth(username, password)
+	if c.daemon != c.wallet {
+		c.daemon.setAuth(username, password)
+	}
 	return c
 }
 
-// Do calls monero json rpc server, usefull if you are calling undocumented API
+// SetWalletAuth sets digest username and password used only by Wallet calls
+func (c *MoneroRPC) SetWalletAuth(username, password string) *MoneroRPC {
+	if c.wallet == c.daemon {
+		c.wallet = c.wallet.clone()
+		c.Wallet = wallet.New(c.wallet)
+	}
+	c.wallet.setAuth(username, password)
+	return c
+}
+
+// SetDaemonAuth sets digest username and password used only by Daemon calls
+func (c *MoneroRPC) SetDaemonAuth(username, password string) *MoneroRPC {
+	if c.daemon == c.wallet {
+		c.daemon = c.daemon.clone()
+		c.Daemon = daemon.New(c.daemon)
+	}
+	c.daemon.setAuth(username, password)
+	return c
+}
+
+// WalletEndpoint returns the endpoint used for monero-wallet-rpc calls
+func (c *MoneroRPC) WalletEndpoint() *Endpoint {
+	return c.wallet
+}
+
+// DaemonEndpoint returns the endpoint used for monerod calls
+func (c *MoneroRPC) DaemonEndpoint() *Endpoint {
+	return c.daemon
+}
+
+// Do calls monero json rpc server, usefull if you are calling undocumented API.
+// The call goes to the wallet endpoint, or to the daemon endpoint if no wallet URI is configured.
 func (c *MoneroRPC) Do(method string, req interface{}, res interface{}) error {
+	return c.DoContext(context.Background(), method, req, res)
+}
+
+// DoContext is like Do but the request is bound to ctx, so it is aborted
+// when the context is canceled or its deadline expires.
+func (c *MoneroRPC) DoContext(ctx context.Context, method string, req interface{}, res interface{}) error {
+	if c.wallet.uri == "" {
+		return c.daemon.DoContext(ctx, method, req, res)
+	}
+	return c.wallet.DoContext(ctx, method, req, res)
+}
+
+// DoOther calls one of monerod's path based endpoints (e.g. /get_height) which do not use the json rpc envelope
+func (c *MoneroRPC) DoOther(ctx context.Context, path string, req interface{}, res interface{}) error {
+	return c.daemon.DoOther(ctx, path, req, res)
+}
+
+// DoBinary calls one of monerod's .bin endpoints (e.g. /get_blocks.bin) which speak epee portable storage
+func (c *MoneroRPC) DoBinary(ctx context.Context, path string, req interface{}, res interface{}) error {
+	return c.daemon.DoBinary(ctx, path, req, res)
+}
+
+// setAuth replaces the digest credentials of the endpoint, empty credentials remove authentication
+func (e *Endpoint) setAuth(username, password string) {
+	if username == "" && password == "" {
+		e.client = e.base
+		return
+	}
+	e.client = withDigestAuth(e.base, username, password)
+}
+
+// clone returns a copy of the endpoint which can be configured on its own
+func (e *Endpoint) clone() *Endpoint {
+	return &Endpoint{
+		client:       e.client,
+		base:         e.base,
+		uri:          e.uri,
+		retry:        e.retry,
+		interceptors: e.interceptors[:len(e.interceptors):len(e.interceptors)],
+		noBatch:      atomic.LoadInt32(&e.noBatch),
+		kind:         e.kind,
+	}
+}
+
+// URI returns the json rpc URI of the endpoint
+func (e *Endpoint) URI() string {
+	return e.uri
+}
+
+// Do calls the json rpc method on this endpoint
+func (e *Endpoint) Do(method string, req interface{}, res interface{}) error {
+	return e.DoContext(context.Background(), method, req, res)
+}
+
+// DoContext is like Do but the request is bound to ctx
+func (e *Endpoint) DoContext(ctx context.Context, method string, req interface{}, res interface{}) error {
+	return e.intercept(ctx, method, req, res, func(ctx context.Context, method string, req interface{}, res interface{}) error {
+		return e.retry.do(ctx, method, func() error {
+			return e.call(ctx, method, req, res)
+		})
+	})
+}
+
+// call makes a single json rpc call
+func (e *Endpoint) call(ctx context.Context, method string, req interface{}, res interface{}) error {
 	buff, err := json2.EncodeClientRequest(method, req)
 	if err != nil {
-		return fmt.Errorf("error creating encoded request %v", err)
+		return &Error{Method: method, Err: fmt.Errorf("error creating encoded request %v", err)}
 	}
 
-	httpReq, err := http.NewRequest("POST", c.uri, bytes.NewReader(buff))
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", e.uri, bytes.NewReader(buff))
 	if err != nil {
-		return fmt.Errorf("error creating http request %v", err)
+		return &Error{Method: method, Err: fmt.Errorf("error creating http request %v", err)}
 	}
 	httpReq.Header.Set("Content-Type", "application/json")
+	setHeaders(ctx, httpReq)
 
-	httpResp, err := c.client.Do(httpReq)
+	httpResp, err := e.client.Do(httpReq)
 	if err != nil {
-		return err
+		return &Error{Method: method, Err: err, transport: true}
 	}
 	defer httpResp.Body.Close()
 	if httpResp.StatusCode == http.StatusUnauthorized {
-		return fmt.Errorf("unauthorized - invalid username or password")
+		return &Error{Method: method, HTTPStatus: httpResp.StatusCode, Err: ErrUnauthorized}
 	}
 
-	if res != nil {
-		err = json2.DecodeClientResponse(httpResp.Body, res)
-		if err == json2.ErrNullResult {
-			return nil
+	if res == nil {
+		// the result is not wanted, but an error sent instead of it is
+		res = new(json.RawMessage)
+	}
+	body, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return &Error{Method: method, HTTPStatus: httpResp.StatusCode, Err: err, transport: true}
+	}
+	err = json2.DecodeClientResponse(bytes.NewReader(body), res)
+	if err == nil {
+		if e.retry != nil && resultBusy(body) {
+			return errBusyResult
 		}
+		return nil
+	}
+	if err == json2.ErrNullResult {
+		return nil
+	}
+	if rpcErr, ok := err.(*json2.Error); ok {
 		// Some monero-wallet-rpc versions return {"error":{"code":0,"message":""}}
 		// instead of an empty result (e.g. get_transfers with pool:true when the pool
 		// is empty). Code 0 with no message is not a valid JSON-RPC error — treat it
 		// the same as a null result.
-		if rpcErr, ok := err.(*json2.Error); ok && rpcErr.Code == 0 && rpcErr.Message == "" && rpcErr.Data == nil {
+		if rpcErr.Code == 0 && rpcErr.Message == "" && rpcErr.Data == nil {
 			return nil
 		}
+		return &Error{
+			Code:       int(rpcErr.Code),
+			Message:    rpcErr.Message,
+			Data:       rpcErr.Data,
+			Method:     method,
+			HTTPStatus: httpResp.StatusCode,
+			Daemon:     e.daemonError(method),
+		}
+	}
+	return &Error{Method: method, HTTPStatus: httpResp.StatusCode, Err: fmt.Errorf("error decoding response %v", err)}
+}
+
+// BaseURI returns the URI of the endpoint without the json_rpc path
+func (e *Endpoint) BaseURI() string {
+	return strings.TrimSuffix(strings.TrimSuffix(e.uri, "/"), "/json_rpc")
+}
+
+// DoOther posts req as a plain JSON body to path relative to BaseURI and decodes the JSON reply into res.
+// A reply whose status is not OK is returned as an error, after res has been populated.
+func (e *Endpoint) DoOther(ctx context.Context, path string, req interface{}, res interface{}) error {
+	return e.intercept(ctx, path, req, res, func(ctx context.Context, path string, req interface{}, res interface{}) error {
+		return e.retry.do(ctx, path, func() error {
+			return e.doOther(ctx, path, req, res)
+		})
+	})
+}
+
+func (e *Endpoint) doOther(ctx context.Context, path string, req interface{}, res interface{}) error {
+	if req == nil {
+		req = struct{}{}
+	}
+	buff, err := json.Marshal(req)
+	if err != nil {
+		return &Error{Method: path, Err: fmt.Errorf("error creating encoded request %v", err)}
+	}
+
+	body, err := e.post(ctx, path, "application/json", buff)
+	if err != nil {
+		return err
+	}
+	if res != nil {
+		if err = json.Unmarshal(body, res); err != nil {
+			return &Error{Method: path, HTTPStatus: http.StatusOK, Err: fmt.Errorf("error decoding response %v", err)}
+		}
+	}
+
+	var status struct {
+		Status string `json:"status"`
+	}
+	if err = json.Unmarshal(body, &status); err != nil {
+		return &Error{Method: path, HTTPStatus: http.StatusOK, Err: fmt.Errorf("error decoding response %v", err)}
+	}
+	return checkStatus(path, status.Status)
+}
+
+// DoBinary posts req encoded as epee portable storage to path relative to BaseURI and decodes the reply into res.
+// A reply whose status is not OK is returned as an error, after res has been populated.
+func (e *Endpoint) DoBinary(ctx context.Context, path string, req interface{}, res interface{}) error {
+	return e.intercept(ctx, path, req, res, func(ctx context.Context, path string, req interface{}, res interface{}) error {
+		return e.retry.do(ctx, path, func() error {
+			return e.doBinary(ctx, path, req, res)
+		})
+	})
+}
+
+func (e *Endpoint) doBinary(ctx context.Context, path string, req interface{}, res interface{}) error {
+	if req == nil {
+		req = struct{}{}
+	}
+	buff, err := epee.Marshal(req)
+	if err != nil {
+		return &Error{Method: path, Err: fmt.Errorf("error creating encoded request %v", err)}
+	}
+
+	body, err := e.post(ctx, path, "application/octet-stream", buff)
+	if err != nil {
+		return err
+	}
+	if res != nil {
+		if err = epee.Unmarshal(body, res); err != nil {
+			return &Error{Method: path, HTTPStatus: http.StatusOK, Err: fmt.Errorf("error decoding response %v", err)}
+		}
+	}
+
+	var status struct {
+		Status string `epee:"status"`
+	}
+	if err = epee.Unmarshal(body, &status); err != nil {
+		return &Error{Method: path, HTTPStatus: http.StatusOK, Err: fmt.Errorf("error decoding response %v", err)}
+	}
+	return checkStatus(path, status.Status)
+}
+
+// post sends body to path relative to BaseURI and returns the reply body
+func (e *Endpoint) post(ctx context.Context, path string, contentType string, body []byte) ([]byte, error) {
+	uri := e.BaseURI() + "/" + strings.TrimPrefix(path, "/")
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", uri, bytes.NewReader(body))
+	if err != nil {
+		return nil, &Error{Method: path, Err: fmt.Errorf("error creating http request %v", err)}
 	}
+	httpReq.Header.Set("Content-Type", contentType)
+	setHeaders(ctx, httpReq)
 
-	return err
+	httpResp, err := e.client.Do(httpReq)
+	if err != nil {
+		return nil, &Error{Method: path, Err: err, transport: true}
+	}
+	defer httpResp.Body.Close()
+	if httpResp.StatusCode == http.StatusUnauthorized {
+		return nil, &Error{Method: path, HTTPStatus: httpResp.StatusCode, Err: ErrUnauthorized}
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		return nil, &Error{Method: path, HTTPStatus: httpResp.StatusCode}
+	}
+	reply, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, &Error{Method: path, HTTPStatus: httpResp.StatusCode, Err: err, transport: true}
+	}
+	return reply, nil
+}
+
+// resultBusy reports whether the status of a json rpc result is BUSY
+func resultBusy(body []byte) bool {
+	var reply struct {
+		Result struct {
+			Status string `json:"status"`
+		} `json:"result"`
+	}
+	return json.Unmarshal(body, &reply) == nil && reply.Result.Status == "BUSY"
+}
+
+// checkStatus turns a non OK status of a path based endpoint into an error
+func checkStatus(path string, status string) error {
+	if status != "" && status != "OK" {
+		return &Error{Method: path, HTTPStatus: http.StatusOK, Status: status}
+	}
+	return nil
 }
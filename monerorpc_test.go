@@ -0,0 +1,395 @@
+package monerorpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MarinX/monerorpc/daemon"
+	"github.com/MarinX/monerorpc/epee"
+	"github.com/MarinX/monerorpc/wallet"
+	"github.com/matryer/is"
+)
+
+func setupServer(t *testing.T, output string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		buff, _ := io.ReadAll(req.Body)
+		t.Log(string(buff))
+		rw.Write([]byte(output))
+	}))
+	return server
+}
+
+func TestDoContext(t *testing.T) {
+	output := `{
+		"id": "0",
+		"jsonrpc": "2.0",
+		"result": {
+		  "count": 993163,
+		  "status": "OK",
+		  "untrusted": false
+		}
+	  }`
+	server := setupServer(t, output)
+	defer server.Close()
+
+	client := New(server.URL, server.Client())
+
+	var res struct {
+		Count uint64 `json:"count"`
+	}
+	err := client.DoContext(context.Background(), "get_block_count", nil, &res)
+	is.New(t).NoErr(err)
+	is.New(t).Equal(res.Count, uint64(993163))
+}
+
+func TestDoContextDeadline(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		io.ReadAll(req.Body)
+		<-req.Context().Done()
+	}))
+	defer server.Close()
+
+	client := New(server.URL, server.Client())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := client.Daemon.GetInfoContext(ctx)
+	is.New(t).True(errors.Is(err, context.DeadlineExceeded))
+}
+
+type countingTransport struct {
+	calls int
+}
+
+func (c *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	c.calls++
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestNewClientSeparateEndpoints(t *testing.T) {
+	walletServer := setupServer(t, `{"id": "0", "jsonrpc": "2.0", "result": {"version": 65539}}`)
+	defer walletServer.Close()
+	daemonServer := setupServer(t, `{"id": "0", "jsonrpc": "2.0", "result": {"count": 993163}}`)
+	defer daemonServer.Close()
+
+	transport := &countingTransport{}
+	client := NewClient(
+		WithWallet(walletServer.URL, &http.Client{Transport: transport}),
+		WithWalletAuth("username", "password"),
+		WithDaemon(daemonServer.URL, nil),
+	)
+	is := is.New(t)
+
+	ver, err := client.Wallet.GetVersion()
+	is.NoErr(err)
+	is.Equal(ver.Version, uint64(65539))
+	is.Equal(transport.calls, 1)
+
+	count, err := client.Daemon.GetBlockCount()
+	is.NoErr(err)
+	is.Equal(count.Count, uint64(993163))
+	is.Equal(transport.calls, 1)
+
+	is.Equal(client.WalletEndpoint().URI(), walletServer.URL)
+	is.Equal(client.DaemonEndpoint().URI(), daemonServer.URL)
+}
+
+func TestNewClientDoWithoutWallet(t *testing.T) {
+	server := setupServer(t, `{"id": "0", "jsonrpc": "2.0", "result": {"count": 993163}}`)
+	defer server.Close()
+
+	client := NewClient(WithDaemon(server.URL, server.Client()))
+
+	var res struct {
+		Count uint64 `json:"count"`
+	}
+	err := client.Do("get_block_count", nil, &res)
+	is.New(t).NoErr(err)
+	is.New(t).Equal(res.Count, uint64(993163))
+}
+
+func TestDoOther(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/get_height":
+			rw.Write([]byte(`{"hash": "7e23a28cfa6df925d5b63940baf60b83c0cbb65da95f49b19e7cf0ce7dd709ce", "height": 2287217, "status": "OK"}`))
+		case "/send_raw_transaction":
+			rw.Write([]byte(`{"double_spend": true, "reason": "double spend", "status": "Failed"}`))
+		default:
+			rw.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := New(server.URL+"/json_rpc", server.Client())
+	is := is.New(t)
+
+	height, err := client.Daemon.GetHeight()
+	is.NoErr(err)
+	is.Equal(height.Height, uint64(2287217))
+
+	var res struct {
+		DoubleSpend bool   `json:"double_spend"`
+		Reason      string `json:"reason"`
+	}
+	err = client.DoOther(context.Background(), "/send_raw_transaction", nil, &res)
+	is.True(err != nil)
+	is.True(res.DoubleSpend)
+	is.Equal(res.Reason, "double spend")
+
+	err = client.DoOther(context.Background(), "/missing", nil, nil)
+	is.True(err != nil)
+}
+
+func TestDoBinary(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		var in struct {
+			Heights []uint64 `epee:"heights"`
+		}
+		if err := epee.Unmarshal(body, &in); err != nil {
+			t.Error(err)
+		}
+		status := "OK"
+		if len(in.Heights) == 0 {
+			status = "BUSY"
+		}
+		buff, _ := epee.Marshal(map[string]interface{}{
+			"blocks": []map[string]interface{}{{"block": []byte{byte(len(in.Heights))}}},
+			"status": status,
+		})
+		rw.Write(buff)
+	}))
+	defer server.Close()
+
+	client := New(server.URL+"/json_rpc", server.Client())
+	is := is.New(t)
+
+	res, err := client.Daemon.GetBlocksByHeightBin(&daemon.GetBlocksByHeightBinRequest{Heights: []uint64{1, 2}})
+	is.NoErr(err)
+	is.Equal(res.Blocks[0].Block, []byte{2})
+
+	_, err = client.Daemon.GetBlocksByHeightBin(&daemon.GetBlocksByHeightBinRequest{})
+	is.True(err != nil)
+}
+
+func TestErrorCode(t *testing.T) {
+	output := `{
+		"id": "0",
+		"jsonrpc": "2.0",
+		"error": {
+		  "code": -17,
+		  "message": "not enough money"
+		}
+	  }`
+	server := setupServer(t, output)
+	defer server.Close()
+
+	client := New(server.URL, server.Client())
+	is := is.New(t)
+
+	var res struct{}
+	err := client.Do("transfer", nil, &res)
+	is.True(errors.Is(err, ErrNotEnoughMoney))
+	is.True(!errors.Is(err, ErrNotOpenWallet))
+	is.True(!errors.Is(err, ErrTransport))
+
+	var rpcErr *Error
+	is.True(errors.As(err, &rpcErr))
+	is.Equal(rpcErr.Code, -17)
+	is.Equal(rpcErr.Message, "not enough money")
+	is.Equal(rpcErr.Method, "transfer")
+	is.Equal(rpcErr.HTTPStatus, http.StatusOK)
+	is.Equal(err.Error(), "transfer: not enough money (code -17)")
+}
+
+func TestErrorCodeDaemon(t *testing.T) {
+	server := setupServer(t, `{"id": "0", "jsonrpc": "2.0", "error": {"code": -9, "message": "Core is busy"}}`)
+	defer server.Close()
+	is := is.New(t)
+
+	client := NewClient(WithDaemon(server.URL, server.Client()))
+	_, err := client.Daemon.GetInfo()
+	is.True(errors.Is(err, ErrCoreBusy))
+	is.True(!errors.Is(err, ErrWrongSignature))
+	var rpcErr *Error
+	is.True(errors.As(err, &rpcErr))
+	is.True(rpcErr.Daemon)
+
+	// an endpoint shared by Wallet and Daemon tells by the method
+	reserve := setupServer(t, `{"id": "0", "jsonrpc": "2.0", "error": {"code": -3, "message": "Too big reserved size"}}`)
+	defer reserve.Close()
+	client = New(reserve.URL, reserve.Client())
+	_, err = client.Daemon.GetBlockTemplate(&daemon.GetBlockTemplateRequest{ReserveSize: 300})
+	is.True(errors.Is(err, ErrTooBigReserveSize))
+	is.True(!errors.Is(err, ErrDaemonIsBusy))
+	_, err = client.Wallet.GetBalance(&wallet.GetBalanceRequest{})
+	is.True(errors.Is(err, ErrDaemonIsBusy))
+	is.True(!errors.Is(err, ErrTooBigReserveSize))
+}
+
+func TestErrorUnauthorized(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	client := New(server.URL, server.Client())
+	is := is.New(t)
+
+	_, err := client.Daemon.GetBlockCount()
+	is.True(errors.Is(err, ErrUnauthorized))
+
+	err = client.DoOther(context.Background(), "/get_height", nil, nil)
+	is.True(errors.Is(err, ErrUnauthorized))
+	var rpcErr *Error
+	is.True(errors.As(err, &rpcErr))
+	is.Equal(rpcErr.Method, "/get_height")
+	is.Equal(rpcErr.HTTPStatus, http.StatusUnauthorized)
+}
+
+func TestErrorTransport(t *testing.T) {
+	server := setupServer(t, "")
+	server.Close()
+
+	client := New(server.URL, server.Client())
+
+	_, err := client.Daemon.GetBlockCount()
+	is.New(t).True(errors.Is(err, ErrTransport))
+}
+
+func TestErrorBusyStatus(t *testing.T) {
+	server := setupServer(t, `{"height": 0, "status": "BUSY"}`)
+	defer server.Close()
+
+	client := New(server.URL+"/json_rpc", server.Client())
+	is := is.New(t)
+
+	_, err := client.Daemon.GetHeight()
+	is.True(errors.Is(err, ErrDaemonIsBusy))
+	var rpcErr *Error
+	is.True(errors.As(err, &rpcErr))
+	is.Equal(rpcErr.Status, "BUSY")
+}
+
+// setupDigestServer challenges requests without digest credentials and replies with the username sent
+func setupDigestServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		io.ReadAll(req.Body)
+		auth := req.Header.Get("Authorization")
+		if !strings.HasPrefix(auth, "Digest ") {
+			rw.Header().Set("WWW-Authenticate", `Digest qop="auth",algorithm=MD5,realm="monero-rpc",nonce="abc",stale=false`)
+			rw.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		user := auth[strings.Index(auth, `username="`)+len(`username="`):]
+		user = user[:strings.Index(user, `"`)]
+		fmt.Fprintf(rw, `{"id": "0", "jsonrpc": "2.0", "result": {"status": "%s"}}`, user)
+	}))
+	return server
+}
+
+// authUser returns the username seen by the server of endpoint
+func authUser(e *Endpoint) (string, error) {
+	var res struct {
+		Status string `json:"status"`
+	}
+	err := e.Do("get_version", nil, &res)
+	return res.Status, err
+}
+
+func TestSetAuthKeepsTransport(t *testing.T) {
+	server := setupDigestServer(t)
+	defer server.Close()
+
+	transport := &countingTransport{}
+	httpClient := &http.Client{Transport: transport}
+	client := New(server.URL, httpClient).SetAuth("user", "pass")
+	is := is.New(t)
+
+	user, err := authUser(client.DaemonEndpoint())
+	is.NoErr(err)
+	is.Equal(user, "user")
+	is.Equal(transport.calls, 2)
+	is.Equal(httpClient.Transport, transport)
+
+	// replacing the credentials does not stack authentication
+	client.SetAuth("other", "pass")
+	user, err = authUser(client.DaemonEndpoint())
+	is.NoErr(err)
+	is.Equal(user, "other")
+	is.Equal(transport.calls, 4)
+}
+
+func TestSetAuthDefaultClient(t *testing.T) {
+	server := setupDigestServer(t)
+	defer server.Close()
+
+	client := New(server.URL, nil).SetAuth("user", "pass")
+	is := is.New(t)
+
+	is.Equal(http.DefaultClient.Transport, nil)
+	user, err := authUser(client.WalletEndpoint())
+	is.NoErr(err)
+	is.Equal(user, "user")
+
+	_, err = New(server.URL, nil).Daemon.GetBlockCount()
+	is.True(errors.Is(err, ErrUnauthorized))
+}
+
+func TestSetAuthPerEndpoint(t *testing.T) {
+	server := setupDigestServer(t)
+	defer server.Close()
+
+	client := New(server.URL, server.Client()).SetWalletAuth("wallet", "1").SetDaemonAuth("daemon", "2")
+	is := is.New(t)
+
+	is.True(client.WalletEndpoint() != client.DaemonEndpoint())
+	user, err := authUser(client.WalletEndpoint())
+	is.NoErr(err)
+	is.Equal(user, "wallet")
+	user, err = authUser(client.DaemonEndpoint())
+	is.NoErr(err)
+	is.Equal(user, "daemon")
+
+	var res struct {
+		Status string `json:"status"`
+	}
+	is.NoErr(client.Do("get_version", nil, &res))
+	is.Equal(res.Status, "wallet")
+
+	client = NewClient(
+		WithWallet(server.URL, nil),
+		WithDaemon(server.URL, nil),
+		WithWalletAuth("wallet", "1"),
+		WithDaemonAuth("daemon", "2"),
+	)
+	user, err = authUser(client.DaemonEndpoint())
+	is.NoErr(err)
+	is.Equal(user, "daemon")
+}
+
+func TestErrorWithoutResult(t *testing.T) {
+	output := `{
+		"id": "0",
+		"jsonrpc": "2.0",
+		"error": {
+		  "code": -21,
+		  "message": "Cannot create wallet. Already exists."
+		}
+	  }`
+	server := setupServer(t, output)
+	defer server.Close()
+
+	client := New(server.URL, server.Client())
+
+	err := client.Wallet.CreateWallet(&wallet.CreateWalletRequest{Filename: "wallet", Language: "English"})
+	is.New(t).True(errors.Is(err, ErrWalletAlreadyExists))
+}
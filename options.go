@@ -0,0 +1,87 @@
+package monerorpc
+
+import (
+	"net/http"
+
+	"github.com/gabstv/httpdigest"
+)
+
+// Option configures a MoneroRPC created by NewClient
+type Option func(*config)
+
+type config struct {
+	wallet endpointConfig
+	daemon endpointConfig
+	retry  *RetryPolicy
+
+	interceptors []Interceptor
+}
+
+type endpointConfig struct {
+	uri      string
+	client   *http.Client
+	username string
+	password string
+}
+
+// WithWallet sets the monero-wallet-rpc URI and an optional http client used by Wallet calls
+func WithWallet(uri string, httpClient *http.Client) Option {
+	return func(c *config) {
+		c.wallet.uri = uri
+		c.wallet.client = httpClient
+	}
+}
+
+// WithDaemon sets the monerod URI and an optional http client used by Daemon calls
+func WithDaemon(uri string, httpClient *http.Client) Option {
+	return func(c *config) {
+		c.daemon.uri = uri
+		c.daemon.client = httpClient
+	}
+}
+
+// WithWalletAuth sets digest username and password for monero-wallet-rpc
+func WithWalletAuth(username, password string) Option {
+	return func(c *config) {
+		c.wallet.username = username
+		c.wallet.password = password
+	}
+}
+
+// WithDaemonAuth sets digest username and password for monerod
+func WithDaemonAuth(username, password string) Option {
+	return func(c *config) {
+		c.daemon.username = username
+		c.daemon.password = password
+	}
+}
+
+func (e endpointConfig) endpoint() *Endpoint {
+	cli := e.client
+	if cli == nil {
+		cli = &http.Client{}
+	}
+	endpoint := &Endpoint{
+		client: cli,
+		base:   cli,
+		uri:    e.uri,
+	}
+	endpoint.setAuth(e.username, e.password)
+	return endpoint
+}
+
+// withDigestAuth returns a copy of client whose transport resolves digest authentication
+// before handing requests to the original transport
+func withDigestAuth(client *http.Client, username, password string) *http.Client {
+	transport := client.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	cli := *client
+	cli.Transport = &httpdigest.Transport{
+		Username:  username,
+		Password:  password,
+		Transport: transport,
+	}
+	return &cli
+}
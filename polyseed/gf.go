@@ -0,0 +1,40 @@
+package polyseed
+
+// Polyseed's checksum is a polynomial code over GF(2048), using the reduction polynomial x^11 + x^2 + 1.
+// Every word is a coefficient, the first word is chosen so that the polynomial evaluates to zero at x = 2.
+
+const (
+	gfBits = 11
+	gfSize = 1 << gfBits
+	gfPoly = 0x805
+)
+
+type poly [NumWords]uint16
+
+// mul2 multiplies x by 2 in GF(2048)
+func mul2(x uint16) uint16 {
+	if x&(gfSize>>1) != 0 {
+		return (x << 1) ^ gfPoly
+	}
+	return x << 1
+}
+
+// eval evaluates the polynomial at x = 2 using Horner's method
+func (p *poly) eval() uint16 {
+	result := p[NumWords-1]
+	for i := NumWords - 2; i >= 0; i-- {
+		result = mul2(result) ^ p[i]
+	}
+	return result
+}
+
+// encode sets the check digit
+func (p *poly) encode() {
+	p[0] = 0
+	p[0] = p.eval()
+}
+
+// check reports whether the check digit is valid
+func (p *poly) check() bool {
+	return p.eval() == 0
+}
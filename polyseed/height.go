@@ -0,0 +1,36 @@
+package polyseed
+
+import (
+	"time"
+
+	"github.com/MarinX/monerorpc/address"
+)
+
+// secondsPerBlock is the block time since the v2 fork
+const secondsPerBlock = 120
+
+// blocksPerMonth is the safety margin subtracted from the estimate, as wallet2 does:
+// blocks are often slower than the target time, so the plain estimate runs ahead of the chain
+const blocksPerMonth = 60 * 60 * 24 * 30 / secondsPerBlock
+
+// ApproximateHeight estimates the height of network at t from the time of the v2 fork and the block time,
+// like monero's wallet does when it restores from a date. A month of blocks is subtracted so the result
+// stays at or below the real height. Testnet and stagenet had large rollbacks, so the estimate is lowered
+// for them. The result is 0 before the v2 fork.
+func ApproximateHeight(network address.Network, t time.Time) uint64 {
+	forkTime, forkHeight, rolledBack := int64(1458748658), uint64(1009827), uint64(0)
+	switch network {
+	case address.Testnet:
+		forkTime, forkHeight, rolledBack = 1448285909, 624634, 342100
+	case address.Stagenet:
+		forkTime, forkHeight, rolledBack = 1520937818, 32000, 30000
+	}
+	if t.Unix() < forkTime {
+		return 0
+	}
+	height := forkHeight + uint64(t.Unix()-forkTime)/secondsPerBlock
+	if height <= rolledBack+blocksPerMonth {
+		return 0
+	}
+	return height - rolledBack - blocksPerMonth
+}
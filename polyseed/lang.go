@@ -0,0 +1,165 @@
+package polyseed
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/tyler-smith/go-bip39/wordlists"
+	"golang.org/x/text/unicode/norm"
+)
+
+// prefixLength is the number of characters identifying a word in languages with prefixes
+const prefixLength = 4
+
+// ErrInvalidWordList is returned by NewLanguage for malformed wordlists
+var ErrInvalidWordList = errors.New("polyseed: invalid wordlist")
+
+// Language is a polyseed wordlist. Polyseed uses the BIP-39 wordlists.
+type Language struct {
+	// Name is the native name, e.g. "Español"
+	Name string
+	// EnglishName is the name in English, e.g. "Spanish"
+	EnglishName string
+	// Separator joins the words of a phrase
+	Separator string
+
+	prefix  bool
+	accents bool
+	compose bool
+	words   []string
+	index   map[string]int
+}
+
+// LanguageOptions are the matching rules of a wordlist
+type LanguageOptions struct {
+	// Separator joins the words of a phrase, a space if empty
+	Separator string
+	// Prefix matches words by their first 4 characters
+	Prefix bool
+	// Accents makes matching ignore accents
+	Accents bool
+	// Compose writes phrases in NFC instead of NFKD
+	Compose bool
+}
+
+// NewLanguage creates a language from a wordlist of 2048 words
+func NewLanguage(name, englishName string, opts LanguageOptions, words []string) (*Language, error) {
+	if len(words) != gfSize {
+		return nil, fmt.Errorf("%w: %s has %d words, want %d", ErrInvalidWordList, name, len(words), gfSize)
+	}
+	if opts.Separator == "" {
+		opts.Separator = " "
+	}
+	l := &Language{
+		Name:        name,
+		EnglishName: englishName,
+		Separator:   opts.Separator,
+		prefix:      opts.Prefix,
+		accents:     opts.Accents,
+		compose:     opts.Compose,
+		words:       append([]string(nil), words...),
+		index:       make(map[string]int, len(words)),
+	}
+	for i, w := range words {
+		key := l.key(w)
+		if _, ok := l.index[key]; ok {
+			return nil, fmt.Errorf("%w: %s has duplicate word %q", ErrInvalidWordList, name, w)
+		}
+		l.index[key] = i
+	}
+	return l, nil
+}
+
+func mustLanguage(name, englishName string, opts LanguageOptions, words []string) *Language {
+	l, err := NewLanguage(name, englishName, opts, words)
+	if err != nil {
+		panic(err)
+	}
+	return l
+}
+
+// key normalizes a word for lookups: NFKD in lower case, without accents if the language ignores them,
+// cut to its prefix if the language has prefixes
+func (l *Language) key(word string) string {
+	word = strings.ToLower(norm.NFKD.String(word))
+	if l.accents {
+		word = strings.Map(func(r rune) rune {
+			if unicode.Is(unicode.Mn, r) {
+				return -1
+			}
+			return r
+		}, word)
+	}
+	if l.prefix {
+		n := 0
+		for i := range word {
+			if n == prefixLength {
+				return word[:i]
+			}
+			n++
+		}
+	}
+	return word
+}
+
+// Lookup returns the index of word, case is ignored. In languages with prefixes, a word matches when it
+// is a full word of the list or starts with the first 4 characters of one.
+func (l *Language) Lookup(word string) (int, bool) {
+	i, ok := l.index[l.key(word)]
+	return i, ok
+}
+
+// Word returns the word at index i
+func (l *Language) Word(i int) string {
+	return l.words[i]
+}
+
+// phrase joins words with the separator, normalized like polyseed does
+func (l *Language) phrase(words []string) string {
+	s := strings.Join(words, l.Separator)
+	if l.compose {
+		return norm.NFC.String(s)
+	}
+	return norm.NFKD.String(s)
+}
+
+// Built in languages. Polyseed also defines Portuguese, which can be added with Register.
+var (
+	Czech              = mustLanguage("čeština", "Czech", LanguageOptions{Prefix: true}, wordlists.Czech)
+	English            = mustLanguage("English", "English", LanguageOptions{Prefix: true}, wordlists.English)
+	Spanish            = mustLanguage("Español", "Spanish", LanguageOptions{Prefix: true, Accents: true, Compose: true}, wordlists.Spanish)
+	French             = mustLanguage("Français", "French", LanguageOptions{Prefix: true, Accents: true, Compose: true}, wordlists.French)
+	Italian            = mustLanguage("Italiano", "Italian", LanguageOptions{Prefix: true}, wordlists.Italian)
+	Japanese           = mustLanguage("日本語", "Japanese", LanguageOptions{Separator: "　", Compose: true}, wordlists.Japanese)
+	Korean             = mustLanguage("한국어", "Korean", LanguageOptions{Compose: true}, wordlists.Korean)
+	ChineseSimplified  = mustLanguage("中文(简体)", "Chinese (simplified)", LanguageOptions{}, wordlists.ChineseSimplified)
+	ChineseTraditional = mustLanguage("中文(繁體)", "Chinese (traditional)", LanguageOptions{}, wordlists.ChineseTraditional)
+)
+
+var (
+	mu        sync.RWMutex
+	languages = []*Language{English, Japanese, Korean, Spanish, French, Italian, Czech, ChineseSimplified, ChineseTraditional}
+)
+
+// Register adds a language to the ones used by Decode, replacing a language with the same name
+func Register(l *Language) {
+	mu.Lock()
+	defer mu.Unlock()
+	for i, existing := range languages {
+		if existing.Name == l.Name {
+			languages[i] = l
+			return
+		}
+	}
+	languages = append(languages, l)
+}
+
+// Languages returns the registered languages
+func Languages() []*Language {
+	mu.RLock()
+	defer mu.RUnlock()
+	return append([]*Language(nil), languages...)
+}
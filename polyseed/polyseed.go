@@ -0,0 +1,345 @@
+// Package polyseed encodes and decodes Polyseed mnemonic seeds, the 16 word seeds of newer Monero wallets.
+//
+// A polyseed holds a 150 bit secret, the wallet birthday with a resolution of about one month and 5 feature
+// bits, protected by a checksum word. The birthday gives the height a wallet can be restored from, see
+// Seed.RestoreHeight and Seed.RestoreRequest.
+package polyseed
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/MarinX/monerorpc/address"
+	"github.com/MarinX/monerorpc/crypto"
+	"github.com/MarinX/monerorpc/mnemonic"
+	"github.com/MarinX/monerorpc/wallet"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/text/unicode/norm"
+)
+
+// NumWords is the number of words of a polyseed phrase
+const NumWords = 16
+
+const (
+	secretBits = 150
+	// SecretSize is the number of bytes holding the secret, the 2 top bits of the last byte are zero
+	SecretSize = (secretBits + 7) / 8
+	clearMask  = 0xff >> (SecretSize*8 - secretBits)
+
+	dateBits    = 10
+	dateMask    = 1<<dateBits - 1
+	featureBits = 5
+	featureMask = 1<<featureBits - 1
+	// shareBits is the number of secret bits stored in every data word
+	shareBits = gfBits - 1
+	dataWords = NumWords - 1
+
+	// epoch is the birthday 0, 1st November 2021 12:00 UTC
+	epoch = 1635768000
+	// timeStep is the birthday resolution, 1/12 of the Gregorian year
+	timeStep = 2629746
+
+	kdfIterations = 10000
+	// coinMonero is the coin a seed is used for, it is mixed into the first data word and the key
+	coinMonero = 0
+)
+
+// Feature bits
+const (
+	// UserFeatures are the feature bits free for wallets to use
+	UserFeatures = 0x07
+	// FeatureEncrypted is set when the secret is encrypted with a password
+	FeatureEncrypted = 0x10
+
+	reservedFeatures = featureMask &^ UserFeatures &^ FeatureEncrypted
+)
+
+var (
+	// ErrNumWords is returned for phrases without 16 words
+	ErrNumWords = errors.New("polyseed: phrase must have 16 words")
+	// ErrUnknownLanguage is returned when no registered language contains all the words of a phrase
+	ErrUnknownLanguage = errors.New("polyseed: unknown language")
+	// ErrMultipleLanguages is returned when a phrase is valid in several languages, use DecodeLanguage
+	ErrMultipleLanguages = errors.New("polyseed: phrase is valid in several languages")
+	// ErrChecksum is returned when the checksum word does not match
+	ErrChecksum = errors.New("polyseed: invalid checksum")
+	// ErrUnsupported is returned for seeds using reserved feature bits
+	ErrUnsupported = errors.New("polyseed: unsupported features")
+	// ErrEncrypted is returned when deriving keys from an encrypted seed, decrypt it with Crypt first
+	ErrEncrypted = errors.New("polyseed: seed is encrypted")
+)
+
+// Seed is a decoded polyseed
+type Seed struct {
+	secret   [SecretSize]byte
+	birthday uint16
+	features uint8
+	checksum uint16
+}
+
+// New creates a seed from a random secret, born now
+func New() (*Seed, error) {
+	var secret [SecretSize]byte
+	if _, err := rand.Read(secret[:]); err != nil {
+		return nil, err
+	}
+	return FromSecret(secret, time.Now(), 0)
+}
+
+// FromSecret creates a seed from secret, whose 2 top bits of the last byte are ignored, born at birthday
+// and with user features
+func FromSecret(secret [SecretSize]byte, birthday time.Time, features uint8) (*Seed, error) {
+	if features&^UserFeatures != 0 {
+		return nil, fmt.Errorf("%w %#x", ErrUnsupported, features)
+	}
+	s := &Seed{
+		secret:   secret,
+		birthday: encodeBirthday(birthday),
+		features: features,
+	}
+	s.secret[SecretSize-1] &= clearMask
+	s.seal()
+	return s, nil
+}
+
+func encodeBirthday(t time.Time) uint16 {
+	if t.Unix() < epoch {
+		return 0
+	}
+	return uint16((uint64(t.Unix()) - epoch) / timeStep & dateMask)
+}
+
+// seal computes the checksum
+func (s *Seed) seal() {
+	p := s.poly()
+	p.encode()
+	s.checksum = p[0]
+}
+
+// poly stores the seed data into the polynomial coefficients. Every data word holds 10 bits
+// of the secret, most significant first, and one bit of the features and birthday.
+func (s *Seed) poly() *poly {
+	p := &poly{s.checksum}
+	extra := uint(s.features)<<dateBits | uint(s.birthday)
+	extraBits := featureBits + dateBits
+
+	idx, bits, remaining := 0, 8, secretBits-8
+	for i := 0; i < dataWords; i++ {
+		var word uint
+		for wordBits := 0; wordBits < shareBits; {
+			if bits == 0 {
+				idx++
+				bits = min(remaining, 8)
+				remaining -= bits
+			}
+			chunk := min(bits, shareBits-wordBits)
+			bits -= chunk
+			wordBits += chunk
+			word = word<<chunk | uint(s.secret[idx])>>bits&(1<<chunk-1)
+		}
+		extraBits--
+		p[1+i] = uint16(word<<1 | extra>>extraBits&1)
+	}
+	return p
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// fromPoly reads the seed data from the polynomial coefficients
+func fromPoly(p *poly) *Seed {
+	s := &Seed{checksum: p[0]}
+	var extra uint
+	idx, bits := 0, 0
+	for i := 1; i < NumWords; i++ {
+		word := uint(p[i])
+		extra = extra<<1 | word&1
+		word >>= 1
+		for wordBits := shareBits; wordBits > 0; {
+			if bits == 8 {
+				idx++
+				bits = 0
+			}
+			chunk := min(wordBits, 8-bits)
+			wordBits -= chunk
+			s.secret[idx] = s.secret[idx]<<chunk | byte(word>>wordBits&(1<<chunk-1))
+			bits += chunk
+		}
+	}
+	s.birthday = uint16(extra & dateMask)
+	s.features = uint8(extra >> dateBits)
+	return s
+}
+
+// Encode returns the phrase of the seed in lang
+func (s *Seed) Encode(lang *Language) string {
+	p := s.poly()
+	p[1] ^= coinMonero
+	words := make([]string, NumWords)
+	for i, c := range p {
+		words[i] = lang.words[c]
+	}
+	return lang.phrase(words)
+}
+
+// String returns the English phrase of the seed
+func (s *Seed) String() string {
+	return s.Encode(English)
+}
+
+// Decode detects the language of phrase and decodes the seed.
+// Words may be given by their first 4 characters in languages with prefixes.
+func Decode(phrase string) (*Seed, *Language, error) {
+	words, err := split(phrase)
+	if err != nil {
+		return nil, nil, err
+	}
+	var (
+		seed  *Seed
+		found *Language
+	)
+	err = ErrUnknownLanguage
+	for _, lang := range Languages() {
+		s, langErr := decode(words, lang)
+		if langErr == nil {
+			if found != nil {
+				return nil, nil, ErrMultipleLanguages
+			}
+			seed, found = s, lang
+			err = nil
+		} else if !errors.Is(langErr, ErrUnknownLanguage) && found == nil {
+			// the words are from this language, report why the phrase is invalid
+			err = langErr
+		}
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return seed, found, nil
+}
+
+// DecodeLanguage decodes a phrase in lang
+func DecodeLanguage(phrase string, lang *Language) (*Seed, error) {
+	words, err := split(phrase)
+	if err != nil {
+		return nil, err
+	}
+	return decode(words, lang)
+}
+
+func split(phrase string) ([]string, error) {
+	words := strings.Fields(norm.NFKD.String(phrase))
+	if len(words) != NumWords {
+		return nil, fmt.Errorf("%w, got %d", ErrNumWords, len(words))
+	}
+	return words, nil
+}
+
+func decode(words []string, lang *Language) (*Seed, error) {
+	p := &poly{}
+	for i, w := range words {
+		index, ok := lang.Lookup(w)
+		if !ok {
+			return nil, fmt.Errorf("%w: %q is not a %s word", ErrUnknownLanguage, w, lang.EnglishName)
+		}
+		p[i] = uint16(index)
+	}
+	p[1] ^= coinMonero
+	if !p.check() {
+		return nil, ErrChecksum
+	}
+	s := fromPoly(p)
+	if s.features&reservedFeatures != 0 {
+		return nil, fmt.Errorf("%w %#x", ErrUnsupported, s.features)
+	}
+	return s, nil
+}
+
+// Birthday returns the time the seed was created, rounded down to about a month
+func (s *Seed) Birthday() time.Time {
+	return time.Unix(epoch+int64(s.birthday)*timeStep, 0).UTC()
+}
+
+// Features returns the feature bits, including FeatureEncrypted
+func (s *Seed) Features() uint8 {
+	return s.features
+}
+
+// Encrypted reports whether the secret is encrypted with a password
+func (s *Seed) Encrypted() bool {
+	return s.features&FeatureEncrypted != 0
+}
+
+// Crypt encrypts the seed with password, or decrypts it if it is encrypted.
+// A wrong password decrypts to a different, valid seed.
+func (s *Seed) Crypt(password string) {
+	salt := make([]byte, 16)
+	copy(salt, "POLYSEED mask")
+	salt[14], salt[15] = 0xff, 0xff
+	mask := pbkdf2.Key([]byte(norm.NFKD.String(password)), salt, kdfIterations, 32, sha256.New)
+	for i := range s.secret {
+		s.secret[i] ^= mask[i]
+	}
+	s.secret[SecretSize-1] &= clearMask
+	s.features ^= FeatureEncrypted
+	s.seal()
+}
+
+// Key derives the 32 byte master key of the seed for Monero
+func (s *Seed) Key() ([32]byte, error) {
+	var key [32]byte
+	if s.Encrypted() {
+		return key, ErrEncrypted
+	}
+	salt := make([]byte, 32)
+	copy(salt, "POLYSEED key")
+	salt[13], salt[14], salt[15] = 0xff, 0xff, 0xff
+	binary.LittleEndian.PutUint32(salt[16:], coinMonero)
+	binary.LittleEndian.PutUint32(salt[20:], uint32(s.birthday))
+	binary.LittleEndian.PutUint32(salt[24:], uint32(s.features))
+	password := make([]byte, 32)
+	copy(password, s.secret[:])
+	copy(key[:], pbkdf2.Key(password, salt, kdfIterations, 32, sha256.New))
+	return key, nil
+}
+
+// SpendKey returns the private spend key of the wallet, the master key reduced modulo the group order
+func (s *Seed) SpendKey() ([32]byte, error) {
+	key, err := s.Key()
+	if err != nil {
+		return key, err
+	}
+	return crypto.Bytes(crypto.Reduce(key)), nil
+}
+
+// RestoreHeight returns the approximate height of network at the birthday of the seed,
+// the height a wallet can safely be restored from
+func (s *Seed) RestoreHeight(network address.Network) uint64 {
+	return ApproximateHeight(network, s.Birthday())
+}
+
+// RestoreRequest builds a request restoring the wallet of the seed with wallet-rpc's restore_deterministic_wallet,
+// which only accepts 25 word seeds: the seed is converted to the English 25 word seed of the unreduced key,
+// like the wallets using polyseed restore it, and the restore height is set from the birthday
+func (s *Seed) RestoreRequest(filename, password string, network address.Network) (*wallet.RestoreDeterministicWalletRequest, error) {
+	key, err := s.Key()
+	if err != nil {
+		return nil, err
+	}
+	return &wallet.RestoreDeterministicWalletRequest{
+		Name:          filename,
+		Password:      password,
+		Seed:          mnemonic.EncodeString(key, mnemonic.English),
+		RestoreHeight: s.RestoreHeight(network),
+		Language:      mnemonic.English.Name,
+	}, nil
+}
@@ -0,0 +1,150 @@
+package polyseed
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MarinX/monerorpc/address"
+	"github.com/MarinX/monerorpc/crypto"
+	"github.com/MarinX/monerorpc/mnemonic"
+	"github.com/matryer/is"
+)
+
+// phrase is a test vector of the polyseed reference implementation, created in December 2021
+const phrase = "raven tail swear infant grief assist regular lamp duck valid someone little harsh puppy airport language"
+
+func TestDecode(t *testing.T) {
+	is := is.New(t)
+	s, lang, err := Decode(phrase)
+	is.NoErr(err)
+	is.Equal(lang, English)
+	is.Equal(s.Birthday(), time.Date(2021, 12, 1, 22, 29, 6, 0, time.UTC))
+	is.Equal(s.Features(), uint8(0))
+	is.True(!s.Encrypted())
+	is.Equal(s.String(), phrase)
+
+	// words by prefix, any case and spacing
+	var prefixes []string
+	for _, w := range strings.Fields(phrase) {
+		if len(w) > 4 {
+			w = strings.ToUpper(w[:4]) + "x"
+		}
+		prefixes = append(prefixes, w)
+	}
+	prefixed, err := DecodeLanguage(" "+strings.Join(prefixes, "\t ")+"\n", English)
+	is.NoErr(err)
+	is.Equal(prefixed, s)
+}
+
+func TestDecodeErrors(t *testing.T) {
+	is := is.New(t)
+	words := strings.Fields(phrase)
+
+	_, _, err := Decode(strings.Join(words[:15], " "))
+	is.True(errors.Is(err, ErrNumWords))
+
+	bad := append([]string(nil), words...)
+	bad[11] = "lamp"
+	_, _, err = Decode(strings.Join(bad, " "))
+	is.True(errors.Is(err, ErrChecksum))
+
+	bad[11] = "xylophone"
+	_, _, err = Decode(strings.Join(bad, " "))
+	is.True(errors.Is(err, ErrUnknownLanguage))
+}
+
+func TestRoundTrip(t *testing.T) {
+	is := is.New(t)
+	birthday := time.Date(2024, 5, 20, 0, 0, 0, 0, time.UTC)
+	for _, lang := range Languages() {
+		var secret [SecretSize]byte
+		for i := range secret {
+			secret[i] = byte(i*37 + len(lang.Name))
+		}
+		s, err := FromSecret(secret, birthday, 5)
+		is.NoErr(err)
+
+		encoded := s.Encode(lang)
+		decoded, found, err := Decode(encoded)
+		is.NoErr(err) // lang.EnglishName
+		is.Equal(found, lang)
+		is.Equal(decoded, s)
+		is.Equal(decoded.Features(), uint8(5))
+		is.True(!decoded.Birthday().After(birthday))
+		is.True(birthday.Sub(decoded.Birthday()) < timeStep*time.Second)
+	}
+}
+
+func TestNew(t *testing.T) {
+	is := is.New(t)
+	s, err := New()
+	is.NoErr(err)
+	is.True(time.Since(s.Birthday()) < timeStep*time.Second)
+	decoded, err := DecodeLanguage(s.String(), English)
+	is.NoErr(err)
+	is.Equal(decoded, s)
+
+	_, err = FromSecret([SecretSize]byte{}, time.Now(), FeatureEncrypted)
+	is.True(errors.Is(err, ErrUnsupported))
+}
+
+func TestCrypt(t *testing.T) {
+	is := is.New(t)
+	s, _, err := Decode(phrase)
+	is.NoErr(err)
+	key, err := s.Key()
+	is.NoErr(err)
+
+	s.Crypt("password")
+	is.True(s.Encrypted())
+	_, err = s.Key()
+	is.Equal(err, ErrEncrypted)
+
+	encrypted, _, err := Decode(s.String())
+	is.NoErr(err)
+	is.True(encrypted.Encrypted())
+	is.True(encrypted.String() != phrase)
+
+	encrypted.Crypt("password")
+	is.Equal(encrypted.String(), phrase)
+	decryptedKey, err := encrypted.Key()
+	is.NoErr(err)
+	is.Equal(decryptedKey, key)
+}
+
+func TestRestoreRequest(t *testing.T) {
+	is := is.New(t)
+	s, _, err := Decode(phrase)
+	is.NoErr(err)
+
+	req, err := s.RestoreRequest("restored", "secret", address.Mainnet)
+	is.NoErr(err)
+	is.Equal(req.Name, "restored")
+	is.Equal(req.RestoreHeight, ApproximateHeight(address.Mainnet, s.Birthday()))
+
+	key, lang, err := mnemonic.Decode(req.Seed)
+	is.NoErr(err)
+	is.Equal(lang, mnemonic.English)
+
+	// wallet-rpc restores the wallet of the polyseed: the view key is derived from the unreduced key
+	spendPublic, err := crypto.PublicKey(crypto.Bytes(crypto.Reduce(key)))
+	is.NoErr(err)
+	viewPublic, err := crypto.PublicKey(crypto.Bytes(crypto.HashToScalar(key[:])))
+	is.NoErr(err)
+	addr := &address.Address{Network: address.Mainnet, Type: address.Standard, SpendKey: spendPublic, ViewKey: viewPublic}
+	is.Equal(addr.String(), "47AjPj7DVPQVGGXJXbbTMZWcKQDejGHYZChVkeujy8qPLgZrXzU7nfujSPdwiuHQxdg2XpMmH9GTzijZT5TmupPx6EBGvtr")
+}
+
+func TestApproximateHeight(t *testing.T) {
+	is := is.New(t)
+	is.Equal(ApproximateHeight(address.Mainnet, time.Unix(1458748658+120*1000, 0)), uint64(1010827-blocksPerMonth))
+	is.Equal(ApproximateHeight(address.Mainnet, time.Unix(1458748658, 0)), uint64(1009827-blocksPerMonth))
+	is.Equal(ApproximateHeight(address.Stagenet, time.Unix(1520937818, 0)), uint64(0))
+	is.Equal(ApproximateHeight(address.Testnet, time.Unix(0, 0)), uint64(0))
+
+	// block 2688888, the v15 fork, was mined on 2022-08-13: the plain 120s estimate is ahead of it
+	// a day later, the margin keeps the restore height below it
+	is.True(ApproximateHeight(address.Mainnet, time.Date(2022, 8, 15, 0, 0, 0, 0, time.UTC)) <= 2688888)
+}
@@ -0,0 +1,270 @@
+package monerorpc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/MarinX/monerorpc/daemon"
+)
+
+// ErrNoNodes is returned by a Pool without nodes
+var ErrNoNodes = errors.New("no nodes in pool")
+
+// Pool is a set of monerod endpoints implementing daemon.MoneroRPC.
+// Calls are routed to the best healthy node and fail over to the next one when a node
+// is unreachable, answers with a 5xx http status or reports that it is busy.
+// Use it with daemon.New:
+//
+//	pool := monerorpc.NewPool([]*monerorpc.Endpoint{
+//		monerorpc.NewEndpoint("http://node1:18081/json_rpc", nil),
+//		monerorpc.NewEndpoint("http://node2:18081/json_rpc", nil),
+//	})
+//	go pool.Run(ctx, time.Minute)
+//	d := daemon.New(pool)
+type Pool struct {
+	mu    sync.RWMutex
+	nodes []*poolNode
+
+	maxHeightLag uint64
+	checkTimeout time.Duration
+}
+
+type poolNode struct {
+	endpoint *Endpoint
+	status   NodeStatus
+}
+
+// NodeStatus is the health of a node as seen by the last check or call
+type NodeStatus struct {
+	URI          string
+	Healthy      bool
+	Height       uint64
+	Synchronized bool
+	Untrusted    bool
+	BusySyncing  bool
+	// Err is the error of the last check or of the last failed call
+	Err error
+	// CheckedAt is the time of the last check, zero if the node was never checked
+	CheckedAt time.Time
+}
+
+// PoolOption configures a Pool created by NewPool
+type PoolOption func(*Pool)
+
+// WithMaxHeightLag sets how many blocks a node may be behind the highest node and still be healthy, default 2
+func WithMaxHeightLag(blocks uint64) PoolOption {
+	return func(p *Pool) {
+		p.maxHeightLag = blocks
+	}
+}
+
+// WithCheckTimeout bounds the health check of a single node, default 5 seconds
+func WithCheckTimeout(timeout time.Duration) PoolOption {
+	return func(p *Pool) {
+		p.checkTimeout = timeout
+	}
+}
+
+// NewEndpoint creates a json rpc endpoint, e.g. to add it to a Pool.
+// If httpClient is nil a new http.Client is used.
+func NewEndpoint(uri string, httpClient *http.Client) *Endpoint {
+	return endpointConfig{uri: uri, client: httpClient}.endpoint()
+}
+
+// NewPool creates a pool of monerod endpoints. Nodes are preferred in the given order
+// until the first health check ranks them.
+func NewPool(endpoints []*Endpoint, opts ...PoolOption) *Pool {
+	p := &Pool{
+		maxHeightLag: 2,
+		checkTimeout: 5 * time.Second,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	for _, e := range endpoints {
+		e.kind = kindDaemon
+		p.nodes = append(p.nodes, &poolNode{
+			endpoint: e,
+			status:   NodeStatus{URI: e.uri, Healthy: true},
+		})
+	}
+	return p
+}
+
+// Status returns the health of every node, in the order they were added
+func (p *Pool) Status() []NodeStatus {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	status := make([]NodeStatus, len(p.nodes))
+	for i, n := range p.nodes {
+		status[i] = n.status
+	}
+	return status
+}
+
+// Check runs GetInfo on every node concurrently and updates their health.
+// A node is healthy when it answers, is synchronized, is not busy syncing and is at most
+// the max height lag behind the highest synchronized node.
+func (p *Pool) Check(ctx context.Context) {
+	p.mu.RLock()
+	nodes := append([]*poolNode(nil), p.nodes...)
+	p.mu.RUnlock()
+
+	status := make([]NodeStatus, len(nodes))
+	var wg sync.WaitGroup
+	for i, n := range nodes {
+		wg.Add(1)
+		go func(i int, n *poolNode) {
+			defer wg.Done()
+			status[i] = p.check(ctx, n.endpoint)
+		}(i, n)
+	}
+	wg.Wait()
+
+	var top uint64
+	for _, s := range status {
+		if s.Err == nil && s.Synchronized && s.Height > top {
+			top = s.Height
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for i, n := range nodes {
+		s := status[i]
+		// keep the last known health of nodes whose check was cut short by the caller
+		if s.Err != nil && ctx.Err() != nil && canceled(s.Err) {
+			continue
+		}
+		s.Healthy = s.Err == nil && s.Synchronized && !s.BusySyncing && s.Height+p.maxHeightLag >= top
+		n.status = s
+	}
+}
+
+func (p *Pool) check(ctx context.Context, e *Endpoint) NodeStatus {
+	ctx, cancel := context.WithTimeout(ctx, p.checkTimeout)
+	defer cancel()
+	status := NodeStatus{URI: e.uri, CheckedAt: time.Now()}
+	info, err := daemon.New(e).GetInfoContext(ctx)
+	if err != nil {
+		status.Err = err
+		return status
+	}
+	status.Height = info.Height
+	status.Synchronized = info.Synchronized
+	status.Untrusted = info.Untrusted
+	status.BusySyncing = info.BusySyncing
+	return status
+}
+
+// Run checks the nodes every interval until ctx is done
+func (p *Pool) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		p.Check(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// candidates returns the nodes in the order they should be tried: healthy first,
+// then trusted, then by height. Nodes with the same rank keep the order they were added.
+func (p *Pool) candidates() []*poolNode {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	nodes := append([]*poolNode(nil), p.nodes...)
+	sort.SliceStable(nodes, func(i, j int) bool {
+		a, b := nodes[i].status, nodes[j].status
+		if a.Healthy != b.Healthy {
+			return a.Healthy
+		}
+		if a.Untrusted != b.Untrusted {
+			return !a.Untrusted
+		}
+		return a.Height > b.Height
+	})
+	return nodes
+}
+
+// markDown flags a node as unhealthy until the next check
+func (p *Pool) markDown(n *poolNode, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	n.status.Healthy = false
+	n.status.Err = err
+}
+
+// failover reports whether err means the call should be tried on another node
+func failover(err error) bool {
+	if errors.Is(err, ErrTransport) || errors.Is(err, ErrUnauthorized) {
+		return true
+	}
+	var rpcErr *Error
+	if !errors.As(err, &rpcErr) {
+		return false
+	}
+	return rpcErr.Status == "BUSY" || (rpcErr.Code == 0 && rpcErr.HTTPStatus >= http.StatusInternalServerError)
+}
+
+// canceled reports whether err was caused by a canceled context or an expired deadline
+func canceled(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// call runs fn on the candidates until one does not need a failover
+func (p *Pool) call(ctx context.Context, fn func(e *Endpoint) error) error {
+	err := ErrNoNodes
+	for _, n := range p.candidates() {
+		err = fn(n.endpoint)
+		if err == nil || !failover(err) {
+			return err
+		}
+		// the caller gave up, the node is not to blame
+		if ctx.Err() != nil || canceled(err) {
+			return err
+		}
+		p.markDown(n, err)
+	}
+	return err
+}
+
+var (
+	_ daemon.MoneroRPC        = (*Pool)(nil)
+	_ daemon.MoneroRPCContext = (*Pool)(nil)
+	_ daemon.MoneroOtherRPC   = (*Pool)(nil)
+	_ daemon.MoneroBinaryRPC  = (*Pool)(nil)
+)
+
+// Do calls the json rpc method on the best node
+func (p *Pool) Do(method string, req interface{}, res interface{}) error {
+	return p.DoContext(context.Background(), method, req, res)
+}
+
+// DoContext is like Do but the request is bound to ctx
+func (p *Pool) DoContext(ctx context.Context, method string, req interface{}, res interface{}) error {
+	return p.call(ctx, func(e *Endpoint) error {
+		return e.DoContext(ctx, method, req, res)
+	})
+}
+
+// DoOther calls one of monerod's path based endpoints on the best node
+func (p *Pool) DoOther(ctx context.Context, path string, req interface{}, res interface{}) error {
+	return p.call(ctx, func(e *Endpoint) error {
+		return e.DoOther(ctx, path, req, res)
+	})
+}
+
+// DoBinary calls one of monerod's .bin endpoints on the best node
+func (p *Pool) DoBinary(ctx context.Context, path string, req interface{}, res interface{}) error {
+	return p.call(ctx, func(e *Endpoint) error {
+		return e.DoBinary(ctx, path, req, res)
+	})
+}
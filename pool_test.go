@@ -0,0 +1,153 @@
+package monerorpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/MarinX/monerorpc/daemon"
+	"github.com/matryer/is"
+)
+
+// setupNode serves get_info with the given height and answers get_block_count with its name
+func setupNode(t *testing.T, name string, height uint64, synchronized bool, calls *int32) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		buff, _ := io.ReadAll(req.Body)
+		if strings.Contains(string(buff), "get_info") {
+			fmt.Fprintf(rw, `{"id": "0", "jsonrpc": "2.0", "result": {"height": %d, "synchronized": %t, "status": "OK"}}`, height, synchronized)
+			return
+		}
+		if calls != nil {
+			atomic.AddInt32(calls, 1)
+		}
+		fmt.Fprintf(rw, `{"id": "0", "jsonrpc": "2.0", "result": {"status": "%s"}}`, name)
+	}))
+	return server
+}
+
+// nodeName returns the name of the node answering get_block_count
+func nodeName(d daemon.MoneroRPC) (string, error) {
+	var res struct {
+		Status string `json:"status"`
+	}
+	err := d.Do("get_block_count", nil, &res)
+	return res.Status, err
+}
+
+func TestPoolRouting(t *testing.T) {
+	down := setupNode(t, "down", 0, true, nil)
+	down.Close()
+	lagging := setupNode(t, "lagging", 90, true, nil)
+	defer lagging.Close()
+	syncing := setupNode(t, "syncing", 120, false, nil)
+	defer syncing.Close()
+	best := setupNode(t, "best", 100, true, nil)
+	defer best.Close()
+
+	pool := NewPool([]*Endpoint{
+		NewEndpoint(down.URL, nil),
+		NewEndpoint(lagging.URL, nil),
+		NewEndpoint(syncing.URL, nil),
+		NewEndpoint(best.URL, nil),
+	}, WithMaxHeightLag(5))
+	is := is.New(t)
+
+	pool.Check(context.Background())
+	status := pool.Status()
+	is.Equal(len(status), 4)
+	is.True(!status[0].Healthy)
+	is.True(errors.Is(status[0].Err, ErrTransport))
+	is.True(!status[1].Healthy)
+	is.True(!status[2].Healthy)
+	is.True(status[3].Healthy)
+	is.Equal(status[3].Height, uint64(100))
+	is.True(!status[3].CheckedAt.IsZero())
+
+	name, err := nodeName(pool)
+	is.NoErr(err)
+	is.Equal(name, "best")
+}
+
+func TestPoolFailover(t *testing.T) {
+	var firstCalls, secondCalls int32
+	first := setupNode(t, "first", 100, true, &firstCalls)
+	second := setupNode(t, "second", 100, true, &secondCalls)
+	defer second.Close()
+
+	pool := NewPool([]*Endpoint{NewEndpoint(first.URL, nil), NewEndpoint(second.URL, nil)})
+	is := is.New(t)
+
+	name, err := nodeName(pool)
+	is.NoErr(err)
+	is.Equal(name, "first")
+
+	first.Close()
+	name, err = nodeName(pool)
+	is.NoErr(err)
+	is.Equal(name, "second")
+	is.True(!pool.Status()[0].Healthy)
+
+	// the failed node is skipped until the next check
+	name, err = nodeName(pool)
+	is.NoErr(err)
+	is.Equal(name, "second")
+	is.Equal(atomic.LoadInt32(&firstCalls), int32(1))
+	is.Equal(atomic.LoadInt32(&secondCalls), int32(2))
+}
+
+func TestPoolNoFailoverOnRPCError(t *testing.T) {
+	var calls int32
+	failing := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		io.ReadAll(req.Body)
+		rw.Write([]byte(`{"id": "0", "jsonrpc": "2.0", "error": {"code": -2, "message": "Too big height"}}`))
+	}))
+	defer failing.Close()
+	other := setupNode(t, "other", 100, true, &calls)
+	defer other.Close()
+
+	pool := NewPool([]*Endpoint{NewEndpoint(failing.URL, nil), NewEndpoint(other.URL, nil)})
+	is := is.New(t)
+
+	_, err := daemon.New(pool).OnGetBlockHash([]uint64{1000})
+	var rpcErr *Error
+	is.True(errors.As(err, &rpcErr))
+	is.Equal(rpcErr.Code, -2)
+	is.Equal(atomic.LoadInt32(&calls), int32(0))
+}
+
+func TestPoolEmpty(t *testing.T) {
+	_, err := daemon.New(NewPool(nil)).GetBlockCount()
+	is.New(t).True(errors.Is(err, ErrNoNodes))
+}
+
+func TestPoolCancelKeepsNodeHealthy(t *testing.T) {
+	started := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		io.ReadAll(req.Body)
+		close(started)
+		<-req.Context().Done()
+	}))
+	defer slow.Close()
+
+	pool := NewPool([]*Endpoint{NewEndpoint(slow.URL, nil)})
+	is := is.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-started
+		cancel()
+	}()
+	_, err := daemon.New(pool).GetBlockCountContext(ctx)
+	is.True(errors.Is(err, context.Canceled))
+	is.True(pool.Status()[0].Healthy)
+
+	// a check under a canceled context keeps the last known health
+	pool.Check(ctx)
+	is.True(pool.Status()[0].Healthy)
+}
@@ -0,0 +1,92 @@
+// Package proof checks the payment proofs of wallet-rpc with only a monerod, without a wallet.
+//
+// Transactions are fetched from the daemon, which is trusted: use a node you run.
+package proof
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"filippo.io/edwards25519"
+	"github.com/MarinX/monerorpc/base58"
+	"github.com/MarinX/monerorpc/crypto"
+	"github.com/MarinX/monerorpc/daemon"
+	"github.com/MarinX/monerorpc/tx"
+)
+
+var (
+	// ErrInvalidTxKey is returned for tx keys that are not hex encoded secret keys
+	ErrInvalidTxKey = errors.New("proof: invalid tx key")
+	// ErrInvalidTxID is returned for transaction ids that are not 32 hex encoded bytes
+	ErrInvalidTxID = errors.New("proof: invalid txid")
+	// ErrInvalidProof is returned for proofs that cannot be decoded or do not match the transactions they prove
+	ErrInvalidProof = errors.New("proof: invalid proof")
+	// ErrSubaddress is returned for reserve proofs checked against a subaddress
+	ErrSubaddress = errors.New("proof: address must not be a subaddress")
+	// ErrDaemonResponse is returned when the daemon does not answer every item of a request
+	ErrDaemonResponse = errors.New("proof: incomplete daemon response")
+)
+
+// one is the scalar 1, multiplying shared secrets by the cofactor only
+var one, _ = edwards25519.NewScalar().SetCanonicalBytes([]byte{1, 31: 0})
+
+func parseHash(s string) ([32]byte, error) {
+	var h [32]byte
+	b, err := hex.DecodeString(s)
+	if err != nil || len(b) != len(h) {
+		return h, fmt.Errorf("%w %q", ErrInvalidTxID, s)
+	}
+	copy(h[:], b)
+	return h, nil
+}
+
+// parseVersion reads the "V1" or "V2" following the header of a proof
+func parseVersion(s string) (int, string, error) {
+	switch {
+	case strings.HasPrefix(s, "V1"):
+		return 1, s[2:], nil
+	case strings.HasPrefix(s, "V2"):
+		return 2, s[2:], nil
+	}
+	return 0, s, fmt.Errorf("%w: unknown version", ErrInvalidProof)
+}
+
+func decodeKey(s string) ([32]byte, error) {
+	var k [32]byte
+	b, err := base58.Decode(s)
+	if err != nil || len(b) != len(k) {
+		return k, fmt.Errorf("%w: bad key encoding", ErrInvalidProof)
+	}
+	copy(k[:], b)
+	return k, nil
+}
+
+func decodeSignature(s string) (crypto.Signature, error) {
+	b, err := base58.Decode(s)
+	if err != nil {
+		return crypto.Signature{}, fmt.Errorf("%w: bad signature encoding", ErrInvalidProof)
+	}
+	sig, ok := crypto.SignatureFromBytes(b)
+	if !ok {
+		return sig, fmt.Errorf("%w: bad signature encoding", ErrInvalidProof)
+	}
+	return sig, nil
+}
+
+// confirmations returns the number of blocks mined since the block of t, 0 while it is in the pool
+func confirmations(ctx context.Context, d daemon.Daemon, t *tx.Transaction) (uint64, error) {
+	if t.InPool {
+		return 0, nil
+	}
+	res, err := d.GetHeightContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if res.Height < t.BlockHeight {
+		return 0, nil
+	}
+	return res.Height - t.BlockHeight, nil
+}
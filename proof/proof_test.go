@@ -0,0 +1,124 @@
+package proof
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/MarinX/monerorpc/crypto"
+	"github.com/MarinX/monerorpc/daemon"
+	"github.com/MarinX/monerorpc/tx"
+	"github.com/matryer/is"
+)
+
+const height = 1000
+
+// stagenet wallet used by the tests
+const (
+	stagenetAddress = "53zEYzu2hi3e97tdMTqTvSRAfFYXwxA7LBJEHLWvFnm699WgcsE8CJujENwNAQotKyY2u94vpbGEZTiwahuMcMfX3x6NFwY"
+	spendSecret     = "372fcc2abc6bc5015103aae4763822e45c4cfe775d163f97a9ebdd77b0d12c0c"
+	viewSecret      = "8aa763d1c8d9da4ca75cb6ca22a021b5cca376c1367be8d62bcc9cdf4b926009"
+)
+
+type entry struct {
+	tx          *tx.Transaction
+	blockHeight uint64
+	inPool      bool
+}
+
+// fakeDaemon serves transactions, outputs, key images and the height of a chain of height blocks
+type fakeDaemon struct {
+	daemon.Daemon
+	txs   map[string]entry
+	outs  map[daemon.OutRequest][32]byte
+	spent map[string]uint64
+}
+
+func newFakeDaemon() *fakeDaemon {
+	return &fakeDaemon{
+		txs:   make(map[string]entry),
+		outs:  make(map[daemon.OutRequest][32]byte),
+		spent: make(map[string]uint64),
+	}
+}
+
+func (f *fakeDaemon) add(hash string, t *tx.Transaction, blockHeight uint64, inPool bool) {
+	f.txs[hash] = entry{tx: t, blockHeight: blockHeight, inPool: inPool}
+}
+
+func (f *fakeDaemon) GetTransactionsContext(ctx context.Context, req *daemon.GetTransactionsRequest) (*daemon.GetTransactionsResponse, error) {
+	res := new(daemon.GetTransactionsResponse)
+	for _, h := range req.TxsHashes {
+		e, ok := f.txs[h]
+		if !ok {
+			res.MissedTx = append(res.MissedTx, h)
+			continue
+		}
+		b, err := json.Marshal(e.tx)
+		if err != nil {
+			return nil, err
+		}
+		res.Txs = append(res.Txs, daemon.Transaction{AsJSON: string(b), TxHash: h, BlockHeight: e.blockHeight, InPool: e.inPool})
+	}
+	return res, nil
+}
+
+func (f *fakeDaemon) GetHeightContext(ctx context.Context) (*daemon.GetHeightResponse, error) {
+	return &daemon.GetHeightResponse{Height: height}, nil
+}
+
+func (f *fakeDaemon) GetOutsContext(ctx context.Context, req *daemon.GetOutsRequest) (*daemon.GetOutsResponse, error) {
+	res := new(daemon.GetOutsResponse)
+	for _, o := range req.Outputs {
+		if k, ok := f.outs[o]; ok {
+			res.Outs = append(res.Outs, daemon.Out{Key: hex.EncodeToString(k[:]), Unlocked: true})
+		}
+	}
+	return res, nil
+}
+
+func (f *fakeDaemon) IsKeyImageSpentContext(ctx context.Context, req *daemon.IsKeyImageSpentRequest) (*daemon.IsKeyImageSpentResponse, error) {
+	res := new(daemon.IsKeyImageSpentResponse)
+	for _, k := range req.KeyImages {
+		res.SpentStatus = append(res.SpentStatus, f.spent[k])
+	}
+	return res, nil
+}
+
+func key(s string) [32]byte {
+	var k [32]byte
+	hex.Decode(k[:], []byte(s))
+	return k
+}
+
+// pay appends an output of amount to spendKey to t, with the derivation shared with the recipient
+func pay(is *is.I, t *tx.Transaction, derivation [32]byte, spendKey [32]byte, amount uint64) {
+	index := uint64(len(t.Outputs))
+	out, err := crypto.DerivePublicKey(derivation, index, spendKey)
+	is.NoErr(err)
+	secret := crypto.DerivationToScalar(derivation, index).Bytes()
+	pad := crypto.Keccak256([]byte("amount"), secret)
+	enc := make([]byte, 8)
+	binary.LittleEndian.PutUint64(enc, amount)
+	for i := range enc {
+		enc[i] ^= pad[i]
+	}
+	mask := crypto.HashToScalar([]byte("commitment_mask"), secret)
+
+	t.Outputs = append(t.Outputs, tx.Output{Target: tx.Target{TaggedKey: &tx.TaggedKey{Key: out, ViewTag: tx.Hex{crypto.ViewTag(derivation, index)}}}})
+	t.RingCT.EcdhInfo = append(t.RingCT.EcdhInfo, tx.EcdhInfo{Amount: enc})
+	t.RingCT.OutPk = append(t.RingCT.OutPk, crypto.Commit(mask, amount))
+}
+
+func TestConfirmations(t *testing.T) {
+	is := is.New(t)
+	d := newFakeDaemon()
+	c, err := confirmations(context.Background(), d, &tx.Transaction{BlockHeight: 990})
+	is.NoErr(err)
+	is.Equal(c, uint64(10))
+	c, err = confirmations(context.Background(), d, &tx.Transaction{InPool: true})
+	is.NoErr(err)
+	is.Equal(c, uint64(0))
+}
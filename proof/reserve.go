@@ -0,0 +1,290 @@
+package proof
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/MarinX/monerorpc/address"
+	"github.com/MarinX/monerorpc/base58"
+	"github.com/MarinX/monerorpc/crypto"
+	"github.com/MarinX/monerorpc/daemon"
+	"github.com/MarinX/monerorpc/tx"
+	"github.com/MarinX/monerorpc/wallet"
+)
+
+// ReserveProofHeader is the header of reserve proofs, followed by the version
+const ReserveProofHeader = "ReserveProof"
+
+// reserveProofEntrySize is the smallest serialization of a ReserveProofEntry, with one byte varints
+const reserveProofEntrySize = 1 + 32 + 1 + 32 + 32 + 64 + 64
+
+// ReserveProofEntry proves the ownership of an unspent output
+type ReserveProofEntry struct {
+	TxID      [32]byte
+	IndexInTx uint64
+	// SharedSecret is the secret view key times the tx public key of the output, not multiplied by the cofactor
+	SharedSecret [32]byte
+	KeyImage     [32]byte
+	// SharedSecretSig is a tx proof of the shared secret by the secret view key
+	SharedSecretSig crypto.Signature
+	// KeyImageSig is a ring signature of the key image by the output alone
+	KeyImageSig crypto.Signature
+}
+
+// ReserveProof is a proof made by wallet-rpc's get_reserve_proof that an address owns outputs
+type ReserveProof struct {
+	// Version is 1 or 2
+	Version int
+	Entries []ReserveProofEntry
+	// SpendKeys maps the spend keys of the subaddresses owning the outputs, and of the address, to signatures by them
+	SpendKeys map[[32]byte]crypto.Signature
+}
+
+// reader reads the binary serialization of wallet2, keeping the first error
+type reader struct {
+	b   []byte
+	err error
+}
+
+func (r *reader) varint() uint64 {
+	if r.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(r.b)
+	if n <= 0 {
+		r.err = fmt.Errorf("%w: bad varint", ErrInvalidProof)
+		return 0
+	}
+	r.b = r.b[n:]
+	return v
+}
+
+func (r *reader) read(dst []byte) {
+	if r.err != nil {
+		return
+	}
+	if len(r.b) < len(dst) {
+		r.err = fmt.Errorf("%w: truncated", ErrInvalidProof)
+		return
+	}
+	copy(dst, r.b)
+	r.b = r.b[len(dst):]
+}
+
+func (r *reader) signature() crypto.Signature {
+	var b [64]byte
+	r.read(b[:])
+	sig, _ := crypto.SignatureFromBytes(b[:])
+	return sig
+}
+
+// count reads the size of a container whose elements take at least size bytes
+func (r *reader) count(size int) int {
+	n := r.varint()
+	if r.err == nil && n > uint64(len(r.b)/size) {
+		r.err = fmt.Errorf("%w: %d elements left in %d bytes", ErrInvalidProof, n, len(r.b))
+		return 0
+	}
+	return int(n)
+}
+
+// ParseReserveProof decodes a "ReserveProofV2" string, or its version 1. Only the binary serialization is read,
+// not the boost archives of wallets before v0.18.
+func ParseReserveProof(s string) (*ReserveProof, error) {
+	if !strings.HasPrefix(s, ReserveProofHeader) {
+		return nil, fmt.Errorf("%w: unknown header", ErrInvalidProof)
+	}
+	version, rest, err := parseVersion(s[len(ReserveProofHeader):])
+	if err != nil {
+		return nil, err
+	}
+	b, err := base58.Decode(rest)
+	if err != nil {
+		return nil, fmt.Errorf("%w: bad encoding", ErrInvalidProof)
+	}
+
+	p := &ReserveProof{Version: version, SpendKeys: make(map[[32]byte]crypto.Signature)}
+	r := &reader{b: b}
+	p.Entries = make([]ReserveProofEntry, r.count(reserveProofEntrySize))
+	for i := range p.Entries {
+		e := &p.Entries[i]
+		if v := r.varint(); r.err == nil && v != 0 {
+			return nil, fmt.Errorf("%w: entry version %d", ErrInvalidProof, v)
+		}
+		r.read(e.TxID[:])
+		e.IndexInTx = r.varint()
+		r.read(e.SharedSecret[:])
+		r.read(e.KeyImage[:])
+		e.SharedSecretSig = r.signature()
+		e.KeyImageSig = r.signature()
+	}
+	n := r.count(1 + 32 + 64)
+	for i := 0; i < n; i++ {
+		// the pairs of the map are arrays of 2 elements
+		if v := r.varint(); r.err == nil && v != 2 {
+			return nil, fmt.Errorf("%w: pair of %d elements", ErrInvalidProof, v)
+		}
+		var k [32]byte
+		r.read(k[:])
+		p.SpendKeys[k] = r.signature()
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	if len(r.b) > 0 {
+		return nil, fmt.Errorf("%w: %d trailing bytes", ErrInvalidProof, len(r.b))
+	}
+	return p, nil
+}
+
+// String encodes the proof like get_reserve_proof, with the spend keys sorted
+func (p *ReserveProof) String() string {
+	b := binary.AppendUvarint(nil, uint64(len(p.Entries)))
+	for _, e := range p.Entries {
+		b = binary.AppendUvarint(b, 0)
+		b = append(b, e.TxID[:]...)
+		b = binary.AppendUvarint(b, e.IndexInTx)
+		b = append(b, e.SharedSecret[:]...)
+		b = append(b, e.KeyImage[:]...)
+		b = append(b, e.SharedSecretSig.Bytes()...)
+		b = append(b, e.KeyImageSig.Bytes()...)
+	}
+	keys := make([][32]byte, 0, len(p.SpendKeys))
+	for k := range p.SpendKeys {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return bytes.Compare(keys[i][:], keys[j][:]) < 0 })
+	b = binary.AppendUvarint(b, uint64(len(keys)))
+	for _, k := range keys {
+		b = binary.AppendUvarint(b, 2)
+		b = append(b, k[:]...)
+		b = append(b, p.SpendKeys[k].Bytes()...)
+	}
+	return fmt.Sprintf("%sV%d%s", ReserveProofHeader, p.Version, base58.Encode(b))
+}
+
+// hash returns the prefix hash signed by the proof, keccak(message || spend key || view key || key images)
+func (p *ReserveProof) hash(addr *address.Address, message string) [32]byte {
+	data := [][]byte{[]byte(message), addr.SpendKey[:], addr.ViewKey[:]}
+	for _, e := range p.Entries {
+		data = append(data, e.KeyImage[:])
+	}
+	return crypto.Keccak256(data...)
+}
+
+// Check verifies the proof for the address addr with the message of the proof, txs holding the transaction of
+// every entry. It returns whether the proof is good and the amount of every entry.
+func (p *ReserveProof) Check(txs []*tx.Transaction, addr *address.Address, message string) (bool, []uint64, error) {
+	if _, ok := p.SpendKeys[addr.SpendKey]; !ok {
+		return false, nil, fmt.Errorf("%w: address not found in the proof", ErrInvalidProof)
+	}
+	if len(txs) != len(p.Entries) {
+		return false, nil, fmt.Errorf("%w: %d transactions for %d entries", ErrInvalidProof, len(txs), len(p.Entries))
+	}
+
+	hash := p.hash(addr, message)
+	amounts := make([]uint64, len(p.Entries))
+	for i, e := range p.Entries {
+		t := txs[i]
+		if t.InPool {
+			return false, nil, fmt.Errorf("%w: tx %x is unconfirmed", ErrInvalidProof, e.TxID)
+		}
+		if e.IndexInTx >= uint64(len(t.Outputs)) {
+			return false, nil, fmt.Errorf("%w: output %d of tx %x out of bounds", ErrInvalidProof, e.IndexInTx, e.TxID)
+		}
+		out, ok := t.Outputs[e.IndexInTx].Key()
+		if !ok {
+			return false, nil, fmt.Errorf("%w: output key of tx %x not found", ErrInvalidProof, e.TxID)
+		}
+		txKey, ok := t.Extra.PublicKey()
+		if !ok {
+			return false, nil, fmt.Errorf("%w: tx public key of tx %x not found", ErrInvalidProof, e.TxID)
+		}
+
+		good := crypto.CheckTxProof(hash, addr.ViewKey, txKey, nil, e.SharedSecret, e.SharedSecretSig, p.Version)
+		if additional := t.Extra.AdditionalPublicKeys(); !good && len(additional) == len(t.Outputs) {
+			good = crypto.CheckTxProof(hash, addr.ViewKey, additional[e.IndexInTx], nil, e.SharedSecret, e.SharedSecretSig, p.Version)
+		}
+		if !good {
+			return false, nil, nil
+		}
+		if !crypto.CheckRingSignature(hash, e.KeyImage, [][32]byte{out}, []crypto.Signature{e.KeyImageSig}) {
+			return false, nil, nil
+		}
+
+		derivation, err := crypto.GenerateKeyDerivation(e.SharedSecret, one)
+		if err != nil {
+			return false, nil, err
+		}
+		spendKey, err := crypto.DeriveSubaddressPublicKey(derivation, e.IndexInTx, out)
+		if err != nil {
+			return false, nil, err
+		}
+		if _, ok := p.SpendKeys[spendKey]; !ok {
+			return false, nil, fmt.Errorf("%w: output %d of tx %x not received by the address", ErrInvalidProof, e.IndexInTx, e.TxID)
+		}
+		// outputs whose amount does not match their commitment count as 0
+		amounts[i], _ = t.Amount(int(e.IndexInTx), crypto.DerivationToScalar(derivation, e.IndexInTx))
+	}
+
+	for k, sig := range p.SpendKeys {
+		if !crypto.CheckSignature(hash, k, sig) {
+			return false, nil, nil
+		}
+	}
+	return true, amounts, nil
+}
+
+// CheckReserveProof checks a proof that req.Address owns outputs, like wallet-rpc's check_reserve_proof
+func CheckReserveProof(ctx context.Context, d daemon.Daemon, req *wallet.CheckReserveProofRequest) (*wallet.CheckReserveProofResponse, error) {
+	addr, err := address.Parse(req.Address)
+	if err != nil {
+		return nil, err
+	}
+	if addr.Type == address.Subaddress {
+		return nil, ErrSubaddress
+	}
+	p, err := ParseReserveProof(req.Signature)
+	if err != nil {
+		return nil, err
+	}
+
+	hashes := make([]string, len(p.Entries))
+	images := make([]string, len(p.Entries))
+	for i, e := range p.Entries {
+		hashes[i] = hex.EncodeToString(e.TxID[:])
+		images[i] = hex.EncodeToString(e.KeyImage[:])
+	}
+	txs, err := tx.Fetch(ctx, d, hashes...)
+	if err != nil {
+		return nil, err
+	}
+	spent, err := d.IsKeyImageSpentContext(ctx, &daemon.IsKeyImageSpentRequest{KeyImages: images})
+	if err != nil {
+		return nil, err
+	}
+	if len(spent.SpentStatus) != len(images) {
+		return nil, fmt.Errorf("%w: %d statuses for %d key images", ErrDaemonResponse, len(spent.SpentStatus), len(images))
+	}
+
+	good, amounts, err := p.Check(txs, addr, req.Message)
+	if err != nil {
+		return nil, err
+	}
+	if !good {
+		return &wallet.CheckReserveProofResponse{}, nil
+	}
+	res := &wallet.CheckReserveProofResponse{Good: true}
+	for i, amount := range amounts {
+		res.Total += amount
+		if spent.SpentStatus[i] != daemon.KeyImageUnspent {
+			res.Spent += amount
+		}
+	}
+	return res, nil
+}
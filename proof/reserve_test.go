@@ -0,0 +1,164 @@
+package proof
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+
+	"filippo.io/edwards25519"
+	"github.com/MarinX/monerorpc/address"
+	"github.com/MarinX/monerorpc/crypto"
+	"github.com/MarinX/monerorpc/daemon"
+	"github.com/MarinX/monerorpc/tx"
+	"github.com/MarinX/monerorpc/wallet"
+	"github.com/matryer/is"
+)
+
+// owned is an output of the stagenet wallet
+type owned struct {
+	t        *tx.Transaction
+	txid     [32]byte
+	shared   [32]byte
+	secret   *edwards25519.Scalar
+	spendKey [32]byte
+	// spend is the private spend key of the subaddress, b + m
+	spend *edwards25519.Scalar
+}
+
+// receive returns a transaction paying amount to the subaddress (major, minor) of the stagenet wallet in its
+// first output, and 7 to someone else
+func receive(is *is.I, g *address.Generator, major, minor uint32, amount uint64) owned {
+	view, err := crypto.ParseScalar(key(viewSecret))
+	is.NoErr(err)
+	spend, err := crypto.ParseScalar(key(spendSecret))
+	is.NoErr(err)
+	r, err := crypto.RandomScalar()
+	is.NoErr(err)
+
+	o := owned{txid: crypto.Keccak256([]byte{byte(major), byte(minor)}), spendKey: g.SpendKey(major, minor)}
+	// subaddresses are paid with an additional tx key r·D
+	txKey := crypto.Bytes(new(edwards25519.Point).ScalarBaseMult(r))
+	e := extra(txKey)
+	o.shared = mul(is, view, txKey)
+	if major != 0 || minor != 0 {
+		additional := mul(is, r, o.spendKey)
+		e = extra(txKey, additional, txKey)
+		o.shared = mul(is, view, additional)
+	}
+	o.t = &tx.Transaction{Version: 2, Extra: e, RingCT: tx.RingCT{Type: tx.RCTTypeBulletproofPlus}}
+	derivation, err := crypto.GenerateKeyDerivation(o.shared, one)
+	is.NoErr(err)
+	pay(is, o.t, derivation, o.spendKey, amount)
+	pay(is, o.t, derivation, txKey, 7)
+
+	m, err := crypto.ParseScalar(g.SecretKey(major, minor))
+	is.NoErr(err)
+	o.spend = new(edwards25519.Scalar).Add(spend, m)
+	o.secret = new(edwards25519.Scalar).Add(crypto.DerivationToScalar(derivation, 0), o.spend)
+	return o
+}
+
+// reserve proves the ownership of outs like get_reserve_proof
+func reserve(is *is.I, addr *address.Address, message string, version int, outs ...owned) *ReserveProof {
+	view, err := crypto.ParseScalar(key(viewSecret))
+	is.NoErr(err)
+	spend, err := crypto.ParseScalar(key(spendSecret))
+	is.NoErr(err)
+
+	p := &ReserveProof{Version: version, SpendKeys: make(map[[32]byte]crypto.Signature)}
+	for _, o := range outs {
+		out, _ := o.t.Outputs[0].Key()
+		p.Entries = append(p.Entries, ReserveProofEntry{TxID: o.txid, SharedSecret: o.shared, KeyImage: crypto.KeyImage(out, o.secret)})
+	}
+	hash := p.hash(addr, message)
+	for i, o := range outs {
+		e := &p.Entries[i]
+		txKey := o.t.Extra.AdditionalPublicKeys()
+		if len(txKey) == 0 {
+			k, _ := o.t.Extra.PublicKey()
+			txKey = append(txKey, k)
+		}
+		e.SharedSecretSig, err = crypto.GenerateTxProof(hash, addr.ViewKey, txKey[0], nil, e.SharedSecret, view, version)
+		is.NoErr(err)
+		out, _ := o.t.Outputs[0].Key()
+		sigs, err := crypto.GenerateRingSignature(hash, e.KeyImage, [][32]byte{out}, o.secret, 0)
+		is.NoErr(err)
+		e.KeyImageSig = sigs[0]
+		p.SpendKeys[o.spendKey], err = crypto.GenerateSignature(hash, o.spendKey, o.spend)
+		is.NoErr(err)
+	}
+	p.SpendKeys[addr.SpendKey], err = crypto.GenerateSignature(hash, addr.SpendKey, spend)
+	is.NoErr(err)
+	return p
+}
+
+func TestReserveProof(t *testing.T) {
+	is := is.New(t)
+	addr, err := address.Parse(stagenetAddress)
+	is.NoErr(err)
+	g, err := address.NewGenerator(address.Stagenet, key(viewSecret), addr.SpendKey)
+	is.NoErr(err)
+	primary := receive(is, g, 0, 0, 1000)
+	sub := receive(is, g, 0, 3, 250)
+
+	d := newFakeDaemon()
+	d.add(hex.EncodeToString(primary.txid[:]), primary.t, 900, false)
+	d.add(hex.EncodeToString(sub.txid[:]), sub.t, 950, false)
+	p := reserve(is, addr, "audit", 2, primary, sub)
+	d.spent[hex.EncodeToString(p.Entries[1].KeyImage[:])] = daemon.KeyImageSpentInChain
+
+	s := p.String()
+	is.True(strings.HasPrefix(s, "ReserveProofV2"))
+	parsed, err := ParseReserveProof(s)
+	is.NoErr(err)
+	is.Equal(parsed, p)
+
+	res, err := CheckReserveProof(context.Background(), d, &wallet.CheckReserveProofRequest{Address: stagenetAddress, Message: "audit", Signature: s})
+	is.NoErr(err)
+	is.Equal(res, &wallet.CheckReserveProofResponse{Good: true, Total: 1250, Spent: 250})
+
+	res, err = CheckReserveProof(context.Background(), d, &wallet.CheckReserveProofRequest{Address: stagenetAddress, Message: "audit 2", Signature: s})
+	is.NoErr(err)
+	is.True(!res.Good)
+
+	v1 := reserve(is, addr, "", 1, primary).String()
+	is.True(strings.HasPrefix(v1, "ReserveProofV1"))
+	res, err = CheckReserveProof(context.Background(), d, &wallet.CheckReserveProofRequest{Address: stagenetAddress, Signature: v1})
+	is.NoErr(err)
+	is.Equal(res, &wallet.CheckReserveProofResponse{Good: true, Total: 1000})
+}
+
+func TestReserveProofErrors(t *testing.T) {
+	is := is.New(t)
+	addr, err := address.Parse(stagenetAddress)
+	is.NoErr(err)
+	g, err := address.NewGenerator(address.Stagenet, key(viewSecret), addr.SpendKey)
+	is.NoErr(err)
+	o := receive(is, g, 0, 0, 1000)
+	d := newFakeDaemon()
+	d.add(hex.EncodeToString(o.txid[:]), o.t, 0, true)
+	p := reserve(is, addr, "", 2, o)
+
+	// proofs are checked against primary addresses only
+	_, err = CheckReserveProof(context.Background(), d, &wallet.CheckReserveProofRequest{Address: g.Address(0, 1).String(), Signature: p.String()})
+	is.True(errors.Is(err, ErrSubaddress))
+
+	_, err = CheckReserveProof(context.Background(), d, &wallet.CheckReserveProofRequest{Address: stagenetAddress, Signature: p.String()})
+	is.True(errors.Is(err, ErrInvalidProof)) // unconfirmed
+
+	delete(p.SpendKeys, addr.SpendKey)
+	_, _, err = p.Check([]*tx.Transaction{o.t}, addr, "")
+	is.True(errors.Is(err, ErrInvalidProof)) // address not in the proof
+
+	for _, s := range []string{
+		"ReserveProofV3",
+		"ReserveProofV2",
+		"ReserveProofV20OO",
+		p.String() + "1",
+	} {
+		_, err := ParseReserveProof(s)
+		is.True(errors.Is(err, ErrInvalidProof))
+	}
+}
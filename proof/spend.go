@@ -0,0 +1,183 @@
+package proof
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/MarinX/monerorpc/base58"
+	"github.com/MarinX/monerorpc/crypto"
+	"github.com/MarinX/monerorpc/daemon"
+	"github.com/MarinX/monerorpc/tx"
+	"github.com/MarinX/monerorpc/wallet"
+)
+
+// SpendProofHeader is the header of spend proofs
+const SpendProofHeader = "SpendProofV1"
+
+// SpendProof is a proof made by wallet-rpc's get_spend_proof that the sender of a transaction spent its inputs
+type SpendProof struct {
+	// Signatures holds a ring signature for every key input of the transaction
+	Signatures [][]crypto.Signature
+}
+
+// errSpendProofSize is returned for spend proofs whose number of signatures does not match the rings of
+// the transaction, which wallet2 reports as not good rather than as an error
+var errSpendProofSize = fmt.Errorf("%w: wrong signature size", ErrInvalidProof)
+
+// ParseSpendProof decodes a "SpendProofV1" string, whose signatures are split along the rings of the key
+// inputs of the transaction t it proves
+func ParseSpendProof(s string, t *tx.Transaction) (*SpendProof, error) {
+	sigs, err := decodeSpendProof(s)
+	if err != nil {
+		return nil, err
+	}
+	return splitSpendProof(sigs, t)
+}
+
+// decodeSpendProof decodes the signatures of a "SpendProofV1" string
+func decodeSpendProof(s string) ([]crypto.Signature, error) {
+	if !strings.HasPrefix(s, SpendProofHeader) {
+		return nil, fmt.Errorf("%w: unknown header", ErrInvalidProof)
+	}
+	rest := s[len(SpendProofHeader):]
+	if len(rest)%encodedSignatureSize != 0 {
+		return nil, errSpendProofSize
+	}
+	sigs := make([]crypto.Signature, 0, len(rest)/encodedSignatureSize)
+	for ; rest != ""; rest = rest[encodedSignatureSize:] {
+		sig, err := decodeSignature(rest[:encodedSignatureSize])
+		if err != nil {
+			return nil, err
+		}
+		sigs = append(sigs, sig)
+	}
+	return sigs, nil
+}
+
+// splitSpendProof splits sigs along the rings of the key inputs of t
+func splitSpendProof(sigs []crypto.Signature, t *tx.Transaction) (*SpendProof, error) {
+	size := 0
+	for _, in := range t.Inputs {
+		if in.Key != nil {
+			size += len(in.Key.KeyOffsets)
+		}
+	}
+	if len(sigs) != size {
+		return nil, errSpendProofSize
+	}
+	p := &SpendProof{}
+	for _, in := range t.Inputs {
+		if in.Key == nil {
+			continue
+		}
+		n := len(in.Key.KeyOffsets)
+		p.Signatures = append(p.Signatures, sigs[:n:n])
+		sigs = sigs[n:]
+	}
+	return p, nil
+}
+
+// String encodes the proof like get_spend_proof
+func (p *SpendProof) String() string {
+	var b strings.Builder
+	b.WriteString(SpendProofHeader)
+	for _, sigs := range p.Signatures {
+		for _, sig := range sigs {
+			b.WriteString(base58.Encode(sig.Bytes()))
+		}
+	}
+	return b.String()
+}
+
+// Rings fetches with get_outs the keys of the ring members of every key input of t
+func Rings(ctx context.Context, d daemon.Daemon, t *tx.Transaction) ([][][32]byte, error) {
+	req := &daemon.GetOutsRequest{}
+	for _, in := range t.Inputs {
+		if in.Key == nil {
+			continue
+		}
+		for _, index := range in.Key.AbsoluteOffsets() {
+			req.Outputs = append(req.Outputs, daemon.OutRequest{Amount: in.Key.Amount, Index: index})
+		}
+	}
+	res, err := d.GetOutsContext(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if len(res.Outs) != len(req.Outputs) {
+		return nil, fmt.Errorf("%w: %d outputs for %d requested", ErrDaemonResponse, len(res.Outs), len(req.Outputs))
+	}
+
+	var rings [][][32]byte
+	outs := res.Outs
+	for _, in := range t.Inputs {
+		if in.Key == nil {
+			continue
+		}
+		ring := make([][32]byte, len(in.Key.KeyOffsets))
+		for i := range ring {
+			b, err := hex.DecodeString(outs[i].Key)
+			if err != nil || len(b) != len(ring[i]) {
+				return nil, fmt.Errorf("%w: bad output key %q", ErrDaemonResponse, outs[i].Key)
+			}
+			copy(ring[i][:], b)
+		}
+		rings = append(rings, ring)
+		outs = outs[len(ring):]
+	}
+	return rings, nil
+}
+
+// Check verifies the proof for the transaction t of hash txid with the message of the proof, rings holding the
+// keys of the ring members of the key inputs of t
+func (p *SpendProof) Check(t *tx.Transaction, txid [32]byte, message string, rings [][][32]byte) bool {
+	if len(rings) != len(p.Signatures) {
+		return false
+	}
+	hash := crypto.Keccak256(txid[:], []byte(message))
+	i := 0
+	for _, in := range t.Inputs {
+		if in.Key == nil {
+			continue
+		}
+		if i == len(rings) || !crypto.CheckRingSignature(hash, in.Key.KeyImage, rings[i], p.Signatures[i]) {
+			return false
+		}
+		i++
+	}
+	return i == len(rings)
+}
+
+// CheckSpendProof checks a proof that the sender of the transaction req.TxID spent its inputs, like wallet-rpc's
+// check_spend_proof. As in wallet2, a proof with a wrong header or signatures that cannot be decoded is an error,
+// found before the daemon is called, while a proof whose signatures do not match the rings of the transaction
+// is not good.
+func CheckSpendProof(ctx context.Context, d daemon.Daemon, req *wallet.CheckSpendProofRequest) (*wallet.CheckSpendProofResponse, error) {
+	txid, err := parseHash(req.TxID)
+	if err != nil {
+		return nil, err
+	}
+	sigs, decodeErr := decodeSpendProof(req.Signature)
+	if decodeErr != nil && decodeErr != errSpendProofSize {
+		return nil, decodeErr
+	}
+	txs, err := tx.Fetch(ctx, d, req.TxID)
+	if err != nil {
+		return nil, err
+	}
+	t := txs[0]
+	if decodeErr != nil {
+		return &wallet.CheckSpendProofResponse{Good: false}, nil
+	}
+	p, err := splitSpendProof(sigs, t)
+	if err != nil {
+		return &wallet.CheckSpendProofResponse{Good: false}, nil
+	}
+	rings, err := Rings(ctx, d, t)
+	if err != nil {
+		return nil, err
+	}
+	return &wallet.CheckSpendProofResponse{Good: p.Check(t, txid, req.Message, rings)}, nil
+}
@@ -0,0 +1,99 @@
+package proof
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+
+	"filippo.io/edwards25519"
+	"github.com/MarinX/monerorpc/crypto"
+	"github.com/MarinX/monerorpc/daemon"
+	"github.com/MarinX/monerorpc/tx"
+	"github.com/MarinX/monerorpc/wallet"
+	"github.com/matryer/is"
+)
+
+// spendTx returns a transaction of two inputs of rings of 3, at the global indices 100, 105, 107 and
+// 200, 201, 210 of the outputs served by d, with a spend proof of message
+func spendTx(is *is.I, d *fakeDaemon, txid [32]byte, message string) (*tx.Transaction, *SpendProof) {
+	hash := crypto.Keccak256(txid[:], []byte(message))
+	t := &tx.Transaction{Version: 2, RingCT: tx.RingCT{Type: tx.RCTTypeBulletproofPlus}}
+	p := &SpendProof{}
+	for _, offsets := range [][]uint64{{100, 5, 2}, {200, 1, 9}} {
+		in := &tx.KeyInput{KeyOffsets: offsets}
+		var ring [][32]byte
+		var secrets []*edwards25519.Scalar
+		for _, index := range in.AbsoluteOffsets() {
+			s, err := crypto.RandomScalar()
+			is.NoErr(err)
+			pub := crypto.Bytes(new(edwards25519.Point).ScalarBaseMult(s))
+			d.outs[daemon.OutRequest{Index: index}] = pub
+			ring = append(ring, pub)
+			secrets = append(secrets, s)
+		}
+		// the second member is spent
+		in.KeyImage = crypto.KeyImage(ring[1], secrets[1])
+		sigs, err := crypto.GenerateRingSignature(hash, in.KeyImage, ring, secrets[1], 1)
+		is.NoErr(err)
+		t.Inputs = append(t.Inputs, tx.Input{Key: in})
+		p.Signatures = append(p.Signatures, sigs)
+	}
+	return t, p
+}
+
+func TestSpendProof(t *testing.T) {
+	is := is.New(t)
+	d := newFakeDaemon()
+	txid := crypto.Keccak256([]byte("spend"))
+	hash := hex.EncodeToString(txid[:])
+	spend, p := spendTx(is, d, txid, "paid")
+	d.add(hash, spend, 900, false)
+
+	s := p.String()
+	is.True(strings.HasPrefix(s, "SpendProofV1"))
+	is.Equal(len(s), len("SpendProofV1")+6*88)
+	parsed, err := ParseSpendProof(s, spend)
+	is.NoErr(err)
+	is.Equal(parsed, p)
+
+	res, err := CheckSpendProof(context.Background(), d, &wallet.CheckSpendProofRequest{TxID: hash, Message: "paid", Signature: s})
+	is.NoErr(err)
+	is.True(res.Good)
+	res, err = CheckSpendProof(context.Background(), d, &wallet.CheckSpendProofRequest{TxID: hash, Message: "not paid", Signature: s})
+	is.NoErr(err)
+	is.True(!res.Good)
+
+	// a proof which does not match the rings of the transaction is not good
+	for _, bad := range []string{s[:len(s)-88], s[:len(s)-1]} {
+		res, err = CheckSpendProof(context.Background(), d, &wallet.CheckSpendProofRequest{TxID: hash, Message: "paid", Signature: bad})
+		is.NoErr(err)
+		is.True(!res.Good)
+	}
+	// a proof which cannot be decoded is an error, found without calling the daemon
+	for _, bad := range []string{"SpendProofV2" + s[len("SpendProofV1"):], s[:len(s)-1] + "0"} {
+		_, err = CheckSpendProof(context.Background(), newFakeDaemon(), &wallet.CheckSpendProofRequest{TxID: hash, Message: "paid", Signature: bad})
+		is.True(errors.Is(err, ErrInvalidProof))
+	}
+
+	// a ring member unknown to the daemon
+	delete(d.outs, daemon.OutRequest{Index: 210})
+	_, err = CheckSpendProof(context.Background(), d, &wallet.CheckSpendProofRequest{TxID: hash, Message: "paid", Signature: s})
+	is.True(errors.Is(err, ErrDaemonResponse))
+}
+
+func TestParseSpendProof(t *testing.T) {
+	is := is.New(t)
+	spend, p := spendTx(is, newFakeDaemon(), [32]byte{}, "")
+	s := p.String()
+	for _, s := range []string{
+		"SpendProofV2" + s[len("SpendProofV1"):],
+		s[:len(s)-88],
+		s + s[len(s)-88:],
+		s[:len(s)-1] + "0",
+	} {
+		_, err := ParseSpendProof(s, spend)
+		is.True(errors.Is(err, ErrInvalidProof))
+	}
+}
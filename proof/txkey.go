@@ -0,0 +1,70 @@
+package proof
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+
+	"filippo.io/edwards25519"
+	"github.com/MarinX/monerorpc/address"
+	"github.com/MarinX/monerorpc/crypto"
+	"github.com/MarinX/monerorpc/daemon"
+	"github.com/MarinX/monerorpc/tx"
+	"github.com/MarinX/monerorpc/wallet"
+)
+
+// parseTxKey decodes the tx secret key, followed by the additional tx keys of transactions paying subaddresses
+// as returned by get_tx_key
+func parseTxKey(s string) (*edwards25519.Scalar, []*edwards25519.Scalar, error) {
+	b, err := hex.DecodeString(s)
+	if err != nil || len(b) == 0 || len(b)%32 != 0 {
+		return nil, nil, fmt.Errorf("%w %q", ErrInvalidTxKey, s)
+	}
+	keys := make([]*edwards25519.Scalar, len(b)/32)
+	for i := range keys {
+		var k [32]byte
+		copy(k[:], b[i*32:])
+		if keys[i], err = crypto.ParseScalar(k); err != nil {
+			return nil, nil, fmt.Errorf("%w: %v", ErrInvalidTxKey, err)
+		}
+	}
+	return keys[0], keys[1:], nil
+}
+
+// CheckTxKey returns how much the transaction req.Txid paid to req.Address, checked with the tx secret key
+// like wallet-rpc's check_tx_key. A received amount of 0 means the transaction did not pay the address.
+func CheckTxKey(ctx context.Context, d daemon.Daemon, req *wallet.CheckTxKeyRequest) (*wallet.CheckTxKeyResponse, error) {
+	addr, err := address.Parse(req.Address)
+	if err != nil {
+		return nil, err
+	}
+	txKey, additionalKeys, err := parseTxKey(req.TxKey)
+	if err != nil {
+		return nil, err
+	}
+	derivation, err := crypto.GenerateKeyDerivation(addr.ViewKey, txKey)
+	if err != nil {
+		return nil, err
+	}
+	additional := make([][32]byte, len(additionalKeys))
+	for i, k := range additionalKeys {
+		if additional[i], err = crypto.GenerateKeyDerivation(addr.ViewKey, k); err != nil {
+			return nil, err
+		}
+	}
+
+	txs, err := tx.Fetch(ctx, d, req.Txid)
+	if err != nil {
+		return nil, err
+	}
+	t := txs[0]
+	received, err := t.Received(addr.SpendKey, derivation, additional)
+	if err != nil {
+		return nil, err
+	}
+	res := &wallet.CheckTxKeyResponse{InPool: t.InPool, Received: received}
+	if res.Confirmations, err = confirmations(ctx, d, t); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
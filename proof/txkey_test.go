@@ -0,0 +1,97 @@
+package proof
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"filippo.io/edwards25519"
+	"github.com/MarinX/monerorpc/address"
+	"github.com/MarinX/monerorpc/crypto"
+	"github.com/MarinX/monerorpc/tx"
+	"github.com/MarinX/monerorpc/wallet"
+	"github.com/matryer/is"
+)
+
+func TestCheckTxKey(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+	addr, err := address.Parse(stagenetAddress)
+	is.NoErr(err)
+
+	r, err := crypto.RandomScalar()
+	is.NoErr(err)
+	derivation, err := crypto.GenerateKeyDerivation(addr.ViewKey, r)
+	is.NoErr(err)
+	payment := &tx.Transaction{Version: 2, RingCT: tx.RingCT{Type: tx.RCTTypeCLSAG}}
+	pay(is, payment, derivation, addr.SpendKey, 550000000000)
+	pay(is, payment, derivation, key("b4cdbf52851002fc7b098b99536df8b9885aa6cb8db24e9fc46103674dc9421a"), 1)
+
+	d := newFakeDaemon()
+	d.add("aa", payment, 990, false)
+	d.add("bb", payment, 0, true)
+
+	req := &wallet.CheckTxKeyRequest{Txid: "aa", TxKey: hex.EncodeToString(r.Bytes()), Address: stagenetAddress}
+	res, err := CheckTxKey(ctx, d, req)
+	is.NoErr(err)
+	is.Equal(res, &wallet.CheckTxKeyResponse{Confirmations: 10, Received: 550000000000})
+
+	req.Txid = "bb"
+	res, err = CheckTxKey(ctx, d, req)
+	is.NoErr(err)
+	is.Equal(res, &wallet.CheckTxKeyResponse{InPool: true, Received: 550000000000})
+
+	// another key finds nothing
+	other, err := crypto.RandomScalar()
+	is.NoErr(err)
+	req.TxKey = hex.EncodeToString(other.Bytes())
+	res, err = CheckTxKey(ctx, d, req)
+	is.NoErr(err)
+	is.Equal(res.Received, uint64(0))
+
+	req.TxKey = "zz"
+	_, err = CheckTxKey(ctx, d, req)
+	is.True(errors.Is(err, ErrInvalidTxKey))
+	req.TxKey = hex.EncodeToString(r.Bytes())
+	req.Txid = "cc"
+	_, err = CheckTxKey(ctx, d, req)
+	is.True(errors.Is(err, tx.ErrNotFound))
+}
+
+// transactions paying subaddresses have one additional tx key per output
+func TestCheckTxKeySubaddress(t *testing.T) {
+	is := is.New(t)
+	addr, err := address.Parse(stagenetAddress)
+	is.NoErr(err)
+	g, err := address.NewGenerator(address.Stagenet, key(viewSecret), addr.SpendKey)
+	is.NoErr(err)
+	sub := g.Address(1, 2)
+
+	var keys []*edwards25519.Scalar
+	payment := &tx.Transaction{Version: 2, RingCT: tx.RingCT{Type: tx.RCTTypeBulletproofPlus}}
+	for i := 0; i < 3; i++ {
+		r, err := crypto.RandomScalar()
+		is.NoErr(err)
+		keys = append(keys, r)
+	}
+	for i, amount := range []uint64{0, 1234, 0} {
+		derivation, err := crypto.GenerateKeyDerivation(sub.ViewKey, keys[i])
+		is.NoErr(err)
+		spend := sub.SpendKey
+		if amount == 0 {
+			spend = addr.SpendKey
+		}
+		pay(is, payment, derivation, spend, amount)
+	}
+	d := newFakeDaemon()
+	d.add("aa", payment, 999, false)
+
+	txKey := hex.EncodeToString(edwards25519.NewScalar().Bytes())
+	for _, k := range keys {
+		txKey += hex.EncodeToString(k.Bytes())
+	}
+	res, err := CheckTxKey(context.Background(), d, &wallet.CheckTxKeyRequest{Txid: "aa", TxKey: txKey, Address: sub.String()})
+	is.NoErr(err)
+	is.Equal(res, &wallet.CheckTxKeyResponse{Confirmations: 1, Received: 1234})
+}
@@ -0,0 +1,171 @@
+package proof
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/MarinX/monerorpc/address"
+	"github.com/MarinX/monerorpc/base58"
+	"github.com/MarinX/monerorpc/crypto"
+	"github.com/MarinX/monerorpc/daemon"
+	"github.com/MarinX/monerorpc/tx"
+	"github.com/MarinX/monerorpc/wallet"
+)
+
+// Headers of tx proofs, followed by the version
+const (
+	OutProofHeader = "OutProof"
+	InProofHeader  = "InProof"
+)
+
+// Sizes of the base58 encodings of a key and of a signature
+const (
+	encodedKeySize       = 44
+	encodedSignatureSize = 88
+)
+
+// TxProof is a proof made by wallet-rpc's get_tx_proof that a transaction paid an address. Out proofs are made
+// by the sender with the tx secret keys, in proofs by the recipient with its secret view key.
+type TxProof struct {
+	// Out is true for out proofs
+	Out bool
+	// Version is 1 or 2
+	Version int
+	// SharedSecrets holds the shared secret of the tx public key, followed by those of the additional tx public keys
+	SharedSecrets [][32]byte
+	// Signatures holds a signature for every shared secret
+	Signatures []crypto.Signature
+}
+
+// ParseTxProof decodes an "OutProofV2" or "InProofV2" string, or their version 1
+func ParseTxProof(s string) (*TxProof, error) {
+	p := &TxProof{}
+	rest := s
+	switch {
+	case strings.HasPrefix(rest, OutProofHeader):
+		p.Out = true
+		rest = rest[len(OutProofHeader):]
+	case strings.HasPrefix(rest, InProofHeader):
+		rest = rest[len(InProofHeader):]
+	default:
+		return nil, fmt.Errorf("%w: unknown header", ErrInvalidProof)
+	}
+	version, rest, err := parseVersion(rest)
+	if err != nil {
+		return nil, err
+	}
+	p.Version = version
+
+	const size = encodedKeySize + encodedSignatureSize
+	if len(rest) == 0 || len(rest)%size != 0 {
+		return nil, fmt.Errorf("%w: wrong signature size", ErrInvalidProof)
+	}
+	for ; len(rest) > 0; rest = rest[size:] {
+		secret, err := decodeKey(rest[:encodedKeySize])
+		if err != nil {
+			return nil, err
+		}
+		sig, err := decodeSignature(rest[encodedKeySize:size])
+		if err != nil {
+			return nil, err
+		}
+		p.SharedSecrets = append(p.SharedSecrets, secret)
+		p.Signatures = append(p.Signatures, sig)
+	}
+	return p, nil
+}
+
+// String encodes the proof like get_tx_proof
+func (p *TxProof) String() string {
+	var b strings.Builder
+	if p.Out {
+		b.WriteString(OutProofHeader)
+	} else {
+		b.WriteString(InProofHeader)
+	}
+	fmt.Fprintf(&b, "V%d", p.Version)
+	for i := range p.SharedSecrets {
+		b.WriteString(base58.Encode(p.SharedSecrets[i][:]))
+		b.WriteString(base58.Encode(p.Signatures[i].Bytes()))
+	}
+	return b.String()
+}
+
+// Check verifies the proof for the transaction t of hash txid, paying addr, with the message of the proof.
+// It returns whether a signature is good and the amount received by the outputs of the good ones.
+func (p *TxProof) Check(t *tx.Transaction, txid [32]byte, addr *address.Address, message string) (bool, uint64, error) {
+	txKey, ok := t.Extra.PublicKey()
+	if !ok {
+		return false, 0, fmt.Errorf("%w: tx public key not found", ErrInvalidProof)
+	}
+	txKeys := append([]tx.Key{txKey}, t.Extra.AdditionalPublicKeys()...)
+	if len(txKeys) != len(p.SharedSecrets) || len(p.Signatures) != len(p.SharedSecrets) {
+		return false, 0, fmt.Errorf("%w: %d signatures for %d tx public keys", ErrInvalidProof, len(p.Signatures), len(txKeys))
+	}
+
+	hash := crypto.Keccak256(txid[:], []byte(message))
+	var spendKey *[32]byte
+	if addr.Type == address.Subaddress {
+		spendKey = &addr.SpendKey
+	}
+	good := false
+	derivations := make([][32]byte, len(txKeys))
+	for i, key := range txKeys {
+		r, a := [32]byte(key), addr.ViewKey
+		if !p.Out {
+			r, a = a, r
+		}
+		if !crypto.CheckTxProof(hash, r, a, spendKey, p.SharedSecrets[i], p.Signatures[i], p.Version) {
+			continue
+		}
+		// the shared secrets are not multiplied by the cofactor
+		derivation, err := crypto.GenerateKeyDerivation(p.SharedSecrets[i], one)
+		if err != nil {
+			return false, 0, err
+		}
+		derivations[i] = derivation
+		good = true
+	}
+	if !good {
+		return false, 0, nil
+	}
+	received, err := t.Received(addr.SpendKey, derivations[0], derivations[1:])
+	if err != nil {
+		return false, 0, err
+	}
+	return true, received, nil
+}
+
+// CheckTxProof checks a proof that the transaction req.TxID paid req.Address, like wallet-rpc's check_tx_proof
+func CheckTxProof(ctx context.Context, d daemon.Daemon, req *wallet.CheckTxProofRequest) (*wallet.CheckTxProofResponse, error) {
+	addr, err := address.Parse(req.Address)
+	if err != nil {
+		return nil, err
+	}
+	p, err := ParseTxProof(req.Signature)
+	if err != nil {
+		return nil, err
+	}
+	txid, err := parseHash(req.TxID)
+	if err != nil {
+		return nil, err
+	}
+	txs, err := tx.Fetch(ctx, d, req.TxID)
+	if err != nil {
+		return nil, err
+	}
+	t := txs[0]
+	good, received, err := p.Check(t, txid, addr, req.Message)
+	if err != nil {
+		return nil, err
+	}
+	if !good {
+		return &wallet.CheckTxProofResponse{}, nil
+	}
+	res := &wallet.CheckTxProofResponse{Good: true, InPool: t.InPool, Received: received}
+	if res.Confirmations, err = confirmations(ctx, d, t); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
@@ -0,0 +1,165 @@
+package proof
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+
+	"filippo.io/edwards25519"
+	"github.com/MarinX/monerorpc/address"
+	"github.com/MarinX/monerorpc/crypto"
+	"github.com/MarinX/monerorpc/tx"
+	"github.com/MarinX/monerorpc/wallet"
+	"github.com/matryer/is"
+)
+
+func mul(is *is.I, s *edwards25519.Scalar, k [32]byte) [32]byte {
+	p, err := crypto.ParsePoint(k)
+	is.NoErr(err)
+	return crypto.Bytes(new(edwards25519.Point).ScalarMult(s, p))
+}
+
+// extra returns an extra holding the tx public key and the additional ones
+func extra(key [32]byte, additional ...[32]byte) tx.Extra {
+	e := append(tx.Extra{tx.ExtraPublicKey}, key[:]...)
+	if len(additional) > 0 {
+		e = append(e, tx.ExtraAdditionalKeys, byte(len(additional)))
+		for _, k := range additional {
+			e = append(e, k[:]...)
+		}
+	}
+	return e
+}
+
+func TestTxProof(t *testing.T) {
+	is := is.New(t)
+	addr, err := address.Parse(stagenetAddress)
+	is.NoErr(err)
+	view, err := crypto.ParseScalar(key(viewSecret))
+	is.NoErr(err)
+	txid := crypto.Keccak256([]byte("tx"))
+	hash := crypto.Keccak256(txid[:], []byte("invoice 42"))
+
+	r, err := crypto.RandomScalar()
+	is.NoErr(err)
+	txKey := crypto.Bytes(new(edwards25519.Point).ScalarBaseMult(r))
+	derivation, err := crypto.GenerateKeyDerivation(addr.ViewKey, r)
+	is.NoErr(err)
+	payment := &tx.Transaction{Version: 2, Extra: extra(txKey), RingCT: tx.RingCT{Type: tx.RCTTypeBulletproofPlus}}
+	pay(is, payment, derivation, addr.SpendKey, 1000)
+	pay(is, payment, derivation, addr.ViewKey, 5)
+
+	// the sender proves with the tx secret key
+	shared := mul(is, r, addr.ViewKey)
+	sig, err := crypto.GenerateTxProof(hash, txKey, addr.ViewKey, nil, shared, r, 2)
+	is.NoErr(err)
+	out := &TxProof{Out: true, Version: 2, SharedSecrets: [][32]byte{shared}, Signatures: []crypto.Signature{sig}}
+	s := out.String()
+	is.True(strings.HasPrefix(s, "OutProofV2"))
+	is.Equal(len(s), len("OutProofV2")+132)
+	parsed, err := ParseTxProof(s)
+	is.NoErr(err)
+	is.Equal(parsed, out)
+
+	good, received, err := parsed.Check(payment, txid, addr, "invoice 42")
+	is.NoErr(err)
+	is.True(good)
+	is.Equal(received, uint64(1000))
+	good, _, err = parsed.Check(payment, txid, addr, "invoice 43")
+	is.NoErr(err)
+	is.True(!good)
+
+	// the recipient proves with the secret view key
+	shared = mul(is, view, txKey)
+	sig, err = crypto.GenerateTxProof(hash, addr.ViewKey, txKey, nil, shared, view, 1)
+	is.NoErr(err)
+	in := &TxProof{Version: 1, SharedSecrets: [][32]byte{shared}, Signatures: []crypto.Signature{sig}}
+	parsed, err = ParseTxProof(in.String())
+	is.NoErr(err)
+	is.True(!parsed.Out)
+	good, received, err = parsed.Check(payment, txid, addr, "invoice 42")
+	is.NoErr(err)
+	is.True(good)
+	is.Equal(received, uint64(1000))
+
+	// the proof must have a signature per tx public key
+	payment.Extra = extra(txKey, txKey)
+	_, _, err = parsed.Check(payment, txid, addr, "invoice 42")
+	is.True(errors.Is(err, ErrInvalidProof))
+	payment.Extra = nil
+	_, _, err = parsed.Check(payment, txid, addr, "invoice 42")
+	is.True(errors.Is(err, ErrInvalidProof))
+}
+
+// transactions paying a subaddress and another address have additional tx keys
+func TestTxProofSubaddress(t *testing.T) {
+	is := is.New(t)
+	addr, err := address.Parse(stagenetAddress)
+	is.NoErr(err)
+	g, err := address.NewGenerator(address.Stagenet, key(viewSecret), addr.SpendKey)
+	is.NoErr(err)
+	sub := g.Address(0, 3)
+	txid := crypto.Keccak256([]byte("tx"))
+	hash := crypto.Keccak256(txid[:])
+
+	var secrets []*edwards25519.Scalar
+	for i := 0; i < 3; i++ {
+		r, err := crypto.RandomScalar()
+		is.NoErr(err)
+		secrets = append(secrets, r)
+	}
+	txKey := crypto.Bytes(new(edwards25519.Point).ScalarBaseMult(secrets[0]))
+	// output 0 pays the subaddress, output 1 is change
+	additional := [][32]byte{mul(is, secrets[1], sub.SpendKey), crypto.Bytes(new(edwards25519.Point).ScalarBaseMult(secrets[2]))}
+	payment := &tx.Transaction{Version: 2, Extra: extra(txKey, additional...), RingCT: tx.RingCT{Type: tx.RCTTypeCLSAG}}
+	derivation, err := crypto.GenerateKeyDerivation(sub.ViewKey, secrets[1])
+	is.NoErr(err)
+	pay(is, payment, derivation, sub.SpendKey, 777)
+	derivation, err = crypto.GenerateKeyDerivation(addr.ViewKey, secrets[2])
+	is.NoErr(err)
+	pay(is, payment, derivation, addr.SpendKey, 1)
+
+	p := &TxProof{Out: true, Version: 2}
+	for i, r := range secrets {
+		shared := mul(is, r, sub.ViewKey)
+		rKey := mul(is, r, sub.SpendKey)
+		sig, err := crypto.GenerateTxProof(hash, rKey, sub.ViewKey, &sub.SpendKey, shared, r, 2)
+		is.NoErr(err)
+		if i == 2 {
+			sig.C[0] ^= 1
+		}
+		p.SharedSecrets = append(p.SharedSecrets, shared)
+		p.Signatures = append(p.Signatures, sig)
+	}
+
+	d := newFakeDaemon()
+	hexID := hex.EncodeToString(txid[:])
+	d.add(hexID, payment, 995, false)
+	req := &wallet.CheckTxProofRequest{TxID: hexID, Address: sub.String(), Signature: p.String()}
+	res, err := CheckTxProof(context.Background(), d, req)
+	is.NoErr(err)
+	is.Equal(res, &wallet.CheckTxProofResponse{Good: true, Confirmations: 5, Received: 777})
+
+	req.Message = "other"
+	res, err = CheckTxProof(context.Background(), d, req)
+	is.NoErr(err)
+	is.Equal(res, &wallet.CheckTxProofResponse{})
+}
+
+func TestParseTxProof(t *testing.T) {
+	is := is.New(t)
+	valid := (&TxProof{Version: 2, SharedSecrets: [][32]byte{{1}}, Signatures: []crypto.Signature{{}}}).String()
+	for _, s := range []string{
+		"",
+		"SpendProofV1" + valid[len("InProofV2"):],
+		"InProofV3" + valid[len("InProofV2"):],
+		"InProofV2",
+		valid[:len(valid)-1],
+		valid[:len(valid)-1] + "0",
+	} {
+		_, err := ParseTxProof(s)
+		is.True(errors.Is(err, ErrInvalidProof))
+	}
+}
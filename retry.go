@@ -0,0 +1,218 @@
+package monerorpc
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// RetryPolicy controls how failed calls are repeated.
+//
+// By default only read methods which are safe to repeat (see IsIdempotent) are retried
+// after a transport failure or a 502, 503 or 504 http status. Replies telling that the
+// daemon is busy (wallet-rpc's daemon busy code, monerod's core busy code or a BUSY status) are
+// retried for every method, since the server did not process the call.
+// Methods overrides the default per json rpc method or endpoint path: true allows retries
+// of that method, false disables them entirely.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between attempts
+	MaxBackoff time.Duration
+	// Multiplier grows the delay after every attempt
+	Multiplier float64
+	// Jitter randomizes each delay by up to this fraction in both directions, between 0 and 1
+	Jitter float64
+	// Methods overrides whether a method may be retried
+	Methods map[string]bool
+}
+
+// DefaultRetryPolicy returns a policy making up to 4 attempts with backoff from 200ms to 5s
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:    4,
+		InitialBackoff: 200 * time.Millisecond,
+		MaxBackoff:     5 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+	}
+}
+
+// idempotentMethods lists json rpc methods and endpoint paths which only read state
+var idempotentMethods = map[string]bool{
+	// monero-wallet-rpc
+	"check_reserve_proof":      true,
+	"check_spend_proof":        true,
+	"check_tx_key":             true,
+	"check_tx_proof":           true,
+	"describe_transfer":        true,
+	"export_key_images":        true,
+	"export_multisig_info":     true,
+	"export_outputs":           true,
+	"get_account_tags":         true,
+	"get_accounts":             true,
+	"get_address":              true,
+	"get_address_book":         true,
+	"get_address_index":        true,
+	"get_attribute":            true,
+	"get_balance":              true,
+	"get_bulk_payments":        true,
+	"get_height":               true,
+	"get_languages":            true,
+	"get_payments":             true,
+	"get_reserve_proof":        true,
+	"get_spend_proof":          true,
+	"get_transfer_by_txid":     true,
+	"get_transfers":            true,
+	"get_tx_key":               true,
+	"get_tx_notes":             true,
+	"get_tx_proof":             true,
+	"get_version":              true,
+	"incoming_transfers":       true,
+	"is_multisig":              true,
+	"make_integrated_address":  true,
+	"make_uri":                 true,
+	"parse_uri":                true,
+	"query_key":                true,
+	"sign":                     true,
+	"split_integrated_address": true,
+	"validate_address":         true,
+	"verify":                   true,
+	// monerod json rpc
+	"get_alternate_chains":       true,
+	"get_bans":                   true,
+	"get_block":                  true,
+	"get_block_count":            true,
+	"get_block_header_by_hash":   true,
+	"get_block_header_by_height": true,
+	"get_block_headers_range":    true,
+	"get_block_template":         true,
+	"get_coinbase_tx_sum":        true,
+	"get_connections":            true,
+	"get_fee_estimate":           true,
+	"get_info":                   true,
+	"get_last_block_header":      true,
+	"get_output_distribution":    true,
+	"get_output_histogram":       true,
+	"get_txpool_backlog":         true,
+	"hard_fork_info":             true,
+	"on_get_block_hash":          true,
+	"sync_info":                  true,
+	// monerod path based endpoints
+	"/get_alt_blocks_hashes":           true,
+	"/get_height":                      true,
+	"/get_outs":                        true,
+	"/get_transaction_pool":            true,
+	"/get_transaction_pool_hashes":     true,
+	"/get_transactions":                true,
+	"/is_key_image_spent":              true,
+	"/get_blocks.bin":                  true,
+	"/get_blocks_by_height.bin":        true,
+	"/get_hashes.bin":                  true,
+	"/get_o_indexes.bin":               true,
+	"/get_outs.bin":                    true,
+	"/get_transaction_pool_hashes.bin": true,
+}
+
+// IsIdempotent reports whether method only reads state, so repeating it has no side effects
+func IsIdempotent(method string) bool {
+	return idempotentMethods[method]
+}
+
+// errBusyResult marks a json rpc result whose status is BUSY.
+// It is only used to trigger a retry and never returned to the caller.
+var errBusyResult = errors.New("busy result")
+
+// retryable reports whether err returned by method should be retried
+func (p *RetryPolicy) retryable(method string, err error) bool {
+	allowed, ok := p.Methods[method]
+	if ok && !allowed {
+		return false
+	}
+	if errors.Is(err, errBusyResult) || errors.Is(err, ErrDaemonIsBusy) || errors.Is(err, ErrCoreBusy) {
+		return true
+	}
+	if !ok && !IsIdempotent(method) {
+		return false
+	}
+	var rpcErr *Error
+	if !errors.As(err, &rpcErr) {
+		return false
+	}
+	if rpcErr.transport {
+		return true
+	}
+	switch rpcErr.HTTPStatus {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return rpcErr.Code == 0
+	}
+	return false
+}
+
+// backoff returns the delay before the given retry, starting at 1
+func (p *RetryPolicy) backoff(retry int) time.Duration {
+	d := float64(p.InitialBackoff)
+	if p.Multiplier > 1 {
+		d *= math.Pow(p.Multiplier, float64(retry-1))
+	}
+	if p.MaxBackoff > 0 && d > float64(p.MaxBackoff) {
+		d = float64(p.MaxBackoff)
+	}
+	if p.Jitter > 0 {
+		d *= 1 + p.Jitter*(2*rand.Float64()-1)
+	}
+	return time.Duration(d)
+}
+
+// do runs call until it succeeds, fails with an error that should not be retried,
+// runs out of attempts or ctx is done
+func (p *RetryPolicy) do(ctx context.Context, method string, call func() error) error {
+	return p.doFunc(ctx, func(err error) bool {
+		return p.retryable(method, err)
+	}, call)
+}
+
+// doFunc is like do but asks retryable whether an error should be retried
+func (p *RetryPolicy) doFunc(ctx context.Context, retryable func(err error) bool, call func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := call()
+		if err == nil || p == nil || attempt >= p.MaxAttempts || ctx.Err() != nil || !retryable(err) {
+			return finalError(err)
+		}
+		timer := time.NewTimer(p.backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return finalError(err)
+		case <-timer.C:
+		}
+	}
+}
+
+// finalError returns the error handed back to the caller once retries are over.
+// A BUSY result is returned as is, like without a retry policy.
+func finalError(err error) error {
+	if errors.Is(err, errBusyResult) {
+		return nil
+	}
+	return err
+}
+
+// SetRetry sets the retry policy of both Wallet and Daemon calls, nil disables retries
+func (c *MoneroRPC) SetRetry(policy *RetryPolicy) *MoneroRPC {
+	c.wallet.retry = policy
+	c.daemon.retry = policy
+	return c
+}
+
+// WithRetry sets the retry policy of both Wallet and Daemon calls
+func WithRetry(policy *RetryPolicy) Option {
+	return func(c *config) {
+		c.retry = policy
+	}
+}
@@ -0,0 +1,189 @@
+package monerorpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/MarinX/monerorpc/daemon"
+	"github.com/matryer/is"
+)
+
+// setupFlakyServer drops the connection for the first failures calls, then replies with output
+func setupFlakyServer(t *testing.T, failures int32, output string) (*httptest.Server, *int32) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		io.ReadAll(req.Body)
+		if atomic.AddInt32(&calls, 1) <= failures {
+			conn, _, err := rw.(http.Hijacker).Hijack()
+			if err != nil {
+				t.Fatal(err)
+			}
+			conn.Close()
+			return
+		}
+		rw.Write([]byte(output))
+	}))
+	return server, &calls
+}
+
+func testRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		Multiplier:     2,
+		Jitter:         0.5,
+	}
+}
+
+func TestRetryIdempotent(t *testing.T) {
+	server, calls := setupFlakyServer(t, 2, `{"id": "0", "jsonrpc": "2.0", "result": {"count": 993163, "status": "OK"}}`)
+	defer server.Close()
+
+	client := New(server.URL, server.Client()).SetRetry(testRetryPolicy())
+	is := is.New(t)
+
+	res, err := client.Daemon.GetBlockCount()
+	is.NoErr(err)
+	is.Equal(res.Count, uint64(993163))
+	is.Equal(atomic.LoadInt32(calls), int32(3))
+}
+
+func TestRetryExhausted(t *testing.T) {
+	server, calls := setupFlakyServer(t, 5, "")
+	defer server.Close()
+
+	client := New(server.URL, server.Client()).SetRetry(testRetryPolicy())
+	is := is.New(t)
+
+	_, err := client.Daemon.GetBlockCount()
+	is.True(errors.Is(err, ErrTransport))
+	is.Equal(atomic.LoadInt32(calls), int32(3))
+}
+
+func TestRetryNotIdempotent(t *testing.T) {
+	server, calls := setupFlakyServer(t, 1, `{"id": "0", "jsonrpc": "2.0", "result": {}}`)
+	defer server.Close()
+
+	client := New(server.URL, server.Client()).SetRetry(testRetryPolicy())
+	is := is.New(t)
+
+	var res struct{}
+	err := client.Do("transfer", nil, &res)
+	is.True(errors.Is(err, ErrTransport))
+	is.Equal(atomic.LoadInt32(calls), int32(1))
+}
+
+func TestRetryMethodOverride(t *testing.T) {
+	server, calls := setupFlakyServer(t, 1, `{"id": "0", "jsonrpc": "2.0", "result": {}}`)
+	defer server.Close()
+
+	policy := testRetryPolicy()
+	policy.Methods = map[string]bool{"relay_tx": true, "get_info": false}
+	client := NewClient(WithDaemon(server.URL, server.Client()), WithRetry(policy))
+	is := is.New(t)
+
+	var res struct{}
+	is.NoErr(client.Do("relay_tx", nil, &res))
+	is.Equal(atomic.LoadInt32(calls), int32(2))
+
+	atomic.StoreInt32(calls, 0)
+	err := client.Do("get_info", nil, &res)
+	is.True(errors.Is(err, ErrTransport))
+	is.Equal(atomic.LoadInt32(calls), int32(1))
+}
+
+func TestRetryBusy(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		io.ReadAll(req.Body)
+		if atomic.AddInt32(&calls, 1) == 1 {
+			rw.Write([]byte(`{"id": "0", "jsonrpc": "2.0", "result": {"status": "BUSY"}}`))
+			return
+		}
+		rw.Write([]byte(`{"id": "0", "jsonrpc": "2.0", "result": {"status": "OK", "blocks": ["1"]}}`))
+	}))
+	defer server.Close()
+
+	client := New(server.URL, server.Client()).SetRetry(testRetryPolicy())
+	is := is.New(t)
+
+	res, err := client.Daemon.GenerateBlocks(nil)
+	is.NoErr(err)
+	is.Equal(res.Blocks, []string{"1"})
+	is.Equal(atomic.LoadInt32(&calls), int32(2))
+}
+
+func TestRetryCoreBusy(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		io.ReadAll(req.Body)
+		switch atomic.AddInt32(&calls, 1) {
+		case 1:
+			rw.Write([]byte(`{"id": "0", "jsonrpc": "2.0", "error": {"code": -9, "message": "Core is busy"}}`))
+		case 2:
+			rw.Write([]byte(`{"id": "0", "jsonrpc": "2.0", "error": {"code": -3, "message": "Too big reserved size"}}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(WithDaemon(server.URL, server.Client()), WithRetry(testRetryPolicy()))
+	is := is.New(t)
+
+	// core busy is retried, too big reserve size from monerod is not
+	_, err := client.Daemon.GetBlockTemplate(&daemon.GetBlockTemplateRequest{ReserveSize: 300})
+	is.True(errors.Is(err, ErrTooBigReserveSize))
+	is.Equal(atomic.LoadInt32(&calls), int32(2))
+}
+
+func TestRetryBusyExhausted(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		io.ReadAll(req.Body)
+		atomic.AddInt32(&calls, 1)
+		rw.Write([]byte(`{"height": 0, "status": "BUSY"}`))
+	}))
+	defer server.Close()
+
+	client := New(server.URL+"/json_rpc", server.Client()).SetRetry(testRetryPolicy())
+	is := is.New(t)
+
+	_, err := client.Daemon.GetHeight()
+	is.True(errors.Is(err, ErrDaemonIsBusy))
+	is.Equal(atomic.LoadInt32(&calls), int32(3))
+}
+
+func TestRetryContextCanceled(t *testing.T) {
+	server, calls := setupFlakyServer(t, 5, "")
+	defer server.Close()
+
+	policy := testRetryPolicy()
+	policy.InitialBackoff = time.Hour
+	client := New(server.URL, server.Client()).SetRetry(policy)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := client.Daemon.GetBlockCountContext(ctx)
+	is.New(t).True(errors.Is(err, ErrTransport))
+	is.New(t).Equal(atomic.LoadInt32(calls), int32(1))
+}
+
+func TestRetryBackoff(t *testing.T) {
+	is := is.New(t)
+
+	p := &RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second, Multiplier: 2}
+	is.Equal(p.backoff(1), 100*time.Millisecond)
+	is.Equal(p.backoff(3), 400*time.Millisecond)
+	is.Equal(p.backoff(10), time.Second)
+
+	p.Jitter = 0.2
+	for i := 0; i < 100; i++ {
+		d := p.backoff(2)
+		is.True(d >= 160*time.Millisecond && d <= 240*time.Millisecond)
+	}
+}
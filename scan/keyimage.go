@@ -0,0 +1,62 @@
+package scan
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/MarinX/monerorpc/daemon"
+	"github.com/MarinX/monerorpc/keys"
+	"github.com/MarinX/monerorpc/wallet"
+)
+
+// KeyImage is the key image of an output found by a scanner and its spent status
+type KeyImage struct {
+	Output Output
+	// Signed is the key image and its signature, as exported by wallet-rpc's export_key_images
+	Signed wallet.SignedImage
+	// Status is daemon.KeyImageUnspent, daemon.KeyImageSpentInChain or daemon.KeyImageSpentInTxpool
+	Status uint64
+}
+
+// Spent reports whether the output is spent, in the chain or in the pool
+func (k *KeyImage) Spent() bool {
+	return k.Status != daemon.KeyImageUnspent
+}
+
+// KeyImages computes the key images of outs with the private spend key of k, and looks up their spent status
+// with is_key_image_spent. The signed images can be imported by a view only wallet-rpc with import_key_images.
+func KeyImages(ctx context.Context, d daemon.Daemon, k *keys.Keys, outs []Output) ([]KeyImage, error) {
+	images := make([]KeyImage, len(outs))
+	req := &daemon.IsKeyImageSpentRequest{KeyImages: make([]string, len(outs))}
+	for i, o := range outs {
+		signed, err := k.SignedKeyImage(o.TxPublicKey, o.Index, o.Key, o.SubaddrIndex)
+		if err != nil {
+			return nil, fmt.Errorf("%s output %d: %w", o.TxHash, o.Index, err)
+		}
+		images[i] = KeyImage{Output: o, Signed: *signed}
+		req.KeyImages[i] = signed.KeyImage
+	}
+	if len(outs) == 0 {
+		return images, nil
+	}
+	res, err := d.IsKeyImageSpentContext(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if len(res.SpentStatus) != len(images) {
+		return nil, fmt.Errorf("%w: %d statuses for %d key images", ErrDaemonResponse, len(res.SpentStatus), len(images))
+	}
+	for i := range images {
+		images[i].Status = res.SpentStatus[i]
+	}
+	return images, nil
+}
+
+// ImportKeyImagesRequest builds the request importing images into a view only wallet-rpc
+func ImportKeyImagesRequest(images []KeyImage) *wallet.ImportKeyImagesRequest {
+	req := &wallet.ImportKeyImagesRequest{SignedKeyImages: make([]wallet.SignedImage, len(images))}
+	for i, image := range images {
+		req.SignedKeyImages[i] = image.Signed
+	}
+	return req
+}
@@ -0,0 +1,56 @@
+package scan
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/MarinX/monerorpc/address"
+	"github.com/MarinX/monerorpc/crypto"
+	"github.com/MarinX/monerorpc/daemon"
+	"github.com/MarinX/monerorpc/keys"
+	"github.com/matryer/is"
+)
+
+func TestKeyImages(t *testing.T) {
+	is := is.New(t)
+	d := newFakeDaemon()
+	s, g := newScanner(is, d)
+	d.mine("a")
+	d.mine("a", payment(is, false, [][32]byte{g.SpendKey(0, 0)}, []uint64{1000}))
+	d.mine("a", payment(is, true, [][32]byte{g.SpendKey(1, 3), g.SpendKey(0, 0)}, []uint64{250, 5}))
+	res, err := s.Scan(context.Background())
+	is.NoErr(err)
+	is.Equal(len(res.Outputs), 3)
+
+	k, err := keys.FromSpendKey(address.Stagenet, key(spendSecret))
+	is.NoErr(err)
+	spent, err := k.KeyImage(res.Outputs[1].TxPublicKey, res.Outputs[1].Index, res.Outputs[1].Key, res.Outputs[1].SubaddrIndex)
+	is.NoErr(err)
+	d.spent[hex.EncodeToString(spent[:])] = daemon.KeyImageSpentInTxpool
+
+	images, err := KeyImages(context.Background(), d, k, res.Outputs)
+	is.NoErr(err)
+	is.Equal(len(images), 3)
+	for i, image := range images {
+		is.Equal(image.Output, res.Outputs[i])
+		is.Equal(image.Spent(), i == 1)
+		// the signatures verify like in import_key_images
+		ki := key(image.Signed.KeyImage)
+		sig, err := hex.DecodeString(image.Signed.Signature)
+		is.NoErr(err)
+		s, ok := crypto.SignatureFromBytes(sig)
+		is.True(ok)
+		is.True(crypto.CheckRingSignature(ki, ki, [][32]byte{image.Output.Key}, []crypto.Signature{s}))
+	}
+	is.Equal(images[1].Status, uint64(daemon.KeyImageSpentInTxpool))
+	req := ImportKeyImagesRequest(images)
+	is.Equal(len(req.SignedKeyImages), 3)
+	is.Equal(req.SignedKeyImages[2], images[2].Signed)
+
+	view, err := keys.FromViewKey(address.Stagenet, k.ViewSecret, k.SpendPublic)
+	is.NoErr(err)
+	_, err = KeyImages(context.Background(), d, view, res.Outputs)
+	is.True(errors.Is(err, keys.ErrViewOnly))
+}
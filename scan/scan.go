@@ -0,0 +1,276 @@
+// Package scan finds the outputs received by a wallet with only its private view key and public spend key,
+// walking the blocks and the pool of a monerod, without monero-wallet-rpc.
+//
+// The scanner is receive only: spends are found by KeyImages, with the private spend key. The daemon is trusted:
+// use a node you run.
+package scan
+
+import (
+	"context"
+	"errors"
+
+	"filippo.io/edwards25519"
+	"github.com/MarinX/monerorpc/address"
+	"github.com/MarinX/monerorpc/crypto"
+	"github.com/MarinX/monerorpc/daemon"
+	"github.com/MarinX/monerorpc/tx"
+	"github.com/MarinX/monerorpc/wallet"
+)
+
+// ReorgDepth is the number of block hashes kept in the state to detect reorgs
+const ReorgDepth = 100
+
+var (
+	// ErrReorgTooDeep is returned when the chain forks below the block hashes kept in the state
+	ErrReorgTooDeep = errors.New("scan: reorg deeper than the scan state")
+	// ErrDaemonResponse is returned when the daemon does not answer every item of a request
+	ErrDaemonResponse = errors.New("scan: incomplete daemon response")
+)
+
+// State is where a scanner resumes, to persist between runs
+type State struct {
+	// Height is the height of the next block to scan
+	Height uint64 `json:"height"`
+	// Hashes are the hashes of the last blocks scanned, up to ReorgDepth, the last one at Height - 1
+	Hashes []string `json:"hashes"`
+}
+
+// Output is an output received by the wallet
+type Output struct {
+	TxHash string
+	// Index is the index of the output in its transaction
+	Index uint64
+	// GlobalIndex is the index of the output among the outputs of its amount, 0 in the pool
+	GlobalIndex uint64
+	// Key is the one time public key of the output
+	Key tx.Key
+	// TxPublicKey is the tx public key the output is derived from, the main one or an additional one
+	TxPublicKey  tx.Key
+	Amount       uint64
+	SubaddrIndex wallet.Index
+	// Height is the height of the block of the transaction, 0 in the pool
+	Height     uint64
+	InPool     bool
+	UnlockTime uint64
+	Coinbase   bool
+}
+
+// Result holds what a scan found
+type Result struct {
+	Outputs []Output
+	// Reorg is true when blocks scanned before left the chain: outputs at ForkHeight and above are no longer
+	// received and must be discarded
+	Reorg      bool
+	ForkHeight uint64
+}
+
+// detach drops the outputs of the blocks from height on, which left the chain
+func (r *Result) detach(height uint64) {
+	r.Reorg = true
+	r.ForkHeight = height
+	outs := r.Outputs[:0]
+	for _, o := range r.Outputs {
+		if o.Height < height {
+			outs = append(outs, o)
+		}
+	}
+	r.Outputs = outs
+}
+
+// Scanner finds the outputs paid to the subaddresses of a lookup table
+type Scanner struct {
+	// State is where the next scan starts, set it to resume a previous scan
+	State State
+
+	d     daemon.Daemon
+	view  *edwards25519.Scalar
+	table address.LookupTable
+}
+
+// New creates a scanner of the account with the private viewKey and public spendKey, finding the outputs paid to
+// the account and to the subaddresses of table, from the block at height, usually the restore height of the wallet
+func New(d daemon.Daemon, viewKey [32]byte, spendKey [32]byte, table address.LookupTable, height uint64) (*Scanner, error) {
+	view, err := crypto.ParseScalar(viewKey)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := crypto.ParsePoint(spendKey); err != nil {
+		return nil, err
+	}
+	t := make(address.LookupTable, len(table)+1)
+	for k, i := range table {
+		t[k] = i
+	}
+	t[spendKey] = wallet.Index{}
+	return &Scanner{
+		State: State{Height: height},
+		d:     d,
+		view:  view,
+		table: t,
+	}, nil
+}
+
+// Scan walks the blocks from the state to the top of the chain, returning the outputs received. Blocks that left
+// the chain are scanned again, see Result. On errors, the result holds the outputs of the blocks scanned before,
+// and the state resumes after them.
+func (s *Scanner) Scan(ctx context.Context) (*Result, error) {
+	res := &Result{}
+	info, err := s.d.GetHeightContext(ctx)
+	if err != nil {
+		return res, err
+	}
+	// without new blocks, the chain may still have been replaced by one of the same height or shorter
+	for n := len(s.State.Hashes); n > 0 && info.Height <= s.State.Height; n = len(s.State.Hashes) {
+		if s.State.Height <= info.Height {
+			h, err := s.d.GetBlockHeaderByHeightContext(ctx, &daemon.GetBlockHeaderByHeightRequest{Height: s.State.Height - 1})
+			if err != nil {
+				return res, err
+			}
+			if h.BlockHeader.Hash == s.State.Hashes[n-1] {
+				break
+			}
+		}
+		// the last block scanned left the chain
+		if n == 1 {
+			return res, ErrReorgTooDeep
+		}
+		s.State.Hashes = s.State.Hashes[:n-1]
+		s.State.Height--
+		res.detach(s.State.Height)
+	}
+	for s.State.Height < info.Height {
+		b, err := s.d.GetBlockContext(ctx, &daemon.GetBlockRequest{Height: s.State.Height})
+		if err != nil {
+			return res, err
+		}
+		if n := len(s.State.Hashes); n > 0 && b.BlockHeader.PrevHash != s.State.Hashes[n-1] {
+			// the last block scanned left the chain
+			if n == 1 {
+				return res, ErrReorgTooDeep
+			}
+			s.State.Hashes = s.State.Hashes[:n-1]
+			s.State.Height--
+			res.detach(s.State.Height)
+			continue
+		}
+
+		txs, err := tx.Fetch(ctx, s.d, append([]string{b.MinerTxHash}, b.TxHashes...)...)
+		if err != nil {
+			return res, err
+		}
+		for _, t := range txs {
+			res.Outputs = append(res.Outputs, s.Outputs(t)...)
+		}
+		s.State.Hashes = append(s.State.Hashes, b.BlockHeader.Hash)
+		if len(s.State.Hashes) > ReorgDepth {
+			s.State.Hashes = s.State.Hashes[len(s.State.Hashes)-ReorgDepth:]
+		}
+		s.State.Height++
+	}
+	return res, nil
+}
+
+// Pool returns the outputs received by the transactions of the pool
+func (s *Scanner) Pool(ctx context.Context) ([]Output, error) {
+	res, err := s.d.GetTransactionPoolContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var outs []Output
+	for _, p := range res.Transactions {
+		t, err := tx.Parse(p.TxJSON)
+		if err != nil {
+			return nil, err
+		}
+		t.Hash = p.IDHash
+		t.InPool = true
+		outs = append(outs, s.Outputs(t)...)
+	}
+	return outs, nil
+}
+
+// candidate is a tx public key and its derivation with the secret view key
+type candidate struct {
+	key        tx.Key
+	derivation [32]byte
+}
+
+func (s *Scanner) candidate(key tx.Key) (candidate, bool) {
+	d, err := crypto.GenerateKeyDerivation(key, s.view)
+	return candidate{key: key, derivation: d}, err == nil
+}
+
+// Outputs returns the outputs of t received by the scanner. Outputs whose amount does not match their commitment
+// are skipped.
+func (s *Scanner) Outputs(t *tx.Transaction) []Output {
+	var main candidate
+	hasMain := false
+	if k, ok := t.Extra.PublicKey(); ok {
+		main, hasMain = s.candidate(k)
+	}
+	// the additional keys are only used when there is one for every output
+	additional := t.Extra.AdditionalPublicKeys()
+	if len(additional) != len(t.Outputs) {
+		additional = nil
+	}
+
+	var outs []Output
+	for i := range t.Outputs {
+		var o Output
+		ok := false
+		if hasMain {
+			o, ok = s.match(t, i, main)
+		}
+		if !ok && additional != nil {
+			if c, valid := s.candidate(additional[i]); valid {
+				o, ok = s.match(t, i, c)
+			}
+		}
+		if ok {
+			outs = append(outs, o)
+		}
+	}
+	return outs
+}
+
+// match returns output i of t if it is derived from c for a subaddress of the table
+func (s *Scanner) match(t *tx.Transaction, i int, c candidate) (Output, bool) {
+	index := uint64(i)
+	key, ok := t.Outputs[i].Key()
+	if !ok {
+		return Output{}, false
+	}
+	if tag, ok := t.Outputs[i].ViewTag(); ok && crypto.ViewTag(c.derivation, index) != tag {
+		return Output{}, false
+	}
+	spendKey, err := crypto.DeriveSubaddressPublicKey(c.derivation, index, key)
+	if err != nil {
+		return Output{}, false
+	}
+	subaddr, ok := s.table.Lookup(spendKey)
+	if !ok {
+		return Output{}, false
+	}
+	amount, ok := t.Amount(i, crypto.DerivationToScalar(c.derivation, index))
+	if !ok {
+		return Output{}, false
+	}
+	o := Output{
+		TxHash:       t.Hash,
+		Index:        index,
+		Key:          key,
+		TxPublicKey:  c.key,
+		Amount:       amount,
+		SubaddrIndex: subaddr,
+		InPool:       t.InPool,
+		UnlockTime:   t.UnlockTime,
+		Coinbase:     len(t.Inputs) > 0 && t.Inputs[0].Gen != nil,
+	}
+	if !t.InPool {
+		o.Height = t.BlockHeight
+	}
+	if len(t.OutputIndices) == len(t.Outputs) {
+		o.GlobalIndex = t.OutputIndices[i]
+	}
+	return o, true
+}
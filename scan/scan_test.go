@@ -0,0 +1,311 @@
+package scan
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"filippo.io/edwards25519"
+	"github.com/MarinX/monerorpc/address"
+	"github.com/MarinX/monerorpc/crypto"
+	"github.com/MarinX/monerorpc/daemon"
+	"github.com/MarinX/monerorpc/tx"
+	"github.com/MarinX/monerorpc/wallet"
+	"github.com/matryer/is"
+)
+
+// stagenet wallet used by the tests
+const (
+	stagenetAddress = "53zEYzu2hi3e97tdMTqTvSRAfFYXwxA7LBJEHLWvFnm699WgcsE8CJujENwNAQotKyY2u94vpbGEZTiwahuMcMfX3x6NFwY"
+	spendSecret     = "372fcc2abc6bc5015103aae4763822e45c4cfe775d163f97a9ebdd77b0d12c0c"
+	viewSecret      = "8aa763d1c8d9da4ca75cb6ca22a021b5cca376c1367be8d62bcc9cdf4b926009"
+)
+
+// fakeDaemon serves a chain of blocks, each with a coinbase and the transactions added to it, a pool and the
+// status of key images
+type fakeDaemon struct {
+	daemon.Daemon
+	blocks []daemon.GetBlockResponse
+	txs    map[string]*tx.Transaction
+	pool   []*tx.Transaction
+	spent  map[string]uint64
+}
+
+func newFakeDaemon() *fakeDaemon {
+	return &fakeDaemon{txs: make(map[string]*tx.Transaction), spent: make(map[string]uint64)}
+}
+
+// mine appends a block holding txs to the chain, a coinbase paying nobody first
+func (f *fakeDaemon) mine(fork string, txs ...*tx.Transaction) {
+	height := uint64(len(f.blocks))
+	b := daemon.GetBlockResponse{BlockHeader: daemon.BlockHeader{Height: height, Hash: fmt.Sprintf("%s%d", fork, height)}}
+	if height > 0 {
+		b.BlockHeader.PrevHash = f.blocks[height-1].BlockHeader.Hash
+	}
+	coinbase := &tx.Transaction{Version: 2, Inputs: []tx.Input{{Gen: &tx.GenInput{Height: height}}}}
+	for i, t := range append([]*tx.Transaction{coinbase}, txs...) {
+		t.Hash = fmt.Sprintf("%s-%d-%d", fork, height, i)
+		t.BlockHeight = height
+		f.txs[t.Hash] = t
+		if i == 0 {
+			b.MinerTxHash = t.Hash
+		} else {
+			b.TxHashes = append(b.TxHashes, t.Hash)
+		}
+	}
+	f.blocks = append(f.blocks, b)
+}
+
+func (f *fakeDaemon) GetHeightContext(ctx context.Context) (*daemon.GetHeightResponse, error) {
+	return &daemon.GetHeightResponse{Height: uint64(len(f.blocks))}, nil
+}
+
+func (f *fakeDaemon) GetBlockContext(ctx context.Context, req *daemon.GetBlockRequest) (*daemon.GetBlockResponse, error) {
+	if req.Height >= uint64(len(f.blocks)) {
+		return nil, errors.New("block not found")
+	}
+	b := f.blocks[req.Height]
+	return &b, nil
+}
+
+func (f *fakeDaemon) GetBlockHeaderByHeightContext(ctx context.Context, req *daemon.GetBlockHeaderByHeightRequest) (*daemon.GetBlockHeaderByHeightResponse, error) {
+	b, err := f.GetBlockContext(ctx, &daemon.GetBlockRequest{Height: req.Height})
+	if err != nil {
+		return nil, err
+	}
+	return &daemon.GetBlockHeaderByHeightResponse{BlockHeader: b.BlockHeader}, nil
+}
+
+func (f *fakeDaemon) GetTransactionsContext(ctx context.Context, req *daemon.GetTransactionsRequest) (*daemon.GetTransactionsResponse, error) {
+	if len(req.TxsHashes) > tx.MaxFetch {
+		return nil, errors.New("too many transactions requested")
+	}
+	res := new(daemon.GetTransactionsResponse)
+	for _, h := range req.TxsHashes {
+		t, ok := f.txs[h]
+		if !ok {
+			res.MissedTx = append(res.MissedTx, h)
+			continue
+		}
+		b, err := json.Marshal(t)
+		if err != nil {
+			return nil, err
+		}
+		indices := make([]uint64, len(t.Outputs))
+		for i := range indices {
+			indices[i] = 1000*t.BlockHeight + uint64(i)
+		}
+		res.Txs = append(res.Txs, daemon.Transaction{AsJSON: string(b), TxHash: h, BlockHeight: t.BlockHeight, OutputIndices: indices})
+	}
+	return res, nil
+}
+
+func (f *fakeDaemon) GetTransactionPoolContext(ctx context.Context) (*daemon.GetTransactionPoolResponse, error) {
+	res := new(daemon.GetTransactionPoolResponse)
+	for i, t := range f.pool {
+		b, err := json.Marshal(t)
+		if err != nil {
+			return nil, err
+		}
+		res.Transactions = append(res.Transactions, daemon.PoolTransaction{IDHash: fmt.Sprintf("pool-%d", i), TxJSON: string(b)})
+	}
+	return res, nil
+}
+
+func (f *fakeDaemon) IsKeyImageSpentContext(ctx context.Context, req *daemon.IsKeyImageSpentRequest) (*daemon.IsKeyImageSpentResponse, error) {
+	res := new(daemon.IsKeyImageSpentResponse)
+	for _, k := range req.KeyImages {
+		res.SpentStatus = append(res.SpentStatus, f.spent[k])
+	}
+	return res, nil
+}
+
+func key(s string) [32]byte {
+	var k [32]byte
+	hex.Decode(k[:], []byte(s))
+	return k
+}
+
+// payment returns a transaction paying amounts to the addresses of spendKeys of the stagenet wallet, with an
+// additional tx key for every output when subaddress is set
+func payment(is *is.I, subaddress bool, spendKeys [][32]byte, amounts []uint64) *tx.Transaction {
+	view, err := crypto.ParseScalar(key(viewSecret))
+	is.NoErr(err)
+	r, err := crypto.RandomScalar()
+	is.NoErr(err)
+	txKey := crypto.Bytes(new(edwards25519.Point).ScalarBaseMult(r))
+	t := &tx.Transaction{Version: 2, Extra: append(tx.Extra{tx.ExtraPublicKey}, txKey[:]...), RingCT: tx.RingCT{Type: tx.RCTTypeBulletproofPlus}}
+	var additional []byte
+	for i, spendKey := range spendKeys {
+		shared := txKey
+		if subaddress {
+			p, err := crypto.ParsePoint(spendKey)
+			is.NoErr(err)
+			shared = crypto.Bytes(new(edwards25519.Point).ScalarMult(r, p))
+			additional = append(additional, shared[:]...)
+		}
+		derivation, err := crypto.GenerateKeyDerivation(shared, view)
+		is.NoErr(err)
+
+		index := uint64(i)
+		out, err := crypto.DerivePublicKey(derivation, index, spendKey)
+		is.NoErr(err)
+		secret := crypto.DerivationToScalar(derivation, index).Bytes()
+		pad := crypto.Keccak256([]byte("amount"), secret)
+		enc := make([]byte, 8)
+		binary.LittleEndian.PutUint64(enc, amounts[i])
+		for j := range enc {
+			enc[j] ^= pad[j]
+		}
+		mask := crypto.HashToScalar([]byte("commitment_mask"), secret)
+		t.Outputs = append(t.Outputs, tx.Output{Target: tx.Target{TaggedKey: &tx.TaggedKey{Key: out, ViewTag: tx.Hex{crypto.ViewTag(derivation, index)}}}})
+		t.RingCT.EcdhInfo = append(t.RingCT.EcdhInfo, tx.EcdhInfo{Amount: enc})
+		t.RingCT.OutPk = append(t.RingCT.OutPk, crypto.Commit(mask, amounts[i]))
+	}
+	if subaddress {
+		t.Extra = append(append(t.Extra, tx.ExtraAdditionalKeys, byte(len(spendKeys))), additional...)
+	}
+	return t
+}
+
+func newScanner(is *is.I, d daemon.Daemon) (*Scanner, *address.Generator) {
+	addr, err := address.Parse(stagenetAddress)
+	is.NoErr(err)
+	g, err := address.NewGenerator(address.Stagenet, key(viewSecret), addr.SpendKey)
+	is.NoErr(err)
+	s, err := New(d, key(viewSecret), addr.SpendKey, g.LookupTable(2, 5), 1)
+	is.NoErr(err)
+	return s, g
+}
+
+func TestScan(t *testing.T) {
+	is := is.New(t)
+	d := newFakeDaemon()
+	s, g := newScanner(is, d)
+	other := g.SpendKey(5, 5) // outside the lookup table
+	primary := g.SpendKey(0, 0)
+
+	d.mine("a", payment(is, false, [][32]byte{primary}, []uint64{1}))                              // before the restore height
+	d.mine("a", payment(is, false, [][32]byte{other, primary}, []uint64{7, 1000}))                 // change to the primary address
+	d.mine("a", payment(is, true, [][32]byte{g.SpendKey(1, 3), other}, []uint64{250, 7}))          // subaddress
+	d.mine("a", payment(is, true, [][32]byte{g.SpendKey(0, 2), g.SpendKey(0, 0)}, []uint64{5, 6})) // both
+	res, err := s.Scan(context.Background())
+	is.NoErr(err)
+	is.True(!res.Reorg)
+	is.Equal(len(res.Outputs), 4)
+	txKey, _ := d.txs["a-1-1"].Extra.PublicKey()
+	is.Equal(res.Outputs[0], Output{
+		TxHash:      "a-1-1",
+		Index:       1,
+		GlobalIndex: 1001,
+		Key:         d.txs["a-1-1"].Outputs[1].Target.TaggedKey.Key,
+		TxPublicKey: txKey,
+		Amount:      1000,
+		Height:      1,
+	})
+	is.Equal(res.Outputs[1].SubaddrIndex, wallet.Index{Major: 1, Minor: 3})
+	is.Equal(res.Outputs[1].Amount, uint64(250))
+	is.Equal(res.Outputs[2].SubaddrIndex, wallet.Index{Minor: 2})
+	is.Equal(res.Outputs[3].SubaddrIndex, wallet.Index{})
+	is.Equal(res.Outputs[3].Amount, uint64(6))
+	is.Equal(s.State, State{Height: 4, Hashes: []string{"a1", "a2", "a3"}})
+
+	// resuming finds the new blocks only
+	d.mine("a")
+	res, err = s.Scan(context.Background())
+	is.NoErr(err)
+	is.Equal(len(res.Outputs), 0)
+	is.Equal(s.State.Height, uint64(5))
+
+	d.pool = append(d.pool, payment(is, false, [][32]byte{primary}, []uint64{42}))
+	outs, err := s.Pool(context.Background())
+	is.NoErr(err)
+	is.Equal(len(outs), 1)
+	is.Equal(outs[0].TxHash, "pool-0")
+	is.True(outs[0].InPool)
+	is.Equal(outs[0].Amount, uint64(42))
+}
+
+func TestScanReorg(t *testing.T) {
+	is := is.New(t)
+	d := newFakeDaemon()
+	s, g := newScanner(is, d)
+	primary := g.SpendKey(0, 0)
+	d.mine("a")
+	d.mine("a", payment(is, false, [][32]byte{primary}, []uint64{1}))
+	d.mine("a", payment(is, false, [][32]byte{primary}, []uint64{2}))
+	d.mine("a", payment(is, false, [][32]byte{primary}, []uint64{3}))
+	res, err := s.Scan(context.Background())
+	is.NoErr(err)
+	is.Equal(len(res.Outputs), 3)
+
+	// blocks 2 and 3 are replaced by a longer fork
+	d.blocks = d.blocks[:2]
+	d.mine("b", payment(is, false, [][32]byte{primary}, []uint64{20}))
+	d.mine("b")
+	d.mine("b", payment(is, false, [][32]byte{primary}, []uint64{40}))
+	res, err = s.Scan(context.Background())
+	is.NoErr(err)
+	is.True(res.Reorg)
+	is.Equal(res.ForkHeight, uint64(2))
+	is.Equal(len(res.Outputs), 2)
+	is.Equal(res.Outputs[0].Amount, uint64(20))
+	is.Equal(res.Outputs[1].Amount, uint64(40))
+	is.Equal(s.State, State{Height: 5, Hashes: []string{"a1", "b2", "b3", "b4"}})
+
+	// a fork below the hashes of the state, found without new blocks
+	s.State = State{Height: 5, Hashes: []string{"c4"}}
+	_, err = s.Scan(context.Background())
+	is.Equal(err, ErrReorgTooDeep)
+}
+
+func TestScanReorgShorterChain(t *testing.T) {
+	is := is.New(t)
+	d := newFakeDaemon()
+	s, g := newScanner(is, d)
+	primary := g.SpendKey(0, 0)
+	d.mine("a")
+	d.mine("a", payment(is, false, [][32]byte{primary}, []uint64{1}))
+	d.mine("a", payment(is, false, [][32]byte{primary}, []uint64{2}))
+	d.mine("a", payment(is, false, [][32]byte{primary}, []uint64{3}))
+	res, err := s.Scan(context.Background())
+	is.NoErr(err)
+	is.Equal(len(res.Outputs), 3)
+
+	// blocks 2 and 3 are replaced by a single block
+	d.blocks = d.blocks[:2]
+	d.mine("b", payment(is, false, [][32]byte{primary}, []uint64{20}))
+	res, err = s.Scan(context.Background())
+	is.NoErr(err)
+	is.True(res.Reorg)
+	is.Equal(res.ForkHeight, uint64(2))
+	is.Equal(len(res.Outputs), 1)
+	is.Equal(res.Outputs[0].Amount, uint64(20))
+	is.Equal(s.State, State{Height: 3, Hashes: []string{"a1", "b2"}})
+
+	// the chain is unchanged
+	res, err = s.Scan(context.Background())
+	is.NoErr(err)
+	is.True(!res.Reorg)
+}
+
+func TestScanBusyBlock(t *testing.T) {
+	is := is.New(t)
+	d := newFakeDaemon()
+	s, g := newScanner(is, d)
+	primary := g.SpendKey(0, 0)
+	d.mine("a")
+	txs := make([]*tx.Transaction, 2*tx.MaxFetch)
+	for i := range txs {
+		txs[i] = payment(is, false, [][32]byte{primary}, []uint64{uint64(i)})
+	}
+	d.mine("a", txs...)
+	res, err := s.Scan(context.Background())
+	is.NoErr(err)
+	is.Equal(len(res.Outputs), len(txs))
+	is.Equal(res.Outputs[len(txs)-1].Amount, uint64(len(txs)-1))
+}
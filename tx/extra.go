@@ -0,0 +1,97 @@
+package tx
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// Tags of the fields of the extra
+const (
+	ExtraPadding             = 0x00
+	ExtraPublicKey           = 0x01
+	ExtraNonce               = 0x02
+	ExtraMergeMining         = 0x03
+	ExtraAdditionalKeys      = 0x04
+	ExtraMysteriousMinergate = 0xde
+)
+
+// Field is a field of the extra
+type Field struct {
+	Tag byte
+	// Data is the content of the field, without its tag and size
+	Data []byte
+}
+
+// Fields parses the fields of the extra. On error, the fields read until then are returned,
+// like wallets do for extras that are partially invalid.
+func (e Extra) Fields() ([]Field, error) {
+	var fields []Field
+	for b := []byte(e); len(b) > 0; {
+		tag := b[0]
+		b = b[1:]
+		var size uint64
+		switch tag {
+		case ExtraPadding:
+			// the padding fills the rest of the extra with zeros
+			for _, c := range b {
+				if c != 0 {
+					return fields, fmt.Errorf("%w: bad padding", ErrInvalidTransaction)
+				}
+			}
+			size = uint64(len(b))
+		case ExtraPublicKey:
+			size = 32
+		case ExtraAdditionalKeys:
+			count, n := binary.Uvarint(b)
+			if n <= 0 || count > uint64(len(b))/32 {
+				return fields, fmt.Errorf("%w: bad additional keys", ErrInvalidTransaction)
+			}
+			b = b[n:]
+			size = count * 32
+		case ExtraNonce, ExtraMergeMining, ExtraMysteriousMinergate:
+			var n int
+			size, n = binary.Uvarint(b)
+			if n <= 0 {
+				return fields, fmt.Errorf("%w: bad extra field %#x", ErrInvalidTransaction, tag)
+			}
+			b = b[n:]
+		default:
+			return fields, fmt.Errorf("%w: unknown extra field %#x", ErrInvalidTransaction, tag)
+		}
+		if size > uint64(len(b)) {
+			return fields, fmt.Errorf("%w: short extra field %#x", ErrInvalidTransaction, tag)
+		}
+		fields = append(fields, Field{Tag: tag, Data: b[:size]})
+		b = b[size:]
+	}
+	return fields, nil
+}
+
+// PublicKey returns the tx public key, false if there is none (get_tx_pub_key_from_extra)
+func (e Extra) PublicKey() (Key, bool) {
+	fields, _ := e.Fields()
+	for _, f := range fields {
+		if f.Tag == ExtraPublicKey {
+			var k Key
+			copy(k[:], f.Data)
+			return k, true
+		}
+	}
+	return Key{}, false
+}
+
+// AdditionalPublicKeys returns the additional tx public keys of transactions paying subaddresses, one per output
+// (get_additional_tx_pub_keys_from_extra)
+func (e Extra) AdditionalPublicKeys() []Key {
+	fields, _ := e.Fields()
+	for _, f := range fields {
+		if f.Tag == ExtraAdditionalKeys {
+			keys := make([]Key, len(f.Data)/32)
+			for i := range keys {
+				copy(keys[i][:], f.Data[i*32:])
+			}
+			return keys
+		}
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package tx
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestExtra(t *testing.T) {
+	is := is.New(t)
+	b, _ := hex.DecodeString("01c4865e47b9392d52e6f4957d0a6f6a9feda0ef0ac4807e1127bea56cb3ba583e020901a88dcbe6025e76ce")
+	extra := Extra(b)
+	fields, err := extra.Fields()
+	is.NoErr(err)
+	is.Equal(len(fields), 2)
+	is.Equal(fields[1].Tag, byte(ExtraNonce))
+	is.Equal(hex.EncodeToString(fields[1].Data), "01a88dcbe6025e76ce")
+	pub, ok := extra.PublicKey()
+	is.True(ok)
+	is.Equal(pub.String(), "c4865e47b9392d52e6f4957d0a6f6a9feda0ef0ac4807e1127bea56cb3ba583e")
+	is.Equal(len(extra.AdditionalPublicKeys()), 0)
+
+	tx, err := Parse(asJSON)
+	is.NoErr(err)
+	pub, ok = tx.Extra.PublicKey()
+	is.True(ok)
+	is.Equal(pub.String(), "65358d0b0b3b288e7aa6cf0334ea67dc4438bb0299f3a7d7d7b596f4bd149b3d")
+}
+
+func TestExtraAdditionalKeys(t *testing.T) {
+	is := is.New(t)
+	extra := Extra{ExtraAdditionalKeys, 2}
+	for i := 0; i < 64; i++ {
+		extra = append(extra, byte(i))
+	}
+	extra = append(extra, ExtraPadding, 0, 0)
+	keys := extra.AdditionalPublicKeys()
+	is.Equal(len(keys), 2)
+	is.Equal(keys[1][0], byte(32))
+	_, ok := extra.PublicKey()
+	is.True(!ok)
+
+	// fields before an invalid one are kept
+	extra = append(Extra{ExtraPublicKey}, make([]byte, 32)...)
+	extra = append(extra, 0x7f, 1)
+	fields, err := extra.Fields()
+	is.True(errors.Is(err, ErrInvalidTransaction))
+	is.Equal(len(fields), 1)
+	_, ok = extra.PublicKey()
+	is.True(ok)
+
+	_, err = Extra{ExtraAdditionalKeys, 2, 1}.Fields()
+	is.True(errors.Is(err, ErrInvalidTransaction))
+}
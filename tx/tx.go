@@ -0,0 +1,324 @@
+// Package tx decodes transactions fetched from monerod, to work with their outputs offline.
+//
+// Transactions are read from the JSON of get_transactions with decode_as_json set. The daemon is trusted:
+// transactions are not checked against their hash.
+package tx
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"filippo.io/edwards25519"
+	"github.com/MarinX/monerorpc/crypto"
+	"github.com/MarinX/monerorpc/daemon"
+)
+
+// RingCT types of rct_signatures
+const (
+	RCTTypeNull = iota
+	RCTTypeFull
+	RCTTypeSimple
+	RCTTypeBulletproof
+	RCTTypeBulletproof2
+	RCTTypeCLSAG
+	RCTTypeBulletproofPlus
+)
+
+var (
+	// ErrNotFound is returned by Fetch for transactions the daemon does not know
+	ErrNotFound = errors.New("tx: transaction not found")
+	// ErrInvalidTransaction is returned for transactions that cannot be decoded
+	ErrInvalidTransaction = errors.New("tx: invalid transaction")
+	// ErrAdditionalKeys is returned when the number of additional derivations does not match the outputs
+	ErrAdditionalKeys = errors.New("tx: additional keys do not match the outputs")
+)
+
+// Key is a 32 byte key, hex encoded in JSON
+type Key [32]byte
+
+// String returns the hex encoding of the key
+func (k Key) String() string {
+	return hex.EncodeToString(k[:])
+}
+
+// MarshalJSON encodes the key as a hex string
+func (k Key) MarshalJSON() ([]byte, error) {
+	return json.Marshal(k.String())
+}
+
+// UnmarshalJSON decodes a hex string of 32 bytes
+func (k *Key) UnmarshalJSON(data []byte) error {
+	var b Hex
+	if err := b.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	if len(b) != len(k) {
+		return fmt.Errorf("%w: key of %d bytes", ErrInvalidTransaction, len(b))
+	}
+	copy(k[:], b)
+	return nil
+}
+
+// Hex is a byte string, hex encoded in JSON
+type Hex []byte
+
+// MarshalJSON encodes the bytes as a hex string
+func (h Hex) MarshalJSON() ([]byte, error) {
+	return json.Marshal(hex.EncodeToString(h))
+}
+
+// UnmarshalJSON decodes a hex string
+func (h *Hex) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidTransaction, err)
+	}
+	*h = b
+	return nil
+}
+
+// Extra is the extra field of a transaction, a JSON array of bytes
+type Extra []byte
+
+// MarshalJSON encodes the bytes as an array of numbers
+func (e Extra) MarshalJSON() ([]byte, error) {
+	values := make([]uint16, len(e))
+	for i, b := range e {
+		values[i] = uint16(b)
+	}
+	return json.Marshal(values)
+}
+
+// UnmarshalJSON decodes an array of numbers. A []byte would be decoded from base64.
+func (e *Extra) UnmarshalJSON(data []byte) error {
+	var values []uint16
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+	b := make([]byte, len(values))
+	for i, v := range values {
+		if v > 0xff {
+			return fmt.Errorf("%w: extra byte %d", ErrInvalidTransaction, v)
+		}
+		b[i] = byte(v)
+	}
+	*e = b
+	return nil
+}
+
+// Transaction is a transaction decoded from the JSON of get_transactions
+type Transaction struct {
+	// Hash, BlockHeight, InPool and OutputIndices are filled by Fetch from the daemon's entry
+	Hash          string   `json:"-"`
+	BlockHeight   uint64   `json:"-"`
+	InPool        bool     `json:"-"`
+	OutputIndices []uint64 `json:"-"`
+
+	Version    uint64   `json:"version"`
+	UnlockTime uint64   `json:"unlock_time"`
+	Inputs     []Input  `json:"vin"`
+	Outputs    []Output `json:"vout"`
+	Extra      Extra    `json:"extra"`
+	RingCT     RingCT   `json:"rct_signatures"`
+}
+
+// Input is a transaction input, either a coinbase or the spend of a key
+type Input struct {
+	Gen *GenInput `json:"gen,omitempty"`
+	Key *KeyInput `json:"key,omitempty"`
+}
+
+// GenInput is the input of a coinbase transaction
+type GenInput struct {
+	Height uint64 `json:"height"`
+}
+
+// KeyInput spends one of the outputs of its ring
+type KeyInput struct {
+	Amount uint64 `json:"amount"`
+	// KeyOffsets are the global indices of the ring members, each relative to the previous one
+	KeyOffsets []uint64 `json:"key_offsets"`
+	KeyImage   Key      `json:"k_image"`
+}
+
+// AbsoluteOffsets returns the global indices of the ring members (relative_output_offsets_to_absolute)
+func (in *KeyInput) AbsoluteOffsets() []uint64 {
+	offsets := make([]uint64, len(in.KeyOffsets))
+	var sum uint64
+	for i, o := range in.KeyOffsets {
+		sum += o
+		offsets[i] = sum
+	}
+	return offsets
+}
+
+// Output is a transaction output
+type Output struct {
+	// Amount is 0 for RingCT outputs, whose amount is encrypted
+	Amount uint64 `json:"amount"`
+	Target Target `json:"target"`
+}
+
+// Target is the destination of an output, a key or, since view tags, a tagged key
+type Target struct {
+	Key       *Key       `json:"key,omitempty"`
+	TaggedKey *TaggedKey `json:"tagged_key,omitempty"`
+}
+
+// TaggedKey is an output key with its view tag
+type TaggedKey struct {
+	Key     Key `json:"key"`
+	ViewTag Hex `json:"view_tag"`
+}
+
+// Key returns the one time key of the output, false for unknown targets
+func (o *Output) Key() (Key, bool) {
+	switch {
+	case o.Target.Key != nil:
+		return *o.Target.Key, true
+	case o.Target.TaggedKey != nil:
+		return o.Target.TaggedKey.Key, true
+	}
+	return Key{}, false
+}
+
+// ViewTag returns the view tag of the output, false if it has none
+func (o *Output) ViewTag() (byte, bool) {
+	if o.Target.TaggedKey == nil || len(o.Target.TaggedKey.ViewTag) != 1 {
+		return 0, false
+	}
+	return o.Target.TaggedKey.ViewTag[0], true
+}
+
+// RingCT is the base of the RingCT signature of a transaction
+type RingCT struct {
+	Type     int        `json:"type"`
+	Fee      uint64     `json:"txnFee"`
+	EcdhInfo []EcdhInfo `json:"ecdhInfo,omitempty"`
+	OutPk    []Key      `json:"outPk,omitempty"`
+}
+
+// EcdhInfo holds the encrypted amount of an output, and its mask before Bulletproof2 transactions
+type EcdhInfo struct {
+	Mask   Key `json:"mask"`
+	Amount Hex `json:"amount"`
+}
+
+// Parse decodes the JSON of a transaction
+func Parse(asJSON string) (*Transaction, error) {
+	t := new(Transaction)
+	if err := json.Unmarshal([]byte(asJSON), t); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTransaction, err)
+	}
+	if t.RingCT.Type != RCTTypeNull && (len(t.RingCT.EcdhInfo) != len(t.Outputs) || len(t.RingCT.OutPk) != len(t.Outputs)) {
+		return nil, fmt.Errorf("%w: %d outputs with %d amounts", ErrInvalidTransaction, len(t.Outputs), len(t.RingCT.EcdhInfo))
+	}
+	return t, nil
+}
+
+// MaxFetch is the number of hashes sent in one get_transactions request, the most restricted nodes accept
+const MaxFetch = 100
+
+// Fetch looks up transactions by hash with get_transactions and decodes them, in the order of hashes.
+// The hashes are sent in requests of at most MaxFetch.
+func Fetch(ctx context.Context, d daemon.Daemon, hashes ...string) ([]*Transaction, error) {
+	txs := make([]*Transaction, 0, len(hashes))
+	for len(hashes) > 0 {
+		n := len(hashes)
+		if n > MaxFetch {
+			n = MaxFetch
+		}
+		chunk, err := fetch(ctx, d, hashes[:n])
+		if err != nil {
+			return nil, err
+		}
+		txs = append(txs, chunk...)
+		hashes = hashes[n:]
+	}
+	return txs, nil
+}
+
+// fetch makes a single get_transactions request
+func fetch(ctx context.Context, d daemon.Daemon, hashes []string) ([]*Transaction, error) {
+	res, err := d.GetTransactionsContext(ctx, &daemon.GetTransactionsRequest{
+		TxsHashes:    hashes,
+		DecodeAsJSON: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(res.MissedTx) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, strings.Join(res.MissedTx, ", "))
+	}
+	if len(res.Txs) != len(hashes) {
+		return nil, fmt.Errorf("%w: got %d transactions for %d hashes", ErrNotFound, len(res.Txs), len(hashes))
+	}
+	txs := make([]*Transaction, len(res.Txs))
+	for i, entry := range res.Txs {
+		t, err := Parse(entry.AsJSON)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", entry.TxHash, err)
+		}
+		t.Hash = entry.TxHash
+		t.BlockHeight = entry.BlockHeight
+		t.InPool = entry.InPool
+		t.OutputIndices = entry.OutputIndices
+		txs[i] = t
+	}
+	return txs, nil
+}
+
+// Amount returns the amount of output i. The amounts of RingCT outputs are decrypted with the output
+// secret, DerivationToScalar of the output, and false is returned if they do not match the output commitment.
+func (t *Transaction) Amount(i int, secret *edwards25519.Scalar) (uint64, bool) {
+	if t.Version == 1 || t.RingCT.Type == RCTTypeNull {
+		return t.Outputs[i].Amount, true
+	}
+	ecdh := t.RingCT.EcdhInfo[i]
+	amount, mask, err := crypto.DecodeAmount(secret, ecdh.Mask, ecdh.Amount, t.RingCT.Type >= RCTTypeBulletproof2)
+	if err != nil || crypto.Commit(mask, amount) != t.RingCT.OutPk[i] {
+		return 0, false
+	}
+	return amount, true
+}
+
+// Received returns the amount t pays to the address of spendKey, given the derivation of the tx public key
+// and the derivations of the additional tx public keys, if any (check_tx_key_helper)
+func (t *Transaction) Received(spendKey [32]byte, derivation [32]byte, additional [][32]byte) (uint64, error) {
+	if len(additional) > 0 && len(additional) != len(t.Outputs) {
+		return 0, ErrAdditionalKeys
+	}
+	var received uint64
+	for i := range t.Outputs {
+		key, ok := t.Outputs[i].Key()
+		if !ok {
+			continue
+		}
+		found := derivation
+		derived, err := crypto.DerivePublicKey(found, uint64(i), spendKey)
+		if err != nil {
+			return 0, err
+		}
+		if derived != key && len(additional) > 0 {
+			found = additional[i]
+			if derived, err = crypto.DerivePublicKey(found, uint64(i), spendKey); err != nil {
+				return 0, err
+			}
+		}
+		if derived != key {
+			continue
+		}
+		// outputs whose amount does not match their commitment count as 0, like in wallet2
+		amount, _ := t.Amount(i, crypto.DerivationToScalar(found, uint64(i)))
+		received += amount
+	}
+	return received, nil
+}
@@ -0,0 +1,167 @@
+package tx
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"filippo.io/edwards25519"
+	"github.com/MarinX/monerorpc/crypto"
+	"github.com/MarinX/monerorpc/daemon"
+	"github.com/matryer/is"
+)
+
+// mainnet transaction 584a77486518a5e3918b307cf317d8ae7999a390f92a4a04ca6c0221b11eec07, with shortened rings
+// and without the prunable part
+const asJSON = `{"version": 2, "unlock_time": 0, "vin": [{"key": {"amount": 0, "key_offsets": [8848476, 249608, 26194], "k_image": "bdf5c15ee53aceb59f4564de717c6f1033e4dfe7f92b91aeb4bf2c73940e1c3b"}}, {"key": {"amount": 0, "key_offsets": [8969535, 121699, 8764], "k_image": "22d10168de47d2f8309122dbf4b889577401e93c8d051f8ecf567b473d498f67"}}], "vout": [{"amount": 0, "target": {"tagged_key": {"key": "522a88fa1389aaaf8a5e6b4284fe822b036472a9e52ebaff828cb7ade14a7207", "view_tag": "ef"}}}, {"amount": 0, "target": {"tagged_key": {"key": "3d902a2b9fa79322a9891ab38a943f9307ae36fdf54551aa483e68c4f566d0e5", "view_tag": "76"}}}], "extra": [1, 101, 53, 141, 11, 11, 59, 40, 142, 122, 166, 207, 3, 52, 234, 103, 220, 68, 56, 187, 2, 153, 243, 167, 215, 215, 181, 150, 244, 189, 20, 155, 61, 2, 9, 1, 152, 98, 50, 176, 133, 157, 208, 233], "rct_signatures": {"type": 6, "txnFee": 123430000, "ecdhInfo": [{"amount": "16ba5c2f912b588a"}, {"amount": "04dce3a9b3f720c2"}], "outPk": ["67bcf08ea28184fc20e47833b8025713fd75d2b66a53bd99d3f817277b1398d1", "535fa95fbbe961bfb5a17da2e820c34374314b1756596e645d9d39c08c1d329a"]}}`
+
+const txHash = "584a77486518a5e3918b307cf317d8ae7999a390f92a4a04ca6c0221b11eec07"
+
+func TestParse(t *testing.T) {
+	is := is.New(t)
+	tx, err := Parse(asJSON)
+	is.NoErr(err)
+	is.Equal(tx.Version, uint64(2))
+	is.Equal(len(tx.Inputs), 2)
+	is.Equal(tx.Inputs[0].Key.KeyOffsets, []uint64{8848476, 249608, 26194})
+	is.Equal(tx.Inputs[0].Key.AbsoluteOffsets(), []uint64{8848476, 9098084, 9124278})
+	is.Equal(tx.Inputs[1].Key.KeyImage.String(), "22d10168de47d2f8309122dbf4b889577401e93c8d051f8ecf567b473d498f67")
+	is.Equal(len(tx.Outputs), 2)
+	key, ok := tx.Outputs[1].Key()
+	is.True(ok)
+	is.Equal(key.String(), "3d902a2b9fa79322a9891ab38a943f9307ae36fdf54551aa483e68c4f566d0e5")
+	tag, ok := tx.Outputs[0].ViewTag()
+	is.True(ok)
+	is.Equal(tag, byte(0xef))
+	is.Equal(len(tx.Extra), 44)
+	is.Equal(tx.Extra[0], byte(1))
+	is.Equal(tx.RingCT.Type, RCTTypeBulletproofPlus)
+	is.Equal(tx.RingCT.Fee, uint64(123430000))
+	is.Equal(hex.EncodeToString(tx.RingCT.EcdhInfo[1].Amount), "04dce3a9b3f720c2")
+
+	// encoding keeps the fields
+	b, err := json.Marshal(tx)
+	is.NoErr(err)
+	again, err := Parse(string(b))
+	is.NoErr(err)
+	is.Equal(again, tx)
+
+	_, err = Parse(`{"version": 2, "vout": [{"amount": 0, "target": {"key": "00"}}]}`)
+	is.True(errors.Is(err, ErrInvalidTransaction))
+	_, err = Parse(`{"version": 2, "vout": [{"amount": 0, "target": {"key": "` + key.String() + `"}}], "rct_signatures": {"type": 6}}`)
+	is.True(errors.Is(err, ErrInvalidTransaction))
+}
+
+type fakeDaemon struct {
+	daemon.Daemon
+	txs map[string]string
+}
+
+func (f *fakeDaemon) GetTransactionsContext(ctx context.Context, req *daemon.GetTransactionsRequest) (*daemon.GetTransactionsResponse, error) {
+	res := new(daemon.GetTransactionsResponse)
+	for _, h := range req.TxsHashes {
+		if s, ok := f.txs[h]; ok {
+			res.Txs = append(res.Txs, daemon.Transaction{AsJSON: s, TxHash: h, BlockHeight: 1619111, OutputIndices: []uint64{9186542, 9186543}})
+		} else {
+			res.MissedTx = append(res.MissedTx, h)
+		}
+	}
+	return res, nil
+}
+
+func TestFetch(t *testing.T) {
+	is := is.New(t)
+	d := &fakeDaemon{txs: map[string]string{txHash: asJSON}}
+	txs, err := Fetch(context.Background(), d, txHash)
+	is.NoErr(err)
+	is.Equal(len(txs), 1)
+	is.Equal(txs[0].Hash, txHash)
+	is.Equal(txs[0].BlockHeight, uint64(1619111))
+	is.Equal(txs[0].OutputIndices, []uint64{9186542, 9186543})
+	is.True(!txs[0].InPool)
+
+	_, err = Fetch(context.Background(), d, txHash, "00")
+	is.True(errors.Is(err, ErrNotFound))
+}
+
+func key(s string) [32]byte {
+	var k [32]byte
+	hex.Decode(k[:], []byte(s))
+	return k
+}
+
+// pay appends an output of amount to the keys to tx, with the compact amount encoding
+func pay(tx *Transaction, derivation [32]byte, spendKey [32]byte, amount uint64) error {
+	index := uint64(len(tx.Outputs))
+	out, err := crypto.DerivePublicKey(derivation, index, spendKey)
+	if err != nil {
+		return err
+	}
+	secret := crypto.DerivationToScalar(derivation, index).Bytes()
+	pad := crypto.Keccak256([]byte("amount"), secret)
+	enc := make([]byte, 8)
+	binary.LittleEndian.PutUint64(enc, amount)
+	for i := range enc {
+		enc[i] ^= pad[i]
+	}
+	mask := crypto.HashToScalar([]byte("commitment_mask"), secret)
+
+	tx.Outputs = append(tx.Outputs, Output{Target: Target{TaggedKey: &TaggedKey{Key: out, ViewTag: Hex{crypto.ViewTag(derivation, index)}}}})
+	tx.RingCT.EcdhInfo = append(tx.RingCT.EcdhInfo, EcdhInfo{Amount: enc})
+	tx.RingCT.OutPk = append(tx.RingCT.OutPk, crypto.Commit(mask, amount))
+	return nil
+}
+
+func TestReceived(t *testing.T) {
+	is := is.New(t)
+	// stagenet 53zEYzu2hi3e97tdMTqTvSRAfFYXwxA7LBJEHLWvFnm699WgcsE8CJujENwNAQotKyY2u94vpbGEZTiwahuMcMfX3x6NFwY
+	spendKey := key("38e9908d33d034de0ba1281aa7afe3907b795cea14852b3d8fe276e8931cb130")
+	viewKey := key("b4cdbf52851002fc7b098b99536df8b9885aa6cb8db24e9fc46103674dc9421a")
+
+	r, err := crypto.RandomScalar()
+	is.NoErr(err)
+	derivation, err := crypto.GenerateKeyDerivation(viewKey, r)
+	is.NoErr(err)
+	other, err := crypto.RandomScalar()
+	is.NoErr(err)
+	change := crypto.Bytes(new(edwards25519.Point).ScalarBaseMult(other))
+
+	tx := &Transaction{Version: 2, RingCT: RingCT{Type: RCTTypeBulletproofPlus}}
+	is.NoErr(pay(tx, derivation, spendKey, 550000000000))
+	is.NoErr(pay(tx, derivation, change, 1))
+	is.NoErr(pay(tx, derivation, spendKey, 25))
+
+	received, err := tx.Received(spendKey, derivation, nil)
+	is.NoErr(err)
+	is.Equal(received, uint64(550000000025))
+
+	amount, ok := tx.Amount(0, crypto.DerivationToScalar(derivation, 0))
+	is.True(ok)
+	is.Equal(amount, uint64(550000000000))
+	// an amount that does not match its commitment counts as 0
+	tx.RingCT.EcdhInfo[2].Amount[0] ^= 1
+	_, ok = tx.Amount(2, crypto.DerivationToScalar(derivation, 2))
+	is.True(!ok)
+	received, err = tx.Received(spendKey, derivation, nil)
+	is.NoErr(err)
+	is.Equal(received, uint64(550000000000))
+
+	_, err = tx.Received(spendKey, derivation, [][32]byte{derivation})
+	is.Equal(err, ErrAdditionalKeys)
+
+	// outputs to subaddresses use additional derivations
+	received, err = tx.Received(spendKey, change, [][32]byte{derivation, derivation, derivation})
+	is.NoErr(err)
+	is.Equal(received, uint64(550000000000))
+
+	// amounts of coinbase and version 1 transactions are in clear
+	tx = &Transaction{Version: 1}
+	is.NoErr(pay(tx, derivation, spendKey, 0))
+	tx.Outputs[0].Amount = 7
+	received, err = tx.Received(spendKey, derivation, nil)
+	is.NoErr(err)
+	is.Equal(received, uint64(7))
+}
@@ -0,0 +1,264 @@
+// Package uri builds and parses monero: payment URIs offline, in the format of wallet-rpc's make_uri and parse_uri.
+//
+// A URI names one or more recipients and optional parameters:
+//
+//	monero:<address>?tx_payment_id=<id>&tx_amount=<xmr>&recipient_name=<name>&tx_description=<text>
+//
+// Several recipients are separated by ";" in the address, tx_amount and recipient_name fields,
+// which then hold one entry per address:
+//
+//	monero:<address1>;<address2>?tx_amount=1.5;0.25&recipient_name=Alice;Bob
+package uri
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/MarinX/monerorpc"
+	"github.com/MarinX/monerorpc/address"
+	"github.com/MarinX/monerorpc/wallet"
+)
+
+// Scheme is the URI scheme of Monero payment requests
+const Scheme = "monero"
+
+// URI parameters
+const (
+	ParamPaymentID     = "tx_payment_id"
+	ParamAmount        = "tx_amount"
+	ParamRecipientName = "recipient_name"
+	ParamDescription   = "tx_description"
+)
+
+const recipientSeparator = ";"
+
+var (
+	// ErrInvalidURI is returned for URIs that are not monero: payment URIs
+	ErrInvalidURI = errors.New("uri: invalid uri")
+	// ErrInvalidPaymentID is returned for payment IDs that are not 64 hex characters
+	ErrInvalidPaymentID = errors.New("uri: invalid payment id")
+	// ErrPaymentIDWithIntegrated is returned when a payment ID is given together with an integrated address
+	ErrPaymentIDWithIntegrated = errors.New("uri: separate payment id given with an integrated address")
+	// ErrMultipleRecipients is returned by ParseURI for URIs with several recipients, which the wallet model cannot hold
+	ErrMultipleRecipients = errors.New("uri: uri has multiple recipients")
+)
+
+// Recipient is an address paid by a payment request
+type Recipient struct {
+	Address string
+	// Amount to pay, 0 if not given
+	Amount monerorpc.Amount
+	// Name of the recipient, empty if not given
+	Name string
+}
+
+// Payment is the content of a payment URI
+type Payment struct {
+	// Recipients holds at least one recipient
+	Recipients []Recipient
+	// PaymentID is a 64 character hex payment id, empty if not given
+	PaymentID string
+	// Description of the reason for the tx, empty if not given
+	Description string
+	// Unknown holds parameters that are not part of the scheme, they are kept when encoding
+	Unknown map[string]string
+}
+
+// Validate checks that every address is valid, that all addresses are on the same network
+// and that the payment id is well formed and not combined with an integrated address
+func (p *Payment) Validate() error {
+	if len(p.Recipients) == 0 {
+		return fmt.Errorf("%w: missing address", ErrInvalidURI)
+	}
+	var network address.Network
+	integrated := false
+	for i, r := range p.Recipients {
+		a, err := address.Parse(r.Address)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidURI, err)
+		}
+		if i > 0 && a.Network != network {
+			return fmt.Errorf("%w: addresses of different networks", ErrInvalidURI)
+		}
+		network = a.Network
+		integrated = integrated || a.Type == address.Integrated
+	}
+	if p.PaymentID == "" {
+		return nil
+	}
+	if integrated {
+		return ErrPaymentIDWithIntegrated
+	}
+	// like wallet2, only long payment ids are accepted, short ones belong in integrated addresses
+	if len(p.PaymentID) != 64 {
+		return fmt.Errorf("%w %q", ErrInvalidPaymentID, p.PaymentID)
+	}
+	if _, err := hex.DecodeString(p.PaymentID); err != nil {
+		return fmt.Errorf("%w %q", ErrInvalidPaymentID, p.PaymentID)
+	}
+	return nil
+}
+
+// String validates and encodes the payment as a URI, with parameters in the order wallet-rpc writes them
+func (p *Payment) String() (string, error) {
+	if err := p.Validate(); err != nil {
+		return "", err
+	}
+	var addresses, amounts, names []string
+	hasAmount, hasName := false, false
+	for _, r := range p.Recipients {
+		addresses = append(addresses, r.Address)
+		amounts = append(amounts, monerorpc.XMRToDecimal(uint64(r.Amount)))
+		names = append(names, escape(r.Name))
+		hasAmount = hasAmount || r.Amount > 0
+		hasName = hasName || r.Name != ""
+	}
+
+	var params []string
+	if p.PaymentID != "" {
+		params = append(params, ParamPaymentID+"="+p.PaymentID)
+	}
+	if hasAmount {
+		params = append(params, ParamAmount+"="+strings.Join(amounts, recipientSeparator))
+	}
+	if hasName {
+		params = append(params, ParamRecipientName+"="+strings.Join(names, recipientSeparator))
+	}
+	if p.Description != "" {
+		params = append(params, ParamDescription+"="+escape(p.Description))
+	}
+	for _, k := range sortedKeys(p.Unknown) {
+		params = append(params, escape(k)+"="+escape(p.Unknown[k]))
+	}
+
+	s := Scheme + ":" + strings.Join(addresses, recipientSeparator)
+	if len(params) > 0 {
+		s += "?" + strings.Join(params, "&")
+	}
+	return s, nil
+}
+
+// Parse decodes and validates a payment URI. Parameters may appear once each.
+func Parse(s string) (*Payment, error) {
+	rest, ok := cutPrefixFold(s, Scheme+":")
+	if !ok {
+		return nil, fmt.Errorf("%w: missing %s: scheme", ErrInvalidURI, Scheme)
+	}
+	addresses, query, _ := strings.Cut(rest, "?")
+	if addresses == "" {
+		return nil, fmt.Errorf("%w: missing address", ErrInvalidURI)
+	}
+
+	p := &Payment{}
+	for _, a := range strings.Split(addresses, recipientSeparator) {
+		p.Recipients = append(p.Recipients, Recipient{Address: a})
+	}
+
+	seen := make(map[string]bool)
+	for _, param := range strings.Split(query, "&") {
+		if query == "" {
+			break
+		}
+		key, value, ok := strings.Cut(param, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("%w: bad parameter %q", ErrInvalidURI, param)
+		}
+		key, err := unescape(key)
+		if err != nil {
+			return nil, err
+		}
+		if seen[key] {
+			return nil, fmt.Errorf("%w: duplicate parameter %s", ErrInvalidURI, key)
+		}
+		seen[key] = true
+
+		switch key {
+		case ParamPaymentID:
+			p.PaymentID = value
+		case ParamAmount:
+			err = p.eachRecipient(key, value, func(r *Recipient, v string) error {
+				amount, err := monerorpc.ParseAmount(v)
+				if err != nil {
+					return fmt.Errorf("%w: bad %s: %v", ErrInvalidURI, key, err)
+				}
+				r.Amount = amount
+				return nil
+			})
+		case ParamRecipientName:
+			err = p.eachRecipient(key, value, func(r *Recipient, v string) error {
+				r.Name, err = unescape(v)
+				return err
+			})
+		case ParamDescription:
+			p.Description, err = unescape(value)
+		default:
+			if p.Unknown == nil {
+				p.Unknown = make(map[string]string)
+			}
+			p.Unknown[key], err = unescape(value)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// eachRecipient splits a per recipient parameter and calls fn with every recipient and its value
+func (p *Payment) eachRecipient(key, value string, fn func(r *Recipient, v string) error) error {
+	values := strings.Split(value, recipientSeparator)
+	if len(values) != len(p.Recipients) {
+		return fmt.Errorf("%w: %s has %d values for %d addresses", ErrInvalidURI, key, len(values), len(p.Recipients))
+	}
+	for i, v := range values {
+		if err := fn(&p.Recipients[i], v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func cutPrefixFold(s, prefix string) (string, bool) {
+	if len(s) < len(prefix) || !strings.EqualFold(s[:len(prefix)], prefix) {
+		return s, false
+	}
+	return s[len(prefix):], true
+}
+
+// MakeURI builds a URI like wallet-rpc's make_uri
+func MakeURI(req *wallet.MakeURIRequest) (*wallet.MakeURIResponse, error) {
+	p := &Payment{
+		Recipients:  []Recipient{{Address: req.Address, Amount: monerorpc.Amount(req.Amount), Name: req.RecipientName}},
+		PaymentID:   req.PaymentID,
+		Description: req.TxDescription,
+	}
+	s, err := p.String()
+	if err != nil {
+		return nil, err
+	}
+	return &wallet.MakeURIResponse{URI: s}, nil
+}
+
+// ParseURI parses a URI with a single recipient like wallet-rpc's parse_uri
+func ParseURI(req *wallet.ParseURIRequest) (*wallet.ParseURIResponse, error) {
+	p, err := Parse(req.URI)
+	if err != nil {
+		return nil, err
+	}
+	if len(p.Recipients) != 1 {
+		return nil, ErrMultipleRecipients
+	}
+	r := p.Recipients[0]
+	return &wallet.ParseURIResponse{URI: wallet.URI{
+		Address:       r.Address,
+		Amount:        uint64(r.Amount),
+		PaymentID:     p.PaymentID,
+		RecipientName: r.Name,
+		TxDescription: p.Description,
+	}}, nil
+}
@@ -0,0 +1,103 @@
+package uri
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/MarinX/monerorpc"
+	"github.com/MarinX/monerorpc/wallet"
+	"github.com/matryer/is"
+)
+
+const (
+	stagenet   = "55LTR8KniP4LQGJSPtbYDacR7dz8RBFnsfAKMaMuwUNYX6aQbBcovzDPyrQF9KXF9tVU6Xk3K8no1BywnJX6GvZX8yJsXvt"
+	mainnet    = "48ukkZtBSBRL8iva7k3p2sBVMLWTfNwsTbW1aVh5M84g21muDCssvCHTpoZCaSc6rq8M9QLZ3sQMrMn1bq2RD2anGnyHhtq"
+	mainnetSub = "84nvgV2eTnG1vAKbg87MnbfjWrSY3eH3s2eykmggk549C8zdNk4PPD7iv7BPfPsnoH9NjXaRhjC19FY6PBmXZUtoG5SEiY7"
+	integrated = "4JcRmNhg3SwL8iva7k3p2sBVMLWTfNwsTbW1aVh5M84g21muDCssvCHTpoZCaSc6rq8M9QLZ3sQMrMn1bq2RD2anQMXGeH3St98D3GPzmn"
+)
+
+// paymentID is a long payment id, the only kind wallet-rpc accepts in URIs
+const paymentID = "420fa29b2d9a49f5420fa29b2d9a49f5420fa29b2d9a49f5420fa29b2d9a49f5"
+
+// rpcURI is the make_uri example of the wallet-rpc documentation, with a long payment id
+const rpcURI = "monero:" + stagenet + "?tx_payment_id=" + paymentID + "&tx_amount=0.000000000010&recipient_name=el00ruobuob%20Stagenet%20wallet&tx_description=Testing%20out%20the%20make_uri%20function."
+
+func TestMakeURI(t *testing.T) {
+	is := is.New(t)
+	res, err := MakeURI(&wallet.MakeURIRequest{
+		Address:       stagenet,
+		Amount:        10,
+		PaymentID:     paymentID,
+		RecipientName: "el00ruobuob Stagenet wallet",
+		TxDescription: "Testing out the make_uri function.",
+	})
+	is.NoErr(err)
+	is.Equal(res.URI, rpcURI)
+
+	res, err = MakeURI(&wallet.MakeURIRequest{Address: mainnet})
+	is.NoErr(err)
+	is.Equal(res.URI, "monero:"+mainnet)
+}
+
+func TestParseURI(t *testing.T) {
+	is := is.New(t)
+	res, err := ParseURI(&wallet.ParseURIRequest{URI: rpcURI})
+	is.NoErr(err)
+	is.Equal(res.URI, wallet.URI{
+		Address:       stagenet,
+		Amount:        10,
+		PaymentID:     paymentID,
+		RecipientName: "el00ruobuob Stagenet wallet",
+		TxDescription: "Testing out the make_uri function.",
+	})
+
+	_, err = ParseURI(&wallet.ParseURIRequest{URI: "monero:" + mainnet + ";" + mainnetSub})
+	is.Equal(err, ErrMultipleRecipients)
+}
+
+func TestRoundTrip(t *testing.T) {
+	is := is.New(t)
+	p := &Payment{
+		Recipients: []Recipient{
+			{Address: mainnet, Amount: monerorpc.MustParseAmount("1.5"), Name: "Alice; & Co"},
+			{Address: mainnetSub, Name: "Bob=100%"},
+		},
+		Description: "order #42?",
+		Unknown:     map[string]string{"x_ref": "a b"},
+	}
+	s, err := p.String()
+	is.NoErr(err)
+	is.Equal(s, "monero:"+mainnet+";"+mainnetSub+
+		"?tx_amount=1.500000000000;0.000000000000&recipient_name=Alice%3B%20%26%20Co;Bob%3D100%25&tx_description=order%20%2342%3F&x_ref=a%20b")
+
+	parsed, err := Parse(s)
+	is.NoErr(err)
+	is.Equal(parsed, p)
+}
+
+func TestParseInvalid(t *testing.T) {
+	is := is.New(t)
+	tests := []struct {
+		uri string
+		err error
+	}{
+		{"bitcoin:" + mainnet, ErrInvalidURI},
+		{"monero:", ErrInvalidURI},
+		{"monero:" + mainnet[1:], ErrInvalidURI},
+		{"monero:" + mainnet + ";" + stagenet, ErrInvalidURI},
+		{"monero:" + mainnet + "?tx_amount=abc", ErrInvalidURI},
+		{"monero:" + mainnet + "?tx_amount=0.0000000000001", ErrInvalidURI},
+		{"monero:" + mainnet + "?tx_amount=1&tx_amount=2", ErrInvalidURI},
+		{"monero:" + mainnet + ";" + mainnetSub + "?tx_amount=1", ErrInvalidURI},
+		{"monero:" + mainnet + "?tx_description", ErrInvalidURI},
+		{"monero:" + mainnet + "?tx_description=%zz", ErrInvalidURI},
+		{"monero:" + mainnet + "?tx_payment_id=1234", ErrInvalidPaymentID},
+		{"monero:" + mainnet + "?tx_payment_id=420fa29b2d9a49f5", ErrInvalidPaymentID},
+		{"monero:" + mainnet + "?tx_payment_id=zz" + paymentID[2:], ErrInvalidPaymentID},
+		{"monero:" + integrated + "?tx_payment_id=" + paymentID, ErrPaymentIDWithIntegrated},
+	}
+	for _, tt := range tests {
+		_, err := Parse(tt.uri)
+		is.True(errors.Is(err, tt.err)) // tt.uri
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"fmt"
-	"strconv"
 )
 
 // NewPaymentID64 generates a 64 bit payment ID
@@ -35,15 +34,14 @@ func XMRToFloat64(xmr uint64) float64 {
 }
 
 // Float64ToXMR converts a float64 to a raw atomic XMR
+//
+// Deprecated: float64 cannot represent most XMR amounts exactly, use ParseAmount.
 func Float64ToXMR(xmr float64) uint64 {
 	return uint64(xmr * 1e12)
 }
 
-// StringToXMR converts a string to a raw atomic XMR
+// StringToXMR converts a string to a raw atomic XMR, without rounding (see ParseAmount)
 func StringToXMR(xmr string) (uint64, error) {
-	f, err := strconv.ParseFloat(xmr, 64)
-	if err != nil {
-		return 0, err
-	}
-	return uint64(f * 1e12), nil
+	a, err := ParseAmount(xmr)
+	return uint64(a), err
 }
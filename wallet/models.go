@@ -832,6 +832,10 @@ type CheckReserveProofRequest struct {
 type CheckReserveProofResponse struct {
 	// States if the inputs proves the reserve.
 	Good bool `json:"good"`
+	// Amount of the outputs proved, in atomic units.
+	Total uint64 `json:"total"`
+	// Amount of the outputs proved that are spent, in atomic units.
+	Spent uint64 `json:"spent"`
 }
 
 // GetTransfersRequest represents the request model for GetTransfers
@@ -975,6 +979,12 @@ type DescribeTransferResponse struct {
 type SignRequest struct {
 	// Anything you need to sign.
 	Data string `json:"data"`
+	// (Optional) Account of the address to sign with (Defaults to 0).
+	AccountIndex uint64 `json:"account_index,omitempty"`
+	// (Optional) Subaddress of the address to sign with (Defaults to 0).
+	AddressIndex uint64 `json:"address_index,omitempty"`
+	// (Optional) "spend" or "view", the key to sign with (Defaults to "spend").
+	SignatureType string `json:"signature_type,omitempty"`
 }
 
 // SignResponse represents the response model for Sign
@@ -997,6 +1007,12 @@ type VerifyRequest struct {
 type VerifyResponse struct {
 	// True if signature is valid.
 	Good bool `json:"good"`
+	// Signature version, 1 or 2.
+	Version uint64 `json:"version"`
+	// True if the signature uses the old, insecure format.
+	Old bool `json:"old"`
+	// "spend" or "view", the key the data was signed with.
+	SignatureType string `json:"signature_type"`
 }
 
 // ExportOutputsRequest represents the request model for ExportOutputs
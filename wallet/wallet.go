@@ -1,170 +1,253 @@
 package wallet
 
+import "context"
+
 // Wallet interface is a list of the monero-wallet-rpc calls, their inputs and outputs, and examples of each.
+// Every call has a Context variant which takes a context.Context used to cancel the request or bound it with a deadline.
 type Wallet interface {
 	// SetDaemon connects the RPC server to a Monero daemon.
 	SetDaemon(req *SetDaemonRequest) error
+	SetDaemonContext(ctx context.Context, req *SetDaemonRequest) error
 	// GetBalance Return the wallet's balance.
 	GetBalance(req *GetBalanceRequest) (*GetBalanceResponse, error)
+	GetBalanceContext(ctx context.Context, req *GetBalanceRequest) (*GetBalanceResponse, error)
 	// GetAddress Return the wallet's addresses for an account. Optionally filter for specific set of subaddresses.
 	GetAddress(req *GetAddressRequest) (*GetAddressResponse, error)
+	GetAddressContext(ctx context.Context, req *GetAddressRequest) (*GetAddressResponse, error)
 	// GetAddressIndex Get account and address indexes from a specific (sub)address
 	GetAddressIndex(req *GetAddressIndexRequest) (*GetAddressIndexResponse, error)
+	GetAddressIndexContext(ctx context.Context, req *GetAddressIndexRequest) (*GetAddressIndexResponse, error)
 	// CreateAddress Create a new address for an account. Optionally, label the new address.
 	CreateAddress(req *CreateAddressRequest) (*CreateAddressResponse, error)
+	CreateAddressContext(ctx context.Context, req *CreateAddressRequest) (*CreateAddressResponse, error)
 	// LabelAddress Label an address.
 	LabelAddress(req *LabelAddressRequest) error
+	LabelAddressContext(ctx context.Context, req *LabelAddressRequest) error
 	// ValidateAddress Analyzes a string to determine whether it is a valid monero wallet address and returns the result and the address specifications.
 	ValidateAddress(req *ValidateAddressRequest) (*ValidateAddressResponse, error)
+	ValidateAddressContext(ctx context.Context, req *ValidateAddressRequest) (*ValidateAddressResponse, error)
 	// GetAccount Get all accounts for a wallet. Optionally filter accounts by tag.
 	GetAccounts(req *GetAccountsRequest) (*GetAccountsResponse, error)
+	GetAccountsContext(ctx context.Context, req *GetAccountsRequest) (*GetAccountsResponse, error)
 	// CreateAccount Create a new account with an optional label.
 	CreateAccount(req *CreateAccountRequest) (*CreateAccountResponse, error)
+	CreateAccountContext(ctx context.Context, req *CreateAccountRequest) (*CreateAccountResponse, error)
 	// LabelAccount Label an account.
 	LabelAccount(req *LabelAccountRequest) error
+	LabelAccountContext(ctx context.Context, req *LabelAccountRequest) error
 	// GetAccountTags Get a list of user-defined account tags.
 	GetAccountTags() (*GetAccountTagsResponse, error)
+	GetAccountTagsContext(ctx context.Context) (*GetAccountTagsResponse, error)
 	// TagAccounts Apply a filtering tag to a list of accounts.
 	TagAccounts(req *TagAccountsRequest) error
+	TagAccountsContext(ctx context.Context, req *TagAccountsRequest) error
 	// UntagAccount Remove filtering tag from a list of accounts.
 	UntagAccounts(req *UntagAccountsRequest) error
+	UntagAccountsContext(ctx context.Context, req *UntagAccountsRequest) error
 	// SetAccountTagDescription Set description for an account tag.
 	SetAccountTagDescription(req *SetAccountTagDescriptionRequest) error
+	SetAccountTagDescriptionContext(ctx context.Context, req *SetAccountTagDescriptionRequest) error
 	// GetHeight Returns the wallet's current block height.
 	GetHeight() (*GetHeightResponse, error)
+	GetHeightContext(ctx context.Context) (*GetHeightResponse, error)
 	// Transfer Send monero to a number of recipients.
 	Transfer(req *TransferRequest) (*TransferResponse, error)
+	TransferContext(ctx context.Context, req *TransferRequest) (*TransferResponse, error)
 	// TransferSplit Same as transfer, but can split into more than one tx if necessary.
 	TransferSplit(req *TransferSplitRequest) (*TransferSplitResponse, error)
+	TransferSplitContext(ctx context.Context, req *TransferSplitRequest) (*TransferSplitResponse, error)
 	// SignTransfer Sign a transaction created on a read-only wallet (in cold-signing process)
 	SignTransfer(req *SignTransferRequest) (*SignTransferResponse, error)
+	SignTransferContext(ctx context.Context, req *SignTransferRequest) (*SignTransferResponse, error)
 	// SubmitTransfer Submit a previously signed transaction on a read-only wallet (in cold-signing process)
 	SubmitTransfer(req *SubmitTransferRequest) (*SubmitTransferResponse, error)
+	SubmitTransferContext(ctx context.Context, req *SubmitTransferRequest) (*SubmitTransferResponse, error)
 	// SweepDust Send all dust outputs back to the wallet's, to make them easier to spend (and mix).
 	SweepDust(req *SweepDustRequest) (*SweepDustResponse, error)
+	SweepDustContext(ctx context.Context, req *SweepDustRequest) (*SweepDustResponse, error)
 	// SweepAll Send all unlocked balance to an address.
 	SweepAll(req *SweepAllRequest) (*SweepAllResponse, error)
+	SweepAllContext(ctx context.Context, req *SweepAllRequest) (*SweepAllResponse, error)
 	// SweepSingle Send all of a specific unlocked output to an address.
 	SweepSingle(req *SweepSingleRequest) (*SweepSingleResponse, error)
+	SweepSingleContext(ctx context.Context, req *SweepSingleRequest) (*SweepSingleResponse, error)
 	// RelaxTx Relay a transaction previously created with "do_not_relay":true.
 	RelayTx(req *RelayTxRequest) (*RelayTxResponse, error)
+	RelayTxContext(ctx context.Context, req *RelayTxRequest) (*RelayTxResponse, error)
 	// Store Save the wallet file.
 	Store() error
+	StoreContext(ctx context.Context) error
 	// GetPayments Get a list of incoming payments using a given payment id.
 	GetPayments(req *GetPaymentsRequest) (*GetPaymentsResponse, error)
+	GetPaymentsContext(ctx context.Context, req *GetPaymentsRequest) (*GetPaymentsResponse, error)
 	// GetBulkPayments Get a list of incoming payments using a given payment id, or a list of payments ids, from a given height.
 	// This method is the preferred method over get_payments because it has the same functionality but is more extendable.
 	// Either is fine for looking up transactions by a single payment ID.
 	GetBulkPayments(req *GetBulkPaymentsRequest) (*GetBulkPaymentsResponse, error)
+	GetBulkPaymentsContext(ctx context.Context, req *GetBulkPaymentsRequest) (*GetBulkPaymentsResponse, error)
 	// IncomingTransfers Return a list of incoming transfers to the wallet.
 	IncomingTransfers(req *IncomingTransfersRequest) (*IncomingTransfersResponse, error)
+	IncomingTransfersContext(ctx context.Context, req *IncomingTransfersRequest) (*IncomingTransfersResponse, error)
 	// QueryKey Return the spend or view private key.
 	QueryKey(req *QueryKeyRequest) (*QueryKeyResponse, error)
+	QueryKeyContext(ctx context.Context, req *QueryKeyRequest) (*QueryKeyResponse, error)
 	// MakeIntegratedAddress Make an integrated address from the wallet address and a payment id.
 	MakeIntegratedAddress(req *MakeIntegratedAddressRequest) (*MakeIntegratedAddressResponse, error)
+	MakeIntegratedAddressContext(ctx context.Context, req *MakeIntegratedAddressRequest) (*MakeIntegratedAddressResponse, error)
 	// SplitIntegratedAddress Retrieve the standard address and payment id corresponding to an integrated address.
 	SplitIntegratedAddress(req *SplitIntegratedAddressRequest) (*SplitIntegratedAddressResponse, error)
+	SplitIntegratedAddressContext(ctx context.Context, req *SplitIntegratedAddressRequest) (*SplitIntegratedAddressResponse, error)
 	// StopWallet Stops the wallet, storing the current state.
 	StopWallet() error
+	StopWalletContext(ctx context.Context) error
 	// RescanBlockchain Rescan the blockchain from scratch, losing any information which can not be recovered from the blockchain itself.
 	// This includes destination addresses, tx secret keys, tx notes, etc.
 	RescanBlockchain() error
+	RescanBlockchainContext(ctx context.Context) error
 	// SetTxNotes Set arbitrary string notes for transactions.
 	SetTxNotes(req *SetTxNotesRequest) error
+	SetTxNotesContext(ctx context.Context, req *SetTxNotesRequest) error
 	// GetTxNotes Get string notes for transactions.
 	GetTxNotes(req *GetTxNotesRequest) (*GetTxNotesResponse, error)
+	GetTxNotesContext(ctx context.Context, req *GetTxNotesRequest) (*GetTxNotesResponse, error)
 	// SetAttribute Set arbitrary attribute.
 	SetAttribute(req *SetAttributeRequest) error
+	SetAttributeContext(ctx context.Context, req *SetAttributeRequest) error
 	// GetAttribute Get attribute value by name.
 	GetAttribute(req *GetAttributeRequest) (*GetAttributeResponse, error)
+	GetAttributeContext(ctx context.Context, req *GetAttributeRequest) (*GetAttributeResponse, error)
 	// GetTxKey Get transaction secret key from transaction id.
 	GetTxKey(req *GetTxKeyRequest) (*GetTxKeyResponse, error)
+	GetTxKeyContext(ctx context.Context, req *GetTxKeyRequest) (*GetTxKeyResponse, error)
 	// CheckTxKey Check a transaction in the blockchain with its secret key.
 	CheckTxKey(req *CheckTxKeyRequest) (*CheckTxKeyResponse, error)
+	CheckTxKeyContext(ctx context.Context, req *CheckTxKeyRequest) (*CheckTxKeyResponse, error)
 	// GetTxProof Get transaction signature to prove it.
 	GetTxProof(req *GetTxProofRequest) (*GetTxProofResponse, error)
+	GetTxProofContext(ctx context.Context, req *GetTxProofRequest) (*GetTxProofResponse, error)
 	// CheckTxProof Prove a transaction by checking its signature.
 	CheckTxProof(req *CheckTxProofRequest) (*CheckTxProofResponse, error)
+	CheckTxProofContext(ctx context.Context, req *CheckTxProofRequest) (*CheckTxProofResponse, error)
 	// GetSpendProof Generate a signature to prove a spend. Unlike proving a transaction, it does not requires the destination public address.
 	GetSpendProof(req *GetSpendProofRequest) (*GetSpendProofResponse, error)
+	GetSpendProofContext(ctx context.Context, req *GetSpendProofRequest) (*GetSpendProofResponse, error)
 	// CheckSpendProof Prove a spend using a signature. Unlike proving a transaction, it does not requires the destination public address.
 	CheckSpendProof(req *CheckSpendProofRequest) (*CheckSpendProofResponse, error)
+	CheckSpendProofContext(ctx context.Context, req *CheckSpendProofRequest) (*CheckSpendProofResponse, error)
 	// GetReserveProof Generate a signature to prove of an available amount in a wallet.
 	GetReserveProof(req *GetReserveProofRequest) (*GetReserveProofResponse, error)
+	GetReserveProofContext(ctx context.Context, req *GetReserveProofRequest) (*GetReserveProofResponse, error)
 	// CheckReserveProof Proves a wallet has a disposable reserve using a signature.
 	CheckReserveProof(req *CheckReserveProofRequest) (*CheckReserveProofResponse, error)
+	CheckReserveProofContext(ctx context.Context, req *CheckReserveProofRequest) (*CheckReserveProofResponse, error)
 	// GetTransfers Returns a list of transfers.
 	GetTransfers(req *GetTransfersRequest) (*GetTransfersResponse, error)
+	GetTransfersContext(ctx context.Context, req *GetTransfersRequest) (*GetTransfersResponse, error)
 	// GetTransferByTxid Show information about a transfer to/from this address.
 	GetTransferByTxid(req *GetTransferByTxidRequest) (*GetTransferByTxidResponse, error)
+	GetTransferByTxidContext(ctx context.Context, req *GetTransferByTxidRequest) (*GetTransferByTxidResponse, error)
 	// DescribeTransfer Returns details for each transaction in an unsigned or multisig transaction set.
 	DescribeTransfer(req *DescribeTransferRequest) (*DescribeTransferResponse, error)
+	DescribeTransferContext(ctx context.Context, req *DescribeTransferRequest) (*DescribeTransferResponse, error)
 	// Sign a string.
 	Sign(req *SignRequest) (*SignResponse, error)
+	SignContext(ctx context.Context, req *SignRequest) (*SignResponse, error)
 	// Verify a signature on a string.
 	Verify(req *VerifyRequest) (*VerifyResponse, error)
+	VerifyContext(ctx context.Context, req *VerifyRequest) (*VerifyResponse, error)
 	// ExportOutputs Export all outputs in hex format.
 	ExportOutputs(req *ExportOutputsRequest) (*ExportOutputsResponse, error)
+	ExportOutputsContext(ctx context.Context, req *ExportOutputsRequest) (*ExportOutputsResponse, error)
 	// ImportOutputs Import outputs in hex format.
 	ImportOutputs(req *ImportOutputsRequest) (*ImportOutputsResponse, error)
+	ImportOutputsContext(ctx context.Context, req *ImportOutputsRequest) (*ImportOutputsResponse, error)
 	// ExportKeyImages Export a signed set of key images.
 	ExportKeyImages(req *ExportKeyImagesRequest) (*ExportKeyImagesResponse, error)
+	ExportKeyImagesContext(ctx context.Context, req *ExportKeyImagesRequest) (*ExportKeyImagesResponse, error)
 	// ImportKeyImages Import signed key images list and verify their spent status.
 	ImportKeyImages(req *ImportKeyImagesRequest) (*ImportKeyImagesResponse, error)
+	ImportKeyImagesContext(ctx context.Context, req *ImportKeyImagesRequest) (*ImportKeyImagesResponse, error)
 	// MakeURI Create a payment URI using the official URI spec.
 	MakeURI(req *MakeURIRequest) (*MakeURIResponse, error)
+	MakeURIContext(ctx context.Context, req *MakeURIRequest) (*MakeURIResponse, error)
 	// ParseURI Parse a payment URI to get payment information.
 	ParseURI(req *ParseURIRequest) (*ParseURIResponse, error)
+	ParseURIContext(ctx context.Context, req *ParseURIRequest) (*ParseURIResponse, error)
 	// GetAddressBook Retrieves entries from the address book.
 	GetAddressBook(req *GetAddressBookRequest) (*GetAddressBookResponse, error)
+	GetAddressBookContext(ctx context.Context, req *GetAddressBookRequest) (*GetAddressBookResponse, error)
 	// AddAddressBook Add an entry to the address book.
 	AddAddressBook(req *AddAddressBookRequest) (*AddAddressBookResponse, error)
+	AddAddressBookContext(ctx context.Context, req *AddAddressBookRequest) (*AddAddressBookResponse, error)
 	// EditAddressBook Edit an existing address book entry.
 	EditAddressBook(req *EditAddressBookRequest) error
+	EditAddressBookContext(ctx context.Context, req *EditAddressBookRequest) error
 	// DeleteAddressBook Delete an entry from the address book
 	DeleteAddressBook(req *DeleteAddressBookRequest) error
+	DeleteAddressBookContext(ctx context.Context, req *DeleteAddressBookRequest) error
 	// Refresh a wallet after openning.
 	Refresh(req *RefreshRequest) (*RefreshResponse, error)
+	RefreshContext(ctx context.Context, req *RefreshRequest) (*RefreshResponse, error)
 	// AutoRefresh Set whether and how often to automatically refresh the current wallet.
 	AutoRefresh(req *AutoRefreshRequest) error
+	AutoRefreshContext(ctx context.Context, req *AutoRefreshRequest) error
 	// RescanSpent Rescan the blockchain for spent outputs.
 	RescanSpent() error
+	RescanSpentContext(ctx context.Context) error
 	// StartMining Start mining in the Monero daemon.
 	StartMining(req *StartMiningRequest) error
+	StartMiningContext(ctx context.Context, req *StartMiningRequest) error
 	// StopMining Stop mining in the Monero daemon.
 	StopMining() error
+	StopMiningContext(ctx context.Context) error
 	// GetLanguages Get a list of available languages for your wallet's seed.
 	GetLanguages() (*GetLanguagesResponse, error)
+	GetLanguagesContext(ctx context.Context) (*GetLanguagesResponse, error)
 	// CreateWallet Create a new wallet. You need to have set the argument "–wallet-dir" when launching monero-wallet-rpc to make this work.
 	CreateWallet(req *CreateWalletRequest) error
+	CreateWalletContext(ctx context.Context, req *CreateWalletRequest) error
 	// GenerateFromKeys Restores a wallet from a given wallet address, view key, and optional spend key.
 	GenerateFromKeys(req *GenerateFromKeysRequest) (*GenerateFromKeysResponse, error)
+	GenerateFromKeysContext(ctx context.Context, req *GenerateFromKeysRequest) (*GenerateFromKeysResponse, error)
 	// OpenWallet Open a wallet. You need to have set the argument "–wallet-dir" when launching monero-wallet-rpc to make this work.
 	OpenWallet(req *OpenWalletRequest) error
+	OpenWalletContext(ctx context.Context, req *OpenWalletRequest) error
 	// RestoreDeterministicWallet Create and open a wallet on the RPC server from an existing mnemonic phrase and close the currently open wallet.
 	RestoreDeterministicWallet(req *RestoreDeterministicWalletRequest) (*RestoreDeterministicWalletResponse, error)
+	RestoreDeterministicWalletContext(ctx context.Context, req *RestoreDeterministicWalletRequest) (*RestoreDeterministicWalletResponse, error)
 	// CloseWallet Close the currently opened wallet, after trying to save it.
 	CloseWallet() error
+	CloseWalletContext(ctx context.Context) error
 	// ChangeWalletPassword Change a wallet password.
 	ChangeWalletPassword(req *ChangeWalletPasswordRequest) error
+	ChangeWalletPasswordContext(ctx context.Context, req *ChangeWalletPasswordRequest) error
 	// IsMultisig Check if a wallet is a multisig one.
 	IsMultisig() (*IsMultisigResponse, error)
+	IsMultisigContext(ctx context.Context) (*IsMultisigResponse, error)
 	// PrepareMultisig Prepare a wallet for multisig by generating a multisig string to share with peers.
 	PrepareMultisig() (*PrepareMultisigResponse, error)
+	PrepareMultisigContext(ctx context.Context) (*PrepareMultisigResponse, error)
 	// MakeMultisig Make a wallet multisig by importing peers multisig string.
 	MakeMultisig(req *MakeMultisigRequest) (*MakeMultisigResponse, error)
+	MakeMultisigContext(ctx context.Context, req *MakeMultisigRequest) (*MakeMultisigResponse, error)
 	// ExportMultisigInfo Export multisig info for other participants.
 	ExportMultisigInfo() (*ExportMultisigInfoResponse, error)
+	ExportMultisigInfoContext(ctx context.Context) (*ExportMultisigInfoResponse, error)
 	// ImportMultisigInfo Import multisig info from other participants.
 	ImportMultisigInfo(req *ImportMultisigInfoRequest) (*ImportMultisigInfoResponse, error)
+	ImportMultisigInfoContext(ctx context.Context, req *ImportMultisigInfoRequest) (*ImportMultisigInfoResponse, error)
 	// FinalizeMultisig Turn this wallet into a multisig wallet, extra step for N-1/N wallets.
 	FinalizeMultisig(req *FinalizeMultisigRequest) (*FinalizeMultisigResponse, error)
+	FinalizeMultisigContext(ctx context.Context, req *FinalizeMultisigRequest) (*FinalizeMultisigResponse, error)
 	// SignMultisig Sign a transaction in multisig.
 	SignMultisig(req *SignMultisigRequest) (*SignMultisigResponse, error)
+	SignMultisigContext(ctx context.Context, req *SignMultisigRequest) (*SignMultisigResponse, error)
 	// SubmitMultisig Submit a signed multisig transaction.
 	SubmitMultisig(req *SubmitMultisigRequest) (*SubmitMultisigResponse, error)
+	SubmitMultisigContext(ctx context.Context, req *SubmitMultisigRequest) (*SubmitMultisigResponse, error)
 	// GetVersion Get RPC version Major & Minor integer-format, where Major is the first 16 bits and Minor the last 16 bits.
 	GetVersion() (*GetVersionResponse, error)
+	GetVersionContext(ctx context.Context) (*GetVersionResponse, error)
 }
 
 // MoneroRPC interface for client
@@ -172,6 +255,13 @@ type MoneroRPC interface {
 	Do(method string, req interface{}, res interface{}) error
 }
 
+// MoneroRPCContext is implemented by clients which can bound a call with a context.
+// Clients that only implement MoneroRPC still work, but the context is only checked before the call is made.
+type MoneroRPCContext interface {
+	MoneroRPC
+	DoContext(ctx context.Context, method string, req interface{}, res interface{}) error
+}
+
 type wallet struct {
 	client MoneroRPC
 }
@@ -183,441 +273,772 @@ func New(client MoneroRPC) Wallet {
 	}
 }
 
+// do calls the RPC method through the context aware client when available
+func (w *wallet) do(ctx context.Context, method string, req interface{}, res interface{}) error {
+	if client, ok := w.client.(MoneroRPCContext); ok {
+		return client.DoContext(ctx, method, req, res)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return w.client.Do(method, req, res)
+}
+
 // SetDaemon connects the RPC server to a Monero daemon.
 func (w *wallet) SetDaemon(req *SetDaemonRequest) error {
-	return w.client.Do("set_daemon", req, nil)
+	return w.SetDaemonContext(context.Background(), req)
+}
+
+func (w *wallet) SetDaemonContext(ctx context.Context, req *SetDaemonRequest) error {
+	return w.do(ctx, "set_daemon", req, nil)
 }
 
 func (w *wallet) GetBalance(req *GetBalanceRequest) (*GetBalanceResponse, error) {
+	return w.GetBalanceContext(context.Background(), req)
+}
+
+func (w *wallet) GetBalanceContext(ctx context.Context, req *GetBalanceRequest) (*GetBalanceResponse, error) {
 	res := new(GetBalanceResponse)
-	err := w.client.Do("get_balance", req, res)
+	err := w.do(ctx, "get_balance", req, res)
 	return res, err
 }
 
 func (w *wallet) GetAddress(req *GetAddressRequest) (*GetAddressResponse, error) {
+	return w.GetAddressContext(context.Background(), req)
+}
+
+func (w *wallet) GetAddressContext(ctx context.Context, req *GetAddressRequest) (*GetAddressResponse, error) {
 	res := new(GetAddressResponse)
-	err := w.client.Do("get_address", req, res)
+	err := w.do(ctx, "get_address", req, res)
 	return res, err
 }
 
 func (w *wallet) GetAddressIndex(req *GetAddressIndexRequest) (*GetAddressIndexResponse, error) {
+	return w.GetAddressIndexContext(context.Background(), req)
+}
+
+func (w *wallet) GetAddressIndexContext(ctx context.Context, req *GetAddressIndexRequest) (*GetAddressIndexResponse, error) {
 	res := new(GetAddressIndexResponse)
-	err := w.client.Do("get_address_index", req, res)
+	err := w.do(ctx, "get_address_index", req, res)
 	return res, err
 }
 
 func (w *wallet) CreateAddress(req *CreateAddressRequest) (*CreateAddressResponse, error) {
+	return w.CreateAddressContext(context.Background(), req)
+}
+
+func (w *wallet) CreateAddressContext(ctx context.Context, req *CreateAddressRequest) (*CreateAddressResponse, error) {
 	res := new(CreateAddressResponse)
-	err := w.client.Do("create_address", req, res)
+	err := w.do(ctx, "create_address", req, res)
 	return res, err
 }
 
 func (w *wallet) LabelAddress(req *LabelAddressRequest) error {
-	return w.client.Do("label_address", req, nil)
+	return w.LabelAddressContext(context.Background(), req)
+}
+
+func (w *wallet) LabelAddressContext(ctx context.Context, req *LabelAddressRequest) error {
+	return w.do(ctx, "label_address", req, nil)
 }
 
 func (w *wallet) ValidateAddress(req *ValidateAddressRequest) (*ValidateAddressResponse, error) {
+	return w.ValidateAddressContext(context.Background(), req)
+}
+
+func (w *wallet) ValidateAddressContext(ctx context.Context, req *ValidateAddressRequest) (*ValidateAddressResponse, error) {
 	res := new(ValidateAddressResponse)
-	err := w.client.Do("validate_address", req, res)
+	err := w.do(ctx, "validate_address", req, res)
 	return res, err
 }
 
 func (w *wallet) GetAccounts(req *GetAccountsRequest) (*GetAccountsResponse, error) {
+	return w.GetAccountsContext(context.Background(), req)
+}
+
+func (w *wallet) GetAccountsContext(ctx context.Context, req *GetAccountsRequest) (*GetAccountsResponse, error) {
 	res := new(GetAccountsResponse)
-	err := w.client.Do("get_accounts", req, res)
+	err := w.do(ctx, "get_accounts", req, res)
 	return res, err
 }
 
 func (w *wallet) CreateAccount(req *CreateAccountRequest) (*CreateAccountResponse, error) {
+	return w.CreateAccountContext(context.Background(), req)
+}
+
+func (w *wallet) CreateAccountContext(ctx context.Context, req *CreateAccountRequest) (*CreateAccountResponse, error) {
 	res := new(CreateAccountResponse)
-	err := w.client.Do("create_account", req, res)
+	err := w.do(ctx, "create_account", req, res)
 	return res, err
 }
 
 func (w *wallet) LabelAccount(req *LabelAccountRequest) error {
-	return w.client.Do("label_account", req, nil)
+	return w.LabelAccountContext(context.Background(), req)
+}
+
+func (w *wallet) LabelAccountContext(ctx context.Context, req *LabelAccountRequest) error {
+	return w.do(ctx, "label_account", req, nil)
 }
 
 func (w *wallet) GetAccountTags() (*GetAccountTagsResponse, error) {
+	return w.GetAccountTagsContext(context.Background())
+}
+
+func (w *wallet) GetAccountTagsContext(ctx context.Context) (*GetAccountTagsResponse, error) {
 	res := new(GetAccountTagsResponse)
-	err := w.client.Do("get_account_tags", nil, res)
+	err := w.do(ctx, "get_account_tags", nil, res)
 	return res, err
 }
 
 func (w *wallet) TagAccounts(req *TagAccountsRequest) error {
-	return w.client.Do("tag_accounts", req, nil)
+	return w.TagAccountsContext(context.Background(), req)
+}
+
+func (w *wallet) TagAccountsContext(ctx context.Context, req *TagAccountsRequest) error {
+	return w.do(ctx, "tag_accounts", req, nil)
 }
 
 func (w *wallet) UntagAccounts(req *UntagAccountsRequest) error {
-	return w.client.Do("untag_accounts", req, nil)
+	return w.UntagAccountsContext(context.Background(), req)
+}
+
+func (w *wallet) UntagAccountsContext(ctx context.Context, req *UntagAccountsRequest) error {
+	return w.do(ctx, "untag_accounts", req, nil)
 }
 
 func (w *wallet) SetAccountTagDescription(req *SetAccountTagDescriptionRequest) error {
-	return w.client.Do("set_account_tag_description", req, nil)
+	return w.SetAccountTagDescriptionContext(context.Background(), req)
+}
+
+func (w *wallet) SetAccountTagDescriptionContext(ctx context.Context, req *SetAccountTagDescriptionRequest) error {
+	return w.do(ctx, "set_account_tag_description", req, nil)
 }
 
 func (w *wallet) GetHeight() (*GetHeightResponse, error) {
+	return w.GetHeightContext(context.Background())
+}
+
+func (w *wallet) GetHeightContext(ctx context.Context) (*GetHeightResponse, error) {
 	res := new(GetHeightResponse)
-	err := w.client.Do("get_height", nil, res)
+	err := w.do(ctx, "get_height", nil, res)
 	return res, err
 }
 
 func (w *wallet) Transfer(req *TransferRequest) (*TransferResponse, error) {
+	return w.TransferContext(context.Background(), req)
+}
+
+func (w *wallet) TransferContext(ctx context.Context, req *TransferRequest) (*TransferResponse, error) {
 	res := new(TransferResponse)
-	err := w.client.Do("transfer", req, res)
+	err := w.do(ctx, "transfer", req, res)
 	return res, err
 }
 
 func (w *wallet) TransferSplit(req *TransferSplitRequest) (*TransferSplitResponse, error) {
+	return w.TransferSplitContext(context.Background(), req)
+}
+
+func (w *wallet) TransferSplitContext(ctx context.Context, req *TransferSplitRequest) (*TransferSplitResponse, error) {
 	res := new(TransferSplitResponse)
-	err := w.client.Do("transfer_split", req, res)
+	err := w.do(ctx, "transfer_split", req, res)
 	return res, err
 }
 
 func (w *wallet) SignTransfer(req *SignTransferRequest) (*SignTransferResponse, error) {
+	return w.SignTransferContext(context.Background(), req)
+}
+
+func (w *wallet) SignTransferContext(ctx context.Context, req *SignTransferRequest) (*SignTransferResponse, error) {
 	res := new(SignTransferResponse)
-	err := w.client.Do("sign_transfer", req, res)
+	err := w.do(ctx, "sign_transfer", req, res)
 	return res, err
 }
 
 func (w *wallet) SubmitTransfer(req *SubmitTransferRequest) (*SubmitTransferResponse, error) {
+	return w.SubmitTransferContext(context.Background(), req)
+}
+
+func (w *wallet) SubmitTransferContext(ctx context.Context, req *SubmitTransferRequest) (*SubmitTransferResponse, error) {
 	res := new(SubmitTransferResponse)
-	err := w.client.Do("submit_transfer", req, res)
+	err := w.do(ctx, "submit_transfer", req, res)
 	return res, err
 }
 
 func (w *wallet) SweepDust(req *SweepDustRequest) (*SweepDustResponse, error) {
+	return w.SweepDustContext(context.Background(), req)
+}
+
+func (w *wallet) SweepDustContext(ctx context.Context, req *SweepDustRequest) (*SweepDustResponse, error) {
 	res := new(SweepDustResponse)
-	err := w.client.Do("sweep_dust", req, res)
+	err := w.do(ctx, "sweep_dust", req, res)
 	return res, err
 }
 
 func (w *wallet) SweepAll(req *SweepAllRequest) (*SweepAllResponse, error) {
+	return w.SweepAllContext(context.Background(), req)
+}
+
+func (w *wallet) SweepAllContext(ctx context.Context, req *SweepAllRequest) (*SweepAllResponse, error) {
 	res := new(SweepAllResponse)
-	err := w.client.Do("sweep_all", req, res)
+	err := w.do(ctx, "sweep_all", req, res)
 	return res, err
 }
 
 func (w *wallet) SweepSingle(req *SweepSingleRequest) (*SweepSingleResponse, error) {
+	return w.SweepSingleContext(context.Background(), req)
+}
+
+func (w *wallet) SweepSingleContext(ctx context.Context, req *SweepSingleRequest) (*SweepSingleResponse, error) {
 	res := new(SweepSingleResponse)
-	err := w.client.Do("sweep_single", req, res)
+	err := w.do(ctx, "sweep_single", req, res)
 	return res, err
 }
 
 func (w *wallet) RelayTx(req *RelayTxRequest) (*RelayTxResponse, error) {
+	return w.RelayTxContext(context.Background(), req)
+}
+
+func (w *wallet) RelayTxContext(ctx context.Context, req *RelayTxRequest) (*RelayTxResponse, error) {
 	res := new(RelayTxResponse)
-	err := w.client.Do("relay_tx", req, res)
+	err := w.do(ctx, "relay_tx", req, res)
 	return res, err
 }
 
 func (w *wallet) Store() error {
-	return w.client.Do("store", nil, nil)
+	return w.StoreContext(context.Background())
+}
+
+func (w *wallet) StoreContext(ctx context.Context) error {
+	return w.do(ctx, "store", nil, nil)
 }
 
 func (w *wallet) GetPayments(req *GetPaymentsRequest) (*GetPaymentsResponse, error) {
+	return w.GetPaymentsContext(context.Background(), req)
+}
+
+func (w *wallet) GetPaymentsContext(ctx context.Context, req *GetPaymentsRequest) (*GetPaymentsResponse, error) {
 	res := new(GetPaymentsResponse)
-	err := w.client.Do("get_payments", req, res)
+	err := w.do(ctx, "get_payments", req, res)
 	return res, err
 }
 
 func (w *wallet) GetBulkPayments(req *GetBulkPaymentsRequest) (*GetBulkPaymentsResponse, error) {
+	return w.GetBulkPaymentsContext(context.Background(), req)
+}
+
+func (w *wallet) GetBulkPaymentsContext(ctx context.Context, req *GetBulkPaymentsRequest) (*GetBulkPaymentsResponse, error) {
 	res := new(GetBulkPaymentsResponse)
-	err := w.client.Do("get_bulk_payments", req, res)
+	err := w.do(ctx, "get_bulk_payments", req, res)
 	return res, err
 }
 
 func (w *wallet) IncomingTransfers(req *IncomingTransfersRequest) (*IncomingTransfersResponse, error) {
+	return w.IncomingTransfersContext(context.Background(), req)
+}
+
+func (w *wallet) IncomingTransfersContext(ctx context.Context, req *IncomingTransfersRequest) (*IncomingTransfersResponse, error) {
 	res := new(IncomingTransfersResponse)
-	err := w.client.Do("incoming_transfers", req, res)
+	err := w.do(ctx, "incoming_transfers", req, res)
 	return res, err
 }
 
 func (w *wallet) QueryKey(req *QueryKeyRequest) (*QueryKeyResponse, error) {
+	return w.QueryKeyContext(context.Background(), req)
+}
+
+func (w *wallet) QueryKeyContext(ctx context.Context, req *QueryKeyRequest) (*QueryKeyResponse, error) {
 	res := new(QueryKeyResponse)
-	err := w.client.Do("query_key", req, res)
+	err := w.do(ctx, "query_key", req, res)
 	return res, err
 }
 
 func (w *wallet) MakeIntegratedAddress(req *MakeIntegratedAddressRequest) (*MakeIntegratedAddressResponse, error) {
+	return w.MakeIntegratedAddressContext(context.Background(), req)
+}
+
+func (w *wallet) MakeIntegratedAddressContext(ctx context.Context, req *MakeIntegratedAddressRequest) (*MakeIntegratedAddressResponse, error) {
 	res := new(MakeIntegratedAddressResponse)
-	err := w.client.Do("make_integrated_address", req, res)
+	err := w.do(ctx, "make_integrated_address", req, res)
 	return res, err
 }
 
 func (w *wallet) SplitIntegratedAddress(req *SplitIntegratedAddressRequest) (*SplitIntegratedAddressResponse, error) {
+	return w.SplitIntegratedAddressContext(context.Background(), req)
+}
+
+func (w *wallet) SplitIntegratedAddressContext(ctx context.Context, req *SplitIntegratedAddressRequest) (*SplitIntegratedAddressResponse, error) {
 	res := new(SplitIntegratedAddressResponse)
-	err := w.client.Do("split_integrated_address", req, res)
+	err := w.do(ctx, "split_integrated_address", req, res)
 	return res, err
 }
 
 func (w *wallet) StopWallet() error {
-	return w.client.Do("stop_wallet", nil, nil)
+	return w.StopWalletContext(context.Background())
+}
+
+func (w *wallet) StopWalletContext(ctx context.Context) error {
+	return w.do(ctx, "stop_wallet", nil, nil)
 }
 
 func (w *wallet) RescanBlockchain() error {
-	return w.client.Do("rescan_blockchain", nil, nil)
+	return w.RescanBlockchainContext(context.Background())
+}
+
+func (w *wallet) RescanBlockchainContext(ctx context.Context) error {
+	return w.do(ctx, "rescan_blockchain", nil, nil)
 }
 
 func (w *wallet) SetTxNotes(req *SetTxNotesRequest) error {
-	return w.client.Do("set_tx_notes", req, nil)
+	return w.SetTxNotesContext(context.Background(), req)
+}
+
+func (w *wallet) SetTxNotesContext(ctx context.Context, req *SetTxNotesRequest) error {
+	return w.do(ctx, "set_tx_notes", req, nil)
 }
 
 func (w *wallet) GetTxNotes(req *GetTxNotesRequest) (*GetTxNotesResponse, error) {
+	return w.GetTxNotesContext(context.Background(), req)
+}
+
+func (w *wallet) GetTxNotesContext(ctx context.Context, req *GetTxNotesRequest) (*GetTxNotesResponse, error) {
 	res := new(GetTxNotesResponse)
-	err := w.client.Do("get_tx_notes", req, res)
+	err := w.do(ctx, "get_tx_notes", req, res)
 	return res, err
 }
 
 func (w *wallet) SetAttribute(req *SetAttributeRequest) error {
-	return w.client.Do("set_attribute", req, nil)
+	return w.SetAttributeContext(context.Background(), req)
+}
+
+func (w *wallet) SetAttributeContext(ctx context.Context, req *SetAttributeRequest) error {
+	return w.do(ctx, "set_attribute", req, nil)
 }
 
 func (w *wallet) GetAttribute(req *GetAttributeRequest) (*GetAttributeResponse, error) {
+	return w.GetAttributeContext(context.Background(), req)
+}
+
+func (w *wallet) GetAttributeContext(ctx context.Context, req *GetAttributeRequest) (*GetAttributeResponse, error) {
 	res := new(GetAttributeResponse)
-	err := w.client.Do("get_attribute", req, res)
+	err := w.do(ctx, "get_attribute", req, res)
 	return res, err
 }
 
 func (w *wallet) GetTxKey(req *GetTxKeyRequest) (*GetTxKeyResponse, error) {
+	return w.GetTxKeyContext(context.Background(), req)
+}
+
+func (w *wallet) GetTxKeyContext(ctx context.Context, req *GetTxKeyRequest) (*GetTxKeyResponse, error) {
 	res := new(GetTxKeyResponse)
-	err := w.client.Do("get_tx_key", req, res)
+	err := w.do(ctx, "get_tx_key", req, res)
 	return res, err
 }
 
 func (w *wallet) CheckTxKey(req *CheckTxKeyRequest) (*CheckTxKeyResponse, error) {
+	return w.CheckTxKeyContext(context.Background(), req)
+}
+
+func (w *wallet) CheckTxKeyContext(ctx context.Context, req *CheckTxKeyRequest) (*CheckTxKeyResponse, error) {
 	res := new(CheckTxKeyResponse)
-	err := w.client.Do("check_tx_key", req, res)
+	err := w.do(ctx, "check_tx_key", req, res)
 	return res, err
 }
 
 func (w *wallet) GetTxProof(req *GetTxProofRequest) (*GetTxProofResponse, error) {
+	return w.GetTxProofContext(context.Background(), req)
+}
+
+func (w *wallet) GetTxProofContext(ctx context.Context, req *GetTxProofRequest) (*GetTxProofResponse, error) {
 	res := new(GetTxProofResponse)
-	err := w.client.Do("get_tx_proof", req, res)
+	err := w.do(ctx, "get_tx_proof", req, res)
 	return res, err
 }
 
 func (w *wallet) CheckTxProof(req *CheckTxProofRequest) (*CheckTxProofResponse, error) {
+	return w.CheckTxProofContext(context.Background(), req)
+}
+
+func (w *wallet) CheckTxProofContext(ctx context.Context, req *CheckTxProofRequest) (*CheckTxProofResponse, error) {
 	res := new(CheckTxProofResponse)
-	err := w.client.Do("check_tx_proof", req, res)
+	err := w.do(ctx, "check_tx_proof", req, res)
 	return res, err
 }
 
 func (w *wallet) GetSpendProof(req *GetSpendProofRequest) (*GetSpendProofResponse, error) {
+	return w.GetSpendProofContext(context.Background(), req)
+}
+
+func (w *wallet) GetSpendProofContext(ctx context.Context, req *GetSpendProofRequest) (*GetSpendProofResponse, error) {
 	res := new(GetSpendProofResponse)
-	err := w.client.Do("get_spend_proof", req, res)
+	err := w.do(ctx, "get_spend_proof", req, res)
 	return res, err
 }
 
 func (w *wallet) CheckSpendProof(req *CheckSpendProofRequest) (*CheckSpendProofResponse, error) {
+	return w.CheckSpendProofContext(context.Background(), req)
+}
+
+func (w *wallet) CheckSpendProofContext(ctx context.Context, req *CheckSpendProofRequest) (*CheckSpendProofResponse, error) {
 	res := new(CheckSpendProofResponse)
-	err := w.client.Do("check_spend_proof", req, res)
+	err := w.do(ctx, "check_spend_proof", req, res)
 	return res, err
 }
 
 func (w *wallet) GetReserveProof(req *GetReserveProofRequest) (*GetReserveProofResponse, error) {
+	return w.GetReserveProofContext(context.Background(), req)
+}
+
+func (w *wallet) GetReserveProofContext(ctx context.Context, req *GetReserveProofRequest) (*GetReserveProofResponse, error) {
 	res := new(GetReserveProofResponse)
-	err := w.client.Do("get_reserve_proof", req, res)
+	err := w.do(ctx, "get_reserve_proof", req, res)
 	return res, err
 }
 
 func (w *wallet) CheckReserveProof(req *CheckReserveProofRequest) (*CheckReserveProofResponse, error) {
+	return w.CheckReserveProofContext(context.Background(), req)
+}
+
+func (w *wallet) CheckReserveProofContext(ctx context.Context, req *CheckReserveProofRequest) (*CheckReserveProofResponse, error) {
 	res := new(CheckReserveProofResponse)
-	err := w.client.Do("check_reserve_proof", req, res)
+	err := w.do(ctx, "check_reserve_proof", req, res)
 	return res, err
 }
 
 func (w *wallet) GetTransfers(req *GetTransfersRequest) (*GetTransfersResponse, error) {
+	return w.GetTransfersContext(context.Background(), req)
+}
+
+func (w *wallet) GetTransfersContext(ctx context.Context, req *GetTransfersRequest) (*GetTransfersResponse, error) {
 	res := new(GetTransfersResponse)
-	err := w.client.Do("get_transfers", req, res)
+	err := w.do(ctx, "get_transfers", req, res)
 	return res, err
 }
 
 func (w *wallet) GetTransferByTxid(req *GetTransferByTxidRequest) (*GetTransferByTxidResponse, error) {
+	return w.GetTransferByTxidContext(context.Background(), req)
+}
+
+func (w *wallet) GetTransferByTxidContext(ctx context.Context, req *GetTransferByTxidRequest) (*GetTransferByTxidResponse, error) {
 	res := new(GetTransferByTxidResponse)
-	err := w.client.Do("get_transfer_by_txid", req, res)
+	err := w.do(ctx, "get_transfer_by_txid", req, res)
 	return res, err
 }
 
 func (w *wallet) DescribeTransfer(req *DescribeTransferRequest) (*DescribeTransferResponse, error) {
+	return w.DescribeTransferContext(context.Background(), req)
+}
+
+func (w *wallet) DescribeTransferContext(ctx context.Context, req *DescribeTransferRequest) (*DescribeTransferResponse, error) {
 	res := new(DescribeTransferResponse)
-	err := w.client.Do("describe_transfer", req, res)
+	err := w.do(ctx, "describe_transfer", req, res)
 	return res, err
 }
 
 func (w *wallet) Sign(req *SignRequest) (*SignResponse, error) {
+	return w.SignContext(context.Background(), req)
+}
+
+func (w *wallet) SignContext(ctx context.Context, req *SignRequest) (*SignResponse, error) {
 	res := new(SignResponse)
-	err := w.client.Do("sign", req, res)
+	err := w.do(ctx, "sign", req, res)
 	return res, err
 }
 
 func (w *wallet) Verify(req *VerifyRequest) (*VerifyResponse, error) {
+	return w.VerifyContext(context.Background(), req)
+}
+
+func (w *wallet) VerifyContext(ctx context.Context, req *VerifyRequest) (*VerifyResponse, error) {
 	res := new(VerifyResponse)
-	err := w.client.Do("verify", req, res)
+	err := w.do(ctx, "verify", req, res)
 	return res, err
 }
 
 func (w *wallet) ExportOutputs(req *ExportOutputsRequest) (*ExportOutputsResponse, error) {
+	return w.ExportOutputsContext(context.Background(), req)
+}
+
+func (w *wallet) ExportOutputsContext(ctx context.Context, req *ExportOutputsRequest) (*ExportOutputsResponse, error) {
 	res := new(ExportOutputsResponse)
-	err := w.client.Do("export_outputs", req, res)
+	err := w.do(ctx, "export_outputs", req, res)
 	return res, err
 }
 
 func (w *wallet) ImportOutputs(req *ImportOutputsRequest) (*ImportOutputsResponse, error) {
+	return w.ImportOutputsContext(context.Background(), req)
+}
+
+func (w *wallet) ImportOutputsContext(ctx context.Context, req *ImportOutputsRequest) (*ImportOutputsResponse, error) {
 	res := new(ImportOutputsResponse)
-	err := w.client.Do("import_outputs", req, res)
+	err := w.do(ctx, "import_outputs", req, res)
 	return res, err
 }
 
 func (w *wallet) ExportKeyImages(req *ExportKeyImagesRequest) (*ExportKeyImagesResponse, error) {
+	return w.ExportKeyImagesContext(context.Background(), req)
+}
+
+func (w *wallet) ExportKeyImagesContext(ctx context.Context, req *ExportKeyImagesRequest) (*ExportKeyImagesResponse, error) {
 	res := new(ExportKeyImagesResponse)
-	err := w.client.Do("export_key_images", req, res)
+	err := w.do(ctx, "export_key_images", req, res)
 	return res, err
 }
 
 func (w *wallet) ImportKeyImages(req *ImportKeyImagesRequest) (*ImportKeyImagesResponse, error) {
+	return w.ImportKeyImagesContext(context.Background(), req)
+}
+
+func (w *wallet) ImportKeyImagesContext(ctx context.Context, req *ImportKeyImagesRequest) (*ImportKeyImagesResponse, error) {
 	res := new(ImportKeyImagesResponse)
-	err := w.client.Do("import_key_images", req, res)
+	err := w.do(ctx, "import_key_images", req, res)
 	return res, err
 }
 
 func (w *wallet) MakeURI(req *MakeURIRequest) (*MakeURIResponse, error) {
+	return w.MakeURIContext(context.Background(), req)
+}
+
+func (w *wallet) MakeURIContext(ctx context.Context, req *MakeURIRequest) (*MakeURIResponse, error) {
 	res := new(MakeURIResponse)
-	err := w.client.Do("make_uri", req, res)
+	err := w.do(ctx, "make_uri", req, res)
 	return res, err
 }
 
 func (w *wallet) ParseURI(req *ParseURIRequest) (*ParseURIResponse, error) {
+	return w.ParseURIContext(context.Background(), req)
+}
+
+func (w *wallet) ParseURIContext(ctx context.Context, req *ParseURIRequest) (*ParseURIResponse, error) {
 	res := new(ParseURIResponse)
-	err := w.client.Do("parse_uri", req, res)
+	err := w.do(ctx, "parse_uri", req, res)
 	return res, err
 }
 
 func (w *wallet) GetAddressBook(req *GetAddressBookRequest) (*GetAddressBookResponse, error) {
+	return w.GetAddressBookContext(context.Background(), req)
+}
+
+func (w *wallet) GetAddressBookContext(ctx context.Context, req *GetAddressBookRequest) (*GetAddressBookResponse, error) {
 	res := new(GetAddressBookResponse)
-	err := w.client.Do("get_address_book", req, res)
+	err := w.do(ctx, "get_address_book", req, res)
 	return res, err
 }
 
 func (w *wallet) AddAddressBook(req *AddAddressBookRequest) (*AddAddressBookResponse, error) {
+	return w.AddAddressBookContext(context.Background(), req)
+}
+
+func (w *wallet) AddAddressBookContext(ctx context.Context, req *AddAddressBookRequest) (*AddAddressBookResponse, error) {
 	res := new(AddAddressBookResponse)
-	err := w.client.Do("add_address_book", req, res)
+	err := w.do(ctx, "add_address_book", req, res)
 	return res, err
 }
 
 func (w *wallet) EditAddressBook(req *EditAddressBookRequest) error {
-	return w.client.Do("edit_address_book", nil, nil)
+	return w.EditAddressBookContext(context.Background(), req)
+}
+
+func (w *wallet) EditAddressBookContext(ctx context.Context, req *EditAddressBookRequest) error {
+	return w.do(ctx, "edit_address_book", nil, nil)
 }
 
 func (w *wallet) DeleteAddressBook(req *DeleteAddressBookRequest) error {
-	return w.client.Do("delete_address_book", req, nil)
+	return w.DeleteAddressBookContext(context.Background(), req)
+}
+
+func (w *wallet) DeleteAddressBookContext(ctx context.Context, req *DeleteAddressBookRequest) error {
+	return w.do(ctx, "delete_address_book", req, nil)
 }
 
 func (w *wallet) Refresh(req *RefreshRequest) (*RefreshResponse, error) {
+	return w.RefreshContext(context.Background(), req)
+}
+
+func (w *wallet) RefreshContext(ctx context.Context, req *RefreshRequest) (*RefreshResponse, error) {
 	res := new(RefreshResponse)
-	err := w.client.Do("refresh", req, res)
+	err := w.do(ctx, "refresh", req, res)
 	return res, err
 }
 
 func (w *wallet) AutoRefresh(req *AutoRefreshRequest) error {
-	return w.client.Do("auto_refresh", req, nil)
+	return w.AutoRefreshContext(context.Background(), req)
+}
+
+func (w *wallet) AutoRefreshContext(ctx context.Context, req *AutoRefreshRequest) error {
+	return w.do(ctx, "auto_refresh", req, nil)
 }
 
 func (w *wallet) RescanSpent() error {
-	return w.client.Do("rescan_spent", nil, nil)
+	return w.RescanSpentContext(context.Background())
+}
+
+func (w *wallet) RescanSpentContext(ctx context.Context) error {
+	return w.do(ctx, "rescan_spent", nil, nil)
 }
 
 func (w *wallet) StartMining(req *StartMiningRequest) error {
-	return w.client.Do("start_mining", req, nil)
+	return w.StartMiningContext(context.Background(), req)
+}
+
+func (w *wallet) StartMiningContext(ctx context.Context, req *StartMiningRequest) error {
+	return w.do(ctx, "start_mining", req, nil)
 }
 
 func (w *wallet) StopMining() error {
-	return w.client.Do("stop_mining", nil, nil)
+	return w.StopMiningContext(context.Background())
+}
+
+func (w *wallet) StopMiningContext(ctx context.Context) error {
+	return w.do(ctx, "stop_mining", nil, nil)
 }
 
 func (w *wallet) GetLanguages() (*GetLanguagesResponse, error) {
+	return w.GetLanguagesContext(context.Background())
+}
+
+func (w *wallet) GetLanguagesContext(ctx context.Context) (*GetLanguagesResponse, error) {
 	res := new(GetLanguagesResponse)
-	err := w.client.Do("get_languages", nil, res)
+	err := w.do(ctx, "get_languages", nil, res)
 	return res, err
 }
 
 func (w *wallet) CreateWallet(req *CreateWalletRequest) error {
-	return w.client.Do("create_wallet", req, nil)
+	return w.CreateWalletContext(context.Background(), req)
+}
+
+func (w *wallet) CreateWalletContext(ctx context.Context, req *CreateWalletRequest) error {
+	return w.do(ctx, "create_wallet", req, nil)
 }
 
 func (w *wallet) GenerateFromKeys(req *GenerateFromKeysRequest) (*GenerateFromKeysResponse, error) {
+	return w.GenerateFromKeysContext(context.Background(), req)
+}
+
+func (w *wallet) GenerateFromKeysContext(ctx context.Context, req *GenerateFromKeysRequest) (*GenerateFromKeysResponse, error) {
 	res := new(GenerateFromKeysResponse)
-	err := w.client.Do("generate_from_keys", req, res)
+	err := w.do(ctx, "generate_from_keys", req, res)
 	return res, err
 }
 
 func (w *wallet) OpenWallet(req *OpenWalletRequest) error {
-	return w.client.Do("open_wallet", req, nil)
+	return w.OpenWalletContext(context.Background(), req)
+}
+
+func (w *wallet) OpenWalletContext(ctx context.Context, req *OpenWalletRequest) error {
+	return w.do(ctx, "open_wallet", req, nil)
 }
 
 func (w *wallet) RestoreDeterministicWallet(req *RestoreDeterministicWalletRequest) (*RestoreDeterministicWalletResponse, error) {
+	return w.RestoreDeterministicWalletContext(context.Background(), req)
+}
+
+func (w *wallet) RestoreDeterministicWalletContext(ctx context.Context, req *RestoreDeterministicWalletRequest) (*RestoreDeterministicWalletResponse, error) {
 	res := new(RestoreDeterministicWalletResponse)
-	err := w.client.Do("restore_deterministic_wallet", req, res)
+	err := w.do(ctx, "restore_deterministic_wallet", req, res)
 	return res, err
 }
 
 func (w *wallet) CloseWallet() error {
-	return w.client.Do("close_wallet", nil, nil)
+	return w.CloseWalletContext(context.Background())
+}
+
+func (w *wallet) CloseWalletContext(ctx context.Context) error {
+	return w.do(ctx, "close_wallet", nil, nil)
 }
 
 func (w *wallet) ChangeWalletPassword(req *ChangeWalletPasswordRequest) error {
-	return w.client.Do("change_wallet_password", req, nil)
+	return w.ChangeWalletPasswordContext(context.Background(), req)
+}
+
+func (w *wallet) ChangeWalletPasswordContext(ctx context.Context, req *ChangeWalletPasswordRequest) error {
+	return w.do(ctx, "change_wallet_password", req, nil)
 }
 
 func (w *wallet) IsMultisig() (*IsMultisigResponse, error) {
+	return w.IsMultisigContext(context.Background())
+}
+
+func (w *wallet) IsMultisigContext(ctx context.Context) (*IsMultisigResponse, error) {
 	res := new(IsMultisigResponse)
-	err := w.client.Do("is_multisig", nil, res)
+	err := w.do(ctx, "is_multisig", nil, res)
 	return res, err
 }
 
 func (w *wallet) PrepareMultisig() (*PrepareMultisigResponse, error) {
+	return w.PrepareMultisigContext(context.Background())
+}
+
+func (w *wallet) PrepareMultisigContext(ctx context.Context) (*PrepareMultisigResponse, error) {
 	res := new(PrepareMultisigResponse)
-	err := w.client.Do("prepare_multisig", nil, res)
+	err := w.do(ctx, "prepare_multisig", nil, res)
 	return res, err
 }
 
 func (w *wallet) MakeMultisig(req *MakeMultisigRequest) (*MakeMultisigResponse, error) {
+	return w.MakeMultisigContext(context.Background(), req)
+}
+
+func (w *wallet) MakeMultisigContext(ctx context.Context, req *MakeMultisigRequest) (*MakeMultisigResponse, error) {
 	res := new(MakeMultisigResponse)
-	err := w.client.Do("make_multisig", req, res)
+	err := w.do(ctx, "make_multisig", req, res)
 	return res, err
 }
 
 func (w *wallet) ExportMultisigInfo() (*ExportMultisigInfoResponse, error) {
+	return w.ExportMultisigInfoContext(context.Background())
+}
+
+func (w *wallet) ExportMultisigInfoContext(ctx context.Context) (*ExportMultisigInfoResponse, error) {
 	res := new(ExportMultisigInfoResponse)
-	err := w.client.Do("export_multisig_info", nil, res)
+	err := w.do(ctx, "export_multisig_info", nil, res)
 	return res, err
 }
 
 func (w *wallet) ImportMultisigInfo(req *ImportMultisigInfoRequest) (*ImportMultisigInfoResponse, error) {
+	return w.ImportMultisigInfoContext(context.Background(), req)
+}
+
+func (w *wallet) ImportMultisigInfoContext(ctx context.Context, req *ImportMultisigInfoRequest) (*ImportMultisigInfoResponse, error) {
 	res := new(ImportMultisigInfoResponse)
-	err := w.client.Do("import_multisig_info", req, res)
+	err := w.do(ctx, "import_multisig_info", req, res)
 	return res, err
 }
 
 func (w *wallet) FinalizeMultisig(req *FinalizeMultisigRequest) (*FinalizeMultisigResponse, error) {
+	return w.FinalizeMultisigContext(context.Background(), req)
+}
+
+func (w *wallet) FinalizeMultisigContext(ctx context.Context, req *FinalizeMultisigRequest) (*FinalizeMultisigResponse, error) {
 	res := new(FinalizeMultisigResponse)
-	err := w.client.Do("finalize_multisig", req, res)
+	err := w.do(ctx, "finalize_multisig", req, res)
 	return res, err
 }
 
 func (w *wallet) SignMultisig(req *SignMultisigRequest) (*SignMultisigResponse, error) {
+	return w.SignMultisigContext(context.Background(), req)
+}
+
+func (w *wallet) SignMultisigContext(ctx context.Context, req *SignMultisigRequest) (*SignMultisigResponse, error) {
 	res := new(SignMultisigResponse)
-	err := w.client.Do("sign_multisig", req, res)
+	err := w.do(ctx, "sign_multisig", req, res)
 	return res, err
 }
 
 func (w *wallet) SubmitMultisig(req *SubmitMultisigRequest) (*SubmitMultisigResponse, error) {
+	return w.SubmitMultisigContext(context.Background(), req)
+}
+
+func (w *wallet) SubmitMultisigContext(ctx context.Context, req *SubmitMultisigRequest) (*SubmitMultisigResponse, error) {
 	res := new(SubmitMultisigResponse)
-	err := w.client.Do("submit_multisig", req, res)
+	err := w.do(ctx, "submit_multisig", req, res)
 	return res, err
 }
 
 func (w *wallet) GetVersion() (*GetVersionResponse, error) {
+	return w.GetVersionContext(context.Background())
+}
+
+func (w *wallet) GetVersionContext(ctx context.Context) (*GetVersionResponse, error) {
 	res := new(GetVersionResponse)
-	err := w.client.Do("get_version", nil, res)
+	err := w.do(ctx, "get_version", nil, res)
 	return res, err
 }
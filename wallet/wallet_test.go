@@ -2,11 +2,14 @@ package wallet
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gorilla/rpc/v2/json2"
 	"github.com/matryer/is"
@@ -18,12 +21,16 @@ type MockMoneroRPC struct {
 }
 
 func (m *MockMoneroRPC) Do(method string, req interface{}, res interface{}) error {
+	return m.DoContext(context.Background(), method, req, res)
+}
+
+func (m *MockMoneroRPC) DoContext(ctx context.Context, method string, req interface{}, res interface{}) error {
 	buff, err := json2.EncodeClientRequest(method, req)
 	if err != nil {
 		return fmt.Errorf("error creating encoded request %v", err)
 	}
 
-	httpReq, err := http.NewRequest("POST", m.uri, bytes.NewReader(buff))
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", m.uri, bytes.NewReader(buff))
 	if err != nil {
 		return fmt.Errorf("error creating http request %v", err)
 	}
@@ -1924,3 +1931,18 @@ func TestWalletGetVersion(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestWalletGetBalanceContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		io.ReadAll(req.Body)
+		<-req.Context().Done()
+	}))
+	defer server.Close()
+
+	w := New(getClient(server.URL, server.Client()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := w.GetBalanceContext(ctx, &GetBalanceRequest{})
+	is.New(t).True(errors.Is(err, context.DeadlineExceeded))
+}